@@ -0,0 +1,20 @@
+
+// Invoked when the buff is first applied to the player.
+function onStart(actor, triggersLeft) {
+
+    // Rain puts out fires and quenches thirst
+    actor.CancelBuffWithFlag("cancel-on-water");
+    actor.CancelBuffWithFlag("thirsty");
+
+    if ( actor.HasBuffFlag("warmed")  ) {
+        actor.RemoveBuff(40)
+        return
+    }
+
+    SendUserMessage(actor.UserId(), 'You are <ansi fg="weather">soaked</ansi> through, and shiver in the cold.');
+}
+
+// Invoked when the buff has run its course.
+function onEnd(actor, triggersLeft) {
+    SendUserMessage(actor.UserId(), 'You finally dry off.');
+}
@@ -0,0 +1,12 @@
+
+// Invoked when the buff is first applied to the player.
+function onStart(actor, triggersLeft) {
+    SendUserMessage(actor.UserId(),     'You sit down to rest.');
+    SendRoomMessage(actor.GetRoomId(),  actor.GetCharacterName(true)+' sits down to rest.', actor.UserId());
+}
+
+// Invoked when the buff has run its course.
+function onEnd(actor, triggersLeft) {
+    SendUserMessage(actor.UserId(),     'You get back up.');
+    SendRoomMessage(actor.GetRoomId(),  actor.GetCharacterName(true)+' gets back up.', actor.UserId());
+}
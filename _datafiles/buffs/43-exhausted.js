@@ -0,0 +1,11 @@
+
+// Invoked when the buff is first applied to the player.
+function onStart(actor, triggersLeft) {
+    SendUserMessage(actor.UserId(),     'You\'re exhausted, and need a moment before you can move on.');
+    SendRoomMessage(actor.GetRoomId(),  actor.GetCharacterName(true)+' stops to catch their breath.', actor.UserId());
+}
+
+// Invoked when the buff has run its course.
+function onEnd(actor, triggersLeft) {
+    SendUserMessage(actor.UserId(),     'You catch your breath.');
+}
@@ -0,0 +1,41 @@
+
+var command = {
+    name: "roll",
+    aliases: ["dice"],
+    category: "fun",
+    help: 'The <ansi fg="command">roll</ansi> command rolls some dice for everyone in the room to see.\n\n' +
+          '<ansi fg="yellow">Usage: </ansi>\n\n' +
+          '  <ansi fg="command">roll</ansi>\n' +
+          '  Rolls a single six sided die.\n\n' +
+          '  <ansi fg="command">roll 2d20</ansi>\n' +
+          '  Rolls two twenty sided dice.',
+    adminOnly: false,
+    allowedWhenDowned: false,
+};
+
+const MAX_DICE = 10;
+const MAX_SIDES = 100;
+
+function onCommand(rest, user, room) {
+
+    var qty = 1;
+    var sides = 6;
+
+    if ( rest != "" ) {
+        var parts = rest.toLowerCase().split("d");
+        qty = parseInt(parts[0]);
+        sides = parseInt(parts[1]);
+
+        if ( parts.length != 2 || isNaN(qty) || isNaN(sides) || qty < 1 || sides < 2 || qty > MAX_DICE || sides > MAX_SIDES ) {
+            SendUserMessage(user.UserId(), 'Roll what? Try something like <ansi fg="command">roll 2d6</ansi>. (Up to '+MAX_DICE+' dice with '+MAX_SIDES+' sides)');
+            return true;
+        }
+    }
+
+    var total = UtilDiceRoll(qty, sides);
+
+    SendUserMessage(user.UserId(), 'You roll '+qty+'d'+sides+' and get <ansi fg="yellow">'+total+'</ansi>.');
+    SendRoomMessage(room.RoomId(), user.GetCharacterName(true)+' rolls '+qty+'d'+sides+' and gets <ansi fg="yellow">'+total+'</ansi>.', user.UserId());
+
+    return true;
+}
@@ -0,0 +1,26 @@
+// Run with: go run . -script-test=_datafiles/rooms/frostfang/36.test.js
+
+test("guards block the throne room without an invitation", function() {
+
+    var room = NewFixtureRoom("Throne Room Entrance");
+    AttachScript(room);
+
+    var user = NewFixtureUser("Tester", room);
+    var watcher = NewFixtureUser("Watcher", room);
+
+    Assert(FireRoomCommand("north", "", user), "north should be handled");
+
+    AssertContains(GetMessages(user), "You must be invited");
+    AssertContains(GetMessages(watcher), "tries to enter the throne room");
+});
+
+test("other directions are ignored", function() {
+
+    var room = NewFixtureRoom("Throne Room Entrance");
+    AttachScript(room);
+
+    var user = NewFixtureUser("Tester", room);
+
+    AssertEqual(FireRoomCommand("south", "", user), false);
+    AssertEqual(GetMessages(user).length, 0);
+});
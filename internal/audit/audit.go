@@ -0,0 +1,64 @@
+package audit
+
+import (
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/volte6/gomud/internal/configs"
+)
+
+var (
+	lock sync.Mutex
+)
+
+// One sensitive action taken by an admin
+type Entry struct {
+	Time     time.Time `json:"time"`
+	UserId   int       `json:"userId"`
+	Username string    `json:"username"`
+	Action   string    `json:"action"`           // What was done, such as "eval"
+	Target   string    `json:"target,omitempty"` // What it was done to, such as "room-1"
+	Details  string    `json:"details,omitempty"`
+	Result   string    `json:"result,omitempty"`
+}
+
+// Logs the entry and appends it to the audit file as a line of json.
+func Record(e Entry) {
+
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+
+	slog.Info("AUDIT", "userId", e.UserId, "username", e.Username, "action", e.Action, "target", e.Target, "details", e.Details)
+
+	line, err := json.Marshal(e)
+	if err != nil {
+		slog.Error("audit.Record()", "error", err)
+		return
+	}
+
+	filePath := string(configs.GetConfig().FileAdminAudit)
+
+	lock.Lock()
+	defer lock.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		slog.Error("audit.Record()", "error", err)
+		return
+	}
+
+	f, err := os.OpenFile(filePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		slog.Error("audit.Record()", "error", err)
+		return
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		slog.Error("audit.Record()", "error", err)
+	}
+}
@@ -61,6 +61,9 @@ const (
 	Warmed       Flag = `warmed`
 	Hydrated     Flag = `hydrated`
 	Thirsty      Flag = `thirsty`
+	Soaked       Flag = `soaked`
+	Swift        Flag = `swift`   // Moving costs half as many action points
+	Resting      Flag = `resting` // Action points recover faster
 
 	// Flags that reveal things
 	SeeHidden Flag = `see-hidden`
@@ -219,6 +219,36 @@ func (c *Character) CarryCapacity() int {
 	return 5 + int(math.Floor(float64(c.Stats.Strength.ValueAdj/3)))
 }
 
+// Carrying more than their capacity makes everything more tiring
+func (c *Character) IsEncumbered() bool {
+	return len(c.Items) > c.CarryCapacity()
+}
+
+// Returns the action points it costs to move through terrain with the given cost
+func (c *Character) MoveCost(terrainCost int) int {
+
+	cost := terrainCost
+
+	if c.IsEncumbered() {
+		cost *= 5
+	}
+
+	if c.HasBuffFlag(buffs.Swift) {
+		cost /= 2
+	}
+
+	if cost < 1 {
+		cost = 1
+	}
+
+	// Never more than they could ever have, so nowhere is impossible to enter
+	if c.ActionPointsMax.Value > 0 && cost > c.ActionPointsMax.Value {
+		cost = c.ActionPointsMax.Value
+	}
+
+	return cost
+}
+
 func (c *Character) DeductActionPoints(amount int) bool {
 
 	if c.ActionPoints < amount {
@@ -22,6 +22,10 @@ const (
 	PVPDisabled = `disabled`
 	PVPOff      = `off`
 	PVPLimited  = `limited`
+
+	SnoopNoticeNone      = `none`
+	SnoopNoticeAnonymous = `anonymous`
+	SnoopNoticeNamed     = `named`
 )
 
 type Config struct {
@@ -32,9 +36,17 @@ type Config struct {
 	FolderUserData               ConfigString      `yaml:"FolderUserData"`
 	FolderSpellData              ConfigString      `yaml:"FolderSpellData"`
 	FolderTemplates              ConfigString      `yaml:"FolderTemplates"`
+	FolderRecordings             ConfigString      `yaml:"FolderRecordings"`
+	FolderCrashReports           ConfigString      `yaml:"FolderCrashReports"`
+	FolderZoneExports            ConfigString      `yaml:"FolderZoneExports"`
 	FileAnsiAliases              ConfigString      `yaml:"FileAnsiAliases"`
 	FileColorPatterns            ConfigString      `yaml:"FileColorPatterns"`
 	FileKeywords                 ConfigString      `yaml:"FileKeywords"`
+	FileSchedules                ConfigString      `yaml:"FileSchedules"`
+	FileScheduleState            ConfigString      `yaml:"FileScheduleState"`
+	FileVehicles                 ConfigString      `yaml:"FileVehicles"`
+	FileWorldScript              ConfigString      `yaml:"FileWorldScript"`
+	FileAdminAudit               ConfigString      `yaml:"FileAdminAudit"`
 	AllowItemBuffRemoval         ConfigBool        `yaml:"AllowItemBuffRemoval"`
 	CarefulSaveFiles             ConfigBool        `yaml:"CarefulSaveFiles"`
 	AuctionsEnabled              ConfigBool        `yaml:"AuctionsEnabled"`
@@ -52,6 +64,10 @@ type Config struct {
 	MaxMobBoredom                ConfigInt         `yaml:"MaxMobBoredom"`
 	ScriptLoadTimeoutMs          ConfigInt         `yaml:"ScriptLoadTimeoutMs"`          // How long to spend the first time a script is loaded into memory
 	ScriptRoomTimeoutMs          ConfigInt         `yaml:"ScriptRoomTimeoutMs"`          // How many milliseconds to allow a script to run before it is interrupted
+	ScriptPermDataMaxBytes       ConfigInt         `yaml:"ScriptPermDataMaxBytes"`       // How much permanent data one script can save on a single item, character or spawn
+	ScriptMaxHeapGrowthMB        ConfigInt         `yaml:"ScriptMaxHeapGrowthMB"`        // If above zero, scripts are interrupted if memory use grows by this much while they run
+	ScriptEvalEnabled            ConfigBool        `yaml:"ScriptEvalEnabled"`            // If true, admins can run javascript inside live scripts with the eval and repl commands
+	ScriptWatchRounds            ConfigInt         `yaml:"ScriptWatchRounds"`            // How many rounds between checks for changed scripts. 0 disables it.
 	MaxTelnetConnections         ConfigInt         `yaml:"MaxTelnetConnections"`         // Maximum number of telnet connections to accept
 	TelnetPort                   ConfigSliceString `yaml:"TelnetPort"`                   // One or more Ports used to accept telnet connections
 	LocalPort                    ConfigInt         `yaml:"LocalPort"`                    // Port used for admin connections, localhost only
@@ -79,6 +95,7 @@ type Config struct {
 
 	MaxIdleSeconds     ConfigInt         `yaml:"MaxIdleSeconds"`     // How many seconds a player can go without a command in game before being kicked.
 	TimeoutMods        ConfigBool        `yaml:"TimeoutMods"`        // Whether to kick admin/mods when idle too long.
+	SnoopNotice        ConfigString      `yaml:"SnoopNotice"`        // Whether players are told when they are being snooped: none, anonymous, named
 	ZombieSeconds      ConfigInt         `yaml:"ZombieSeconds"`      // How many seconds a player will be a zombie allowing them to reconnect.
 	LogoutRounds       ConfigInt         `yaml:"LogoutRounds"`       // How many rounds of uninterrupted meditation must be completed to log out.
 	StartRoom          ConfigInt         `yaml:"StartRoom"`          // Default starting room.
@@ -98,6 +115,9 @@ type Config struct {
 
 	LeaderboardSize ConfigInt `yaml:"LeaderboardSize"` // Maximum size of leaderboard
 
+	RecordingMaxFileKB ConfigInt `yaml:"RecordingMaxFileKB"` // Size a session recording can reach before a new file is started
+	RecordingMaxFiles  ConfigInt `yaml:"RecordingMaxFiles"`  // How many recording files to keep per user before the oldest are deleted
+
 	SeedInt int64 `yaml:"-"`
 
 	RoundCount ConfigUInt64 `yaml:"RoundCount,omitempty"` // Last saved round count
@@ -372,6 +392,18 @@ func (c *Config) Validate() {
 		c.FolderTemplates = `_datafiles/templates` // default
 	}
 
+	if c.FolderRecordings == `` {
+		c.FolderRecordings = `_datafiles/recordings` // default
+	}
+
+	if c.FolderCrashReports == `` {
+		c.FolderCrashReports = `_datafiles/crashreports` // default
+	}
+
+	if c.FolderZoneExports == `` {
+		c.FolderZoneExports = `_datafiles/exports` // default
+	}
+
 	if c.FileAnsiAliases == `` {
 		c.FileAnsiAliases = `_datafiles/ansi-aliases.yaml` // default
 	}
@@ -384,6 +416,26 @@ func (c *Config) Validate() {
 		c.FileKeywords = `_datafiles/keywords.yaml` // default
 	}
 
+	if c.FileSchedules == `` {
+		c.FileSchedules = `_datafiles/schedules.yaml` // default
+	}
+
+	if c.FileScheduleState == `` {
+		c.FileScheduleState = `_datafiles/schedules-state.yaml` // default
+	}
+
+	if c.FileVehicles == `` {
+		c.FileVehicles = `_datafiles/vehicles.yaml` // default
+	}
+
+	if c.FileWorldScript == `` {
+		c.FileWorldScript = `_datafiles/world.js` // default
+	}
+
+	if c.FileAdminAudit == `` {
+		c.FileAdminAudit = `_datafiles/admin-audit.log` // default
+	}
+
 	if c.TimeFormat == `` {
 		c.TimeFormat = `Monday, 02-Jan-2006 03:04:05PM`
 	}
@@ -409,6 +461,11 @@ func (c *Config) Validate() {
 		}
 	}
 
+	// Validate SnoopNotice setting
+	if c.SnoopNotice != SnoopNoticeNone && c.SnoopNotice != SnoopNoticeAnonymous && c.SnoopNotice != SnoopNoticeNamed {
+		c.SnoopNotice = SnoopNoticeAnonymous
+	}
+
 	if int(c.PVPMinimumLevel) < 0 {
 		c.PVPMinimumLevel = 0
 	}
@@ -466,6 +523,12 @@ func (c *Config) Validate() {
 	if c.ZombieSeconds < 0 {
 		c.ZombieSeconds = 0 // default
 	}
+	if c.RecordingMaxFileKB < 1 {
+		c.RecordingMaxFileKB = 1024 // default
+	}
+	if c.RecordingMaxFiles < 1 {
+		c.RecordingMaxFiles = 10 // default
+	}
 	if c.LogoutRounds < 0 {
 		c.LogoutRounds = 3 // default
 	}
@@ -496,6 +559,18 @@ func (c *Config) Validate() {
 		c.ScriptRoomTimeoutMs = 10
 	}
 
+	if c.ScriptPermDataMaxBytes < 1 {
+		c.ScriptPermDataMaxBytes = 4096 // default
+	}
+
+	if c.ScriptMaxHeapGrowthMB < 0 {
+		c.ScriptMaxHeapGrowthMB = 0 // disabled
+	}
+
+	if c.ScriptWatchRounds < 0 {
+		c.ScriptWatchRounds = 0 // disabled
+	}
+
 	if c.MaxTelnetConnections < 1 {
 		c.MaxTelnetConnections = 50 // default
 	}
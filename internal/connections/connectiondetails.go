@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/gorilla/websocket"
+	"github.com/volte6/gomud/internal/recordings"
 )
 
 type ConnectState uint32
@@ -109,6 +110,9 @@ func (ci *ClientInput) Reset() {
 
 type InputHandler func(ci *ClientInput, handlerState map[string]any) (doNextHandler bool)
 
+// Prepended to every line of snooped output
+const snoopLinePrefix = "\033[0;35m%\033[0m "
+
 type ConnectionDetails struct {
 	connectionId      ConnectionId
 	state             ConnectState
@@ -121,6 +125,10 @@ type ConnectionDetails struct {
 	inputHandlers     []InputHandler
 	inputDisabled     bool
 	clientSettings    ClientSettings
+	recorderLock      sync.Mutex
+	recorder          *recordings.Recorder
+	snoopLock         sync.Mutex
+	snoopers          map[ConnectionId]*ConnectionDetails // Connections that receive a copy of all output
 }
 
 func (cd *ConnectionDetails) IsWebsocket() bool {
@@ -134,6 +142,10 @@ func (cd *ConnectionDetails) HandleInput(ci *ClientInput, handlerState map[strin
 
 	cd.lastInputTime = time.Now()
 
+	if rec := cd.Recorder(); rec != nil && len(ci.DataIn) > 0 {
+		rec.WriteInput(ci.DataIn)
+	}
+
 	handlerCt := len(cd.inputHandlers)
 	if handlerCt < 1 {
 		return false, lastHandler, errors.New("no input handlers")
@@ -187,6 +199,34 @@ func (cd *ConnectionDetails) Write(p []byte) (n int, err error) {
 
 	p = []byte(strings.ReplaceAll(string(p), "\n", "\r\n"))
 
+	if rec := cd.Recorder(); rec != nil {
+		rec.WriteOutput(p)
+	}
+
+	cd.snoopLock.Lock()
+	for _, snooper := range cd.snoopers {
+		snooper.writeSnooped(p)
+	}
+	cd.snoopLock.Unlock()
+
+	return cd.write(p)
+}
+
+// Writes a copy of another connections output, marking each line so it stands out.
+// Snooped output is never passed on to this connections own snoopers.
+func (cd *ConnectionDetails) writeSnooped(p []byte) {
+
+	p = []byte(strings.ReplaceAll(string(p), "\r\n", "\r\n"+snoopLinePrefix))
+
+	if rec := cd.Recorder(); rec != nil {
+		rec.WriteOutput(p)
+	}
+
+	cd.write(p)
+}
+
+func (cd *ConnectionDetails) write(p []byte) (n int, err error) {
+
 	if cd.wsConn != nil {
 		cd.wsLock.Lock()
 		defer cd.wsLock.Unlock()
@@ -201,6 +241,61 @@ func (cd *ConnectionDetails) Write(p []byte) (n int, err error) {
 	return cd.conn.Write(p)
 }
 
+// Returns the active session recorder, or nil if the connection isn't being recorded
+func (cd *ConnectionDetails) Recorder() *recordings.Recorder {
+	cd.recorderLock.Lock()
+	defer cd.recorderLock.Unlock()
+
+	return cd.recorder
+}
+
+// Replaces the session recorder, closing any previous one.
+// Pass nil to stop recording.
+func (cd *ConnectionDetails) SetRecorder(rec *recordings.Recorder) {
+	cd.recorderLock.Lock()
+	defer cd.recorderLock.Unlock()
+
+	if cd.recorder != nil {
+		cd.recorder.Close()
+	}
+	cd.recorder = rec
+}
+
+// Starts sending a copy of all output to another connection
+func (cd *ConnectionDetails) AddSnooper(snooper *ConnectionDetails) {
+	cd.snoopLock.Lock()
+	defer cd.snoopLock.Unlock()
+
+	if cd.snoopers == nil {
+		cd.snoopers = map[ConnectionId]*ConnectionDetails{}
+	}
+	cd.snoopers[snooper.ConnectionId()] = snooper
+}
+
+// Stops sending output to a snooping connection. Returns false if it wasn't snooping.
+func (cd *ConnectionDetails) RemoveSnooper(snooperId ConnectionId) bool {
+	cd.snoopLock.Lock()
+	defer cd.snoopLock.Unlock()
+
+	if _, ok := cd.snoopers[snooperId]; !ok {
+		return false
+	}
+	delete(cd.snoopers, snooperId)
+	return true
+}
+
+// Returns the connection id's of everyone snooping this connection
+func (cd *ConnectionDetails) Snoopers() []ConnectionId {
+	cd.snoopLock.Lock()
+	defer cd.snoopLock.Unlock()
+
+	ids := make([]ConnectionId, 0, len(cd.snoopers))
+	for id := range cd.snoopers {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
 func (cd *ConnectionDetails) Read(p []byte) (n int, err error) {
 
 	if cd.wsConn != nil {
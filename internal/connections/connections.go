@@ -8,6 +8,7 @@ import (
 	"sync"
 
 	"github.com/gorilla/websocket"
+	"github.com/volte6/gomud/internal/recordings"
 )
 
 const ReadBufferSize = 1024
@@ -131,6 +132,12 @@ func Remove(id ConnectionId) (err error) {
 
 		// close the connection, no longer useful.
 		cd.Close()
+		// stop any session recording
+		cd.SetRecorder(nil)
+		// stop snooping anyone else
+		for _, otherCd := range netConnections {
+			otherCd.RemoveSnooper(id)
+		}
 		// keep track of the number of disconnects
 		disconnectCounter++
 		// Remove the entry
@@ -211,6 +218,111 @@ func SendTo(b []byte, ids ...ConnectionId) {
 	}
 }
 
+// Starts recording the input and output of a connection
+func StartRecording(id ConnectionId, username string, startedBy string) (string, error) {
+	lock.Lock()
+	defer lock.Unlock()
+
+	cd, ok := netConnections[id]
+	if !ok {
+		return ``, errors.New("connection not found")
+	}
+
+	rec, err := recordings.NewRecorder(username, startedBy)
+	if err != nil {
+		return ``, err
+	}
+
+	cd.SetRecorder(rec)
+
+	slog.Info("session recording started", "connectionId", id, "username", username, "startedBy", startedBy, "file", rec.FileName())
+
+	return rec.FileName(), nil
+}
+
+// Stops recording a connection. Returns false if it wasn't being recorded.
+func StopRecording(id ConnectionId) bool {
+	lock.Lock()
+	defer lock.Unlock()
+
+	cd, ok := netConnections[id]
+	if !ok || cd.Recorder() == nil {
+		return false
+	}
+
+	cd.SetRecorder(nil)
+
+	slog.Info("session recording stopped", "connectionId", id)
+
+	return true
+}
+
+// Returns the active session recorder for a connection, or nil
+func GetRecorder(id ConnectionId) *recordings.Recorder {
+	lock.Lock()
+	defer lock.Unlock()
+
+	if cd, ok := netConnections[id]; ok {
+		return cd.Recorder()
+	}
+
+	return nil
+}
+
+// Sends a copy of all output for one connection to another
+func AddSnooper(targetId ConnectionId, snooperId ConnectionId) error {
+	lock.Lock()
+	defer lock.Unlock()
+
+	target, ok := netConnections[targetId]
+	if !ok {
+		return errors.New("connection not found")
+	}
+
+	snooper, ok := netConnections[snooperId]
+	if !ok {
+		return errors.New("connection not found")
+	}
+
+	if targetId == snooperId {
+		return errors.New("cannot snoop yourself")
+	}
+
+	target.AddSnooper(snooper)
+
+	slog.Info("snoop started", "connectionId", targetId, "snooperConnectionId", snooperId)
+
+	return nil
+}
+
+// Stops copying output from one connection to another.
+// Returns false if no snoop was in place.
+func RemoveSnooper(targetId ConnectionId, snooperId ConnectionId) bool {
+	lock.Lock()
+	defer lock.Unlock()
+
+	if target, ok := netConnections[targetId]; ok {
+		if target.RemoveSnooper(snooperId) {
+			slog.Info("snoop stopped", "connectionId", targetId, "snooperConnectionId", snooperId)
+			return true
+		}
+	}
+
+	return false
+}
+
+// Returns the connection id's of everyone snooping a connection
+func GetSnoopers(targetId ConnectionId) []ConnectionId {
+	lock.Lock()
+	defer lock.Unlock()
+
+	if target, ok := netConnections[targetId]; ok {
+		return target.Snoopers()
+	}
+
+	return nil
+}
+
 // make this more efficient later
 func ActiveConnectionCount() int {
 	lock.RLock()
@@ -0,0 +1,128 @@
+package crashreports
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/volte6/gomud/internal/configs"
+	"github.com/volte6/gomud/internal/users"
+)
+
+const (
+	// The same panic in the same place is only written/announced once in this window
+	duplicateWindow = time.Minute
+
+	fileTimeFormat = `20060102-150405.000`
+)
+
+var (
+	lock         sync.Mutex
+	lastReported = map[string]time.Time{}
+	suppressed   = map[string]int{}
+)
+
+// Everything known about a recovered panic
+type Report struct {
+	Time          time.Time
+	Where         string // What was running, such as "processInput" or "script:TryRoomCommand"
+	Error         string // The value passed to panic()
+	UserId        int
+	MobInstanceId int
+	RoomId        int
+	Input         string // The command or event being processed, if any
+	Stack         string
+}
+
+// Builds a report from the value returned by recover().
+// Should be called from the deferred function so the stack trace is still meaningful.
+func New(r any, where string) Report {
+	return Report{
+		Time:  time.Now(),
+		Where: where,
+		Error: fmt.Sprintf(`%v`, r),
+		Stack: string(debug.Stack()),
+	}
+}
+
+// Logs the report, writes it to the crash report folder and tells online admins about it.
+// Returns the path of the written file, or an empty string if it was a recent duplicate.
+func (r Report) Save() string {
+
+	slog.Error("PANIC RECOVERED", "where", r.Where, "error", r.Error, "userId", r.UserId, "mobInstanceId", r.MobInstanceId, "roomId", r.RoomId, "input", r.Input)
+
+	key := r.Where + `|` + r.Error
+
+	lock.Lock()
+	if last, ok := lastReported[key]; ok && r.Time.Sub(last) < duplicateWindow {
+		suppressed[key]++
+		lock.Unlock()
+		return ``
+	}
+	repeatCt := suppressed[key]
+	lastReported[key] = r.Time
+	delete(suppressed, key)
+	lock.Unlock()
+
+	folder := string(configs.GetConfig().FolderCrashReports)
+
+	fileName := fmt.Sprintf(`%s-%s.txt`, r.Time.Format(fileTimeFormat), sanitizeName(r.Where))
+	filePath := filepath.Join(folder, fileName)
+
+	if err := os.MkdirAll(folder, 0755); err != nil {
+		slog.Error("crashreports.Save()", "error", err)
+		filePath = ``
+	} else if err := os.WriteFile(filePath, []byte(r.String(repeatCt)), 0644); err != nil {
+		slog.Error("crashreports.Save()", "error", err)
+		filePath = ``
+	}
+
+	NotifyAdmins(fmt.Sprintf(`<ansi fg="red-bold">PANIC</ansi> in <ansi fg="yellow">%s</ansi>: <ansi fg="red">%s</ansi> (user:%d mob:%d room:%d) <ansi fg="black-bold">%s</ansi>`, r.Where, r.Error, r.UserId, r.MobInstanceId, r.RoomId, fileName))
+
+	return filePath
+}
+
+// Formats the report as it is written to disk.
+// repeatCt is how many identical panics were not reported since the last one.
+func (r Report) String(repeatCt int) string {
+
+	sb := strings.Builder{}
+
+	sb.WriteString(fmt.Sprintf("Time:          %s\n", r.Time.Format(time.RFC3339Nano)))
+	sb.WriteString(fmt.Sprintf("Where:         %s\n", r.Where))
+	sb.WriteString(fmt.Sprintf("Error:         %s\n", r.Error))
+	sb.WriteString(fmt.Sprintf("UserId:        %d\n", r.UserId))
+	sb.WriteString(fmt.Sprintf("MobInstanceId: %d\n", r.MobInstanceId))
+	sb.WriteString(fmt.Sprintf("RoomId:        %d\n", r.RoomId))
+	sb.WriteString(fmt.Sprintf("Input:         %q\n", r.Input))
+	if repeatCt > 0 {
+		sb.WriteString(fmt.Sprintf("Suppressed:    %d identical panic(s) since the last report\n", repeatCt))
+	}
+	sb.WriteString("\n")
+	sb.WriteString(r.Stack)
+
+	return sb.String()
+}
+
+// Sends a message to every admin that is currently online
+func NotifyAdmins(msg string) {
+	for _, u := range users.GetAllActiveUsers() {
+		if u.Permission == users.PermissionAdmin {
+			u.SendText(msg)
+		}
+	}
+}
+
+func sanitizeName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '-' || r == '_' {
+			return r
+		}
+		return '_'
+	}, name)
+}
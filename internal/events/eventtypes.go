@@ -5,6 +5,7 @@ type Buff struct {
 	UserId        int
 	MobInstanceId int
 	BuffId        int
+	WaitTurns     int
 }
 
 func (b Buff) Type() string { return `Buff` }
@@ -28,6 +29,17 @@ type RoomAction struct {
 
 func (r RoomAction) Type() string { return `RoomAction` }
 
+// Fired when a player moves from one room to another
+type RoomChange struct {
+	UserId     int
+	FromRoomId int
+	ToRoomId   int
+	FromZone   string
+	ToZone     string
+}
+
+func (r RoomChange) Type() string { return `RoomChange` }
+
 // Used for Input from players/mobs
 type Input struct {
 	UserId        int
@@ -0,0 +1,184 @@
+package events
+
+import (
+	"container/heap"
+	"sort"
+	"sync"
+
+	"github.com/volte6/gomud/internal/configs"
+)
+
+// A handle that can be used to cancel a scheduled event
+type ScheduleHandle uint64
+
+// Information about an event waiting on the scheduler
+type ScheduledEvent struct {
+	Handle  ScheduleHandle
+	DueTurn uint64
+	Event   Event
+}
+
+type scheduleItem struct {
+	ScheduledEvent
+	seq   uint64 // preserves insertion order for events due on the same turn
+	index int
+}
+
+// A min-heap of scheduled events ordered by the turn they are due
+type scheduleHeap []*scheduleItem
+
+func (h scheduleHeap) Len() int { return len(h) }
+
+func (h scheduleHeap) Less(i, j int) bool {
+	if h[i].DueTurn == h[j].DueTurn {
+		return h[i].seq < h[j].seq
+	}
+	return h[i].DueTurn < h[j].DueTurn
+}
+
+func (h scheduleHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *scheduleHeap) Push(x any) {
+	item := x.(*scheduleItem)
+	item.index = len(*h)
+	*h = append(*h, item)
+}
+
+func (h *scheduleHeap) Pop() any {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	old[n-1] = nil
+	item.index = -1
+	*h = old[:n-1]
+	return item
+}
+
+var (
+	sLock       = sync.Mutex{}
+	schedule    = scheduleHeap{}
+	scheduleMap = map[ScheduleHandle]*scheduleItem{}
+	lastHandle  ScheduleHandle
+	currentTurn uint64
+)
+
+// Schedules an event to be added to its queue on a specific turn.
+// If the turn has already passed, it will be released on the next call to ReleaseDue()
+func ScheduleAt(e Event, turn uint64) ScheduleHandle {
+	sLock.Lock()
+	defer sLock.Unlock()
+
+	lastHandle++
+
+	item := &scheduleItem{
+		ScheduledEvent: ScheduledEvent{
+			Handle:  lastHandle,
+			DueTurn: turn,
+			Event:   e,
+		},
+		seq: uint64(lastHandle),
+	}
+
+	heap.Push(&schedule, item)
+	scheduleMap[item.Handle] = item
+
+	return item.Handle
+}
+
+// Schedules an event to be added to its queue after a number of turns have passed
+func ScheduleTurns(e Event, turns int) ScheduleHandle {
+	if turns < 0 {
+		turns = 0
+	}
+
+	sLock.Lock()
+	dueTurn := currentTurn + uint64(turns)
+	sLock.Unlock()
+
+	return ScheduleAt(e, dueTurn)
+}
+
+// Schedules an event to be added to its queue after a number of rounds have passed
+func ScheduleRounds(e Event, rounds int) ScheduleHandle {
+	turnsPerRound := configs.GetConfig().TurnsPerRound()
+	if turnsPerRound < 1 {
+		turnsPerRound = 1
+	}
+	return ScheduleTurns(e, rounds*turnsPerRound)
+}
+
+// Removes a scheduled event before it is released.
+// Returns false if the event was not found (already released or cancelled)
+func Cancel(h ScheduleHandle) bool {
+	sLock.Lock()
+	defer sLock.Unlock()
+
+	item, ok := scheduleMap[h]
+	if !ok {
+		return false
+	}
+
+	heap.Remove(&schedule, item.index)
+	delete(scheduleMap, h)
+
+	return true
+}
+
+// Moves all events due on or before the turn provided into their queues.
+// Returns how many events were released.
+func ReleaseDue(turn uint64) int {
+
+	due := []Event{}
+
+	sLock.Lock()
+	currentTurn = turn
+	for len(schedule) > 0 && schedule[0].DueTurn <= turn {
+		item := heap.Pop(&schedule).(*scheduleItem)
+		delete(scheduleMap, item.Handle)
+		due = append(due, item.Event)
+	}
+	sLock.Unlock()
+
+	// Queue outside of the scheduler lock so that nothing is held while queueing
+	for _, e := range due {
+		AddToQueue(e)
+	}
+
+	return len(due)
+}
+
+// Returns the turn the scheduler was last advanced to
+func CurrentTurn() uint64 {
+	sLock.Lock()
+	defer sLock.Unlock()
+
+	return currentTurn
+}
+
+// Returns a copy of all pending scheduled events, soonest first.
+// Intended for debugging.
+func GetScheduled() []ScheduledEvent {
+	sLock.Lock()
+	defer sLock.Unlock()
+
+	items := make([]*scheduleItem, len(schedule))
+	copy(items, schedule)
+
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].DueTurn == items[j].DueTurn {
+			return items[i].seq < items[j].seq
+		}
+		return items[i].DueTurn < items[j].DueTurn
+	})
+
+	result := make([]ScheduledEvent, len(items))
+	for i, item := range items {
+		result[i] = item.ScheduledEvent
+	}
+
+	return result
+}
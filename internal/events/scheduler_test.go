@@ -0,0 +1,73 @@
+package events
+
+import (
+	"testing"
+)
+
+func resetScheduler() {
+	sLock.Lock()
+	schedule = scheduleHeap{}
+	scheduleMap = map[ScheduleHandle]*scheduleItem{}
+	currentTurn = 0
+	sLock.Unlock()
+
+	qLock.Lock()
+	allQueues = map[string]*Queue{}
+	requeues = map[string][]Event{}
+	qLock.Unlock()
+}
+
+func TestScheduler_ReleaseDue(t *testing.T) {
+	resetScheduler()
+
+	ScheduleTurns(Input{UserId: 1, InputText: `third`}, 5)
+	ScheduleTurns(Input{UserId: 1, InputText: `first`}, 2)
+	ScheduleTurns(Input{UserId: 1, InputText: `second`}, 2)
+
+	if ct := ReleaseDue(1); ct != 0 {
+		t.Fatalf("ReleaseDue(1) released %d events, expected 0", ct)
+	}
+
+	if ct := ReleaseDue(2); ct != 2 {
+		t.Fatalf("ReleaseDue(2) released %d events, expected 2", ct)
+	}
+
+	q := GetQueue(Input{})
+	for _, expected := range []string{`first`, `second`} {
+		input := q.Poll().(Input)
+		if input.InputText != expected {
+			t.Errorf("got %q, expected %q", input.InputText, expected)
+		}
+	}
+
+	if pending := GetScheduled(); len(pending) != 1 || pending[0].DueTurn != 5 {
+		t.Errorf("unexpected pending events: %+v", pending)
+	}
+}
+
+func TestScheduler_Cancel(t *testing.T) {
+	resetScheduler()
+
+	h1 := ScheduleAt(Input{UserId: 1, InputText: `keep`}, 3)
+	h2 := ScheduleAt(Input{UserId: 1, InputText: `cancel`}, 3)
+
+	if !Cancel(h2) {
+		t.Fatalf("Cancel() returned false for a pending event")
+	}
+
+	if Cancel(h2) {
+		t.Errorf("Cancel() returned true for an already cancelled event")
+	}
+
+	if ct := ReleaseDue(10); ct != 1 {
+		t.Fatalf("ReleaseDue(10) released %d events, expected 1", ct)
+	}
+
+	if Cancel(h1) {
+		t.Errorf("Cancel() returned true for an already released event")
+	}
+
+	if input := GetQueue(Input{}).Poll().(Input); input.InputText != `keep` {
+		t.Errorf("got %q, expected %q", input.InputText, `keep`)
+	}
+}
@@ -1,6 +1,10 @@
 package exit
 
-import "github.com/volte6/gomud/internal/gamelock"
+import (
+	"fmt"
+
+	"github.com/volte6/gomud/internal/gamelock"
+)
 
 // There is a magic portal of Chuckles, magic portal of Henry here!
 // There is a magical hole in the east wall here!
@@ -17,8 +21,35 @@ type RoomExit struct {
 	Secret       bool          `yaml:"secret,omitempty"`
 	MapDirection string        `yaml:"mapdirection,omitempty"` // Optionaly indicate the direction of this exit for mapping purposes
 	Lock         gamelock.Lock `yaml:"lock,omitempty"`         // 0 - no lock. greater than zero = difficulty to unlock.
+	Instanced    bool          `yaml:"instanced,omitempty"`    // Players going this way enter their own copy of the zone it leads to
+	Door         Door          `yaml:"door,omitempty"`         // If it has a name, there is a door across the exit
+}
+
+// A door across an exit. Doors start out closed, and can't be opened while locked.
+// If the exit back from the other room also has a door, they are the same door and open, close and lock together.
+type Door struct {
+	Name string `yaml:"name,omitempty"` // What it is called, such as "door" or "iron gate"
+	Key  string `yaml:"key,omitempty"`  // If set, the lock id keys need to open the lock from either side. Defaults to "<roomid>-<exitname>".
+	Open bool   `yaml:"-"`              // Whether it is open right now
 }
 
 func (re RoomExit) HasLock() bool {
 	return re.Lock.Difficulty > 0
 }
+
+func (re RoomExit) HasDoor() bool {
+	return re.Door.Name != ``
+}
+
+// Whether a door or lock is in the way
+func (re RoomExit) IsClosed() bool {
+	return (re.HasDoor() && !re.Door.Open) || re.Lock.IsLocked()
+}
+
+// The id of the lock that keys and lockpicking sequences are remembered for
+func (re RoomExit) LockId(roomId int, exitName string) string {
+	if re.Door.Key != `` {
+		return re.Door.Key
+	}
+	return fmt.Sprintf(`%d-%s`, roomId, exitName)
+}
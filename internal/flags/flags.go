@@ -10,10 +10,15 @@ import (
 	"strings"
 )
 
+var (
+	scriptTestPath string
+)
+
 func HandleFlags() {
 	var portsearch string
 
 	flag.StringVar(&portsearch, "port-search", "", "Search for the first 10 open ports: -port-search=30000-40000")
+	flag.StringVar(&scriptTestPath, "script-test", "", "Run script tests (*.test.js) in a file or folder, then exit: -script-test=_datafiles/rooms/frostfang")
 
 	flag.Parse()
 
@@ -23,6 +28,12 @@ func HandleFlags() {
 	}
 }
 
+// The path given to -script-test, if any.
+// Script tests need the data files loaded, so are run later on in startup.
+func ScriptTestPath() string {
+	return scriptTestPath
+}
+
 func doPortSearch(portRangeStr string) {
 	portRange := strings.Split(portRangeStr, `-`)
 
@@ -312,7 +312,7 @@ func (g GameDate) AddPeriod(str string) uint64 {
 
 	}
 
-	if len(timeStr) >= 2 {
+	if len(timeStr) >= 3 {
 
 		strShort := timeStr[0:3]
 
@@ -446,6 +446,82 @@ func (g GameDate) AddPeriod(str string) uint64 {
 
 }
 
+// Returns an error if a period string isn't something AddPeriod understands.
+// Periods such as "noon" or "sunset" land on a time of day rather than adding a fixed length of time,
+// so they are only accepted when allowRelative is true.
+func ValidatePeriod(str string, allowRelative bool) error {
+
+	timeStr := ``
+	realTime := false
+
+	parts := strings.Split(strings.ToLower(strings.TrimSpace(str)), ` `)
+	if len(parts) == 1 { // e.g. 2
+
+		if parts[0] == `` {
+			return fmt.Errorf("period is empty")
+		}
+
+		if qty, err := strconv.Atoi(parts[0]); err == nil {
+			if qty < 1 {
+				return fmt.Errorf("period %q must be at least 1", str)
+			}
+			return nil
+		}
+		timeStr = parts[0]
+
+	} else {
+
+		if len(parts) > 3 {
+			return fmt.Errorf("period %q has too many words", str)
+		}
+
+		if qty, err := strconv.Atoi(parts[0]); err != nil || qty < 1 {
+			return fmt.Errorf("period %q must start with a quantity of at least 1", str)
+		}
+
+		if len(parts) == 2 { // e.g. - 2 days
+			timeStr = parts[1]
+		} else if parts[1] == `real` || parts[1] == `irl` { // e.g. - 2 irl days
+			realTime = true
+			timeStr = parts[2]
+		} else if parts[1] == `game` || parts[1] == `gametime` { // e.g. - 2 game days
+			timeStr = parts[2]
+		} else if parts[2] == `real` || parts[2] == `irl` { // e.g. - 2 days irl
+			realTime = true
+			timeStr = parts[1]
+		} else if parts[2] == `game` || parts[2] == `gametime` { // e.g. - 2 days gametime
+			timeStr = parts[1]
+		} else {
+			return fmt.Errorf("period %q should be like \"2 days\", \"2 real days\" or \"2 days irl\"", str)
+		}
+	}
+
+	if timeStr == `round` || timeStr == `rounds` {
+		return nil
+	}
+
+	if len(timeStr) >= 3 {
+		switch timeStr[0:3] {
+		case `yea`, `mon`, `wee`, `day`, `dai`, `hou`, `min`:
+			return nil
+		}
+	}
+
+	if strings.HasPrefix(timeStr, `noo`) || strings.HasPrefix(timeStr, `mid`) ||
+		timeStr == `sunrise` || timeStr == `sunrises` || timeStr == `sunset` || timeStr == `sunsets` {
+
+		if !allowRelative {
+			return fmt.Errorf("period %q is a time of day, not a length of time", str)
+		}
+		if realTime {
+			return fmt.Errorf("period %q can't be in real time", str)
+		}
+		return nil
+	}
+
+	return fmt.Errorf("period %q has an unknown unit: %s", str, timeStr)
+}
+
 func GetLastPeriod(periodName string, roundNumber uint64) uint64 {
 
 	c := configs.GetConfig()
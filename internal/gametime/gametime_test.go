@@ -20,3 +20,38 @@ func Benchmark_GetDate_Cached(b *testing.B) {
 		GetDate()
 	}
 }
+
+func TestValidatePeriod(t *testing.T) {
+
+	tests := []struct {
+		period        string
+		allowRelative bool
+		wantErr       bool
+	}{
+		{`5`, false, false},
+		{`10 rounds`, false, false},
+		{`1 day`, false, false},
+		{`daily`, false, false},
+		{`30 real minutes`, false, false},
+		{`2 hours irl`, false, false},
+		{`3 game weeks`, false, false},
+		{`1 noon`, true, false},
+		{`sunset`, true, false},
+		{`1 noon`, false, true},
+		{`1 real sunrise`, true, true},
+		{``, false, true},
+		{`0`, false, true},
+		{`5 xx`, false, true},
+		{`5 fortnights`, false, true},
+		{`x days`, false, true},
+		{`1 long day`, false, true},
+		{`1 real game day`, false, true},
+		{`1 2 3 4`, false, true},
+	}
+
+	for _, tt := range tests {
+		if err := ValidatePeriod(tt.period, tt.allowRelative); (err != nil) != tt.wantErr {
+			t.Errorf("ValidatePeriod(%q, %v) error = %v, wantErr %v", tt.period, tt.allowRelative, err, tt.wantErr)
+		}
+	}
+}
@@ -15,9 +15,27 @@ var (
 		`Keldris`,
 		`Luneth`,
 	}
+
+	seasonNames = []string{
+		`winter`,
+		`spring`,
+		`summer`,
+		`autumn`,
+	}
 )
 
+// How many months are in a game year
+func MonthsPerYear() int {
+	return len(monthNames)
+}
+
 func MonthName(month int) string {
 	month--
 	return monthNames[month%len(monthNames)]
 }
+
+// Returns winter, spring, summer or autumn.
+// The last month and first two months of the year are winter.
+func SeasonName(month int) string {
+	return seasonNames[(month%len(monthNames))/3]
+}
@@ -0,0 +1,88 @@
+package gametime
+
+import "strings"
+
+const (
+	MoonPhaseNew = iota
+	MoonPhaseWaxingCrescent
+	MoonPhaseFirstQuarter
+	MoonPhaseWaxingGibbous
+	MoonPhaseFull
+	MoonPhaseWaningGibbous
+	MoonPhaseThirdQuarter
+	MoonPhaseWaningCrescent
+)
+
+type moonInfo struct {
+	Name      string
+	CycleDays int // How many days from new moon to new moon
+	DayOffset int // Shifts the cycle so that all moons don't line up
+}
+
+var (
+	// The moon that is used when no moon name is specified
+	DefaultMoon = `arcanix`
+
+	// Names match the folders in _datafiles/templates/moons
+	moons = []moonInfo{
+		{Name: `arcanix`, CycleDays: 28, DayOffset: 0},
+		{Name: `noctherion`, CycleDays: 36, DayOffset: 11},
+		{Name: `terrosel`, CycleDays: 21, DayOffset: 5},
+	}
+
+	moonPhaseNames = []string{
+		`new moon`,
+		`waxing crescent`,
+		`first quarter`,
+		`waxing gibbous`,
+		`full moon`,
+		`waning gibbous`,
+		`third quarter`,
+		`waning crescent`,
+	}
+)
+
+// Returns the names of all moons
+func GetMoonNames() []string {
+	names := make([]string, len(moons))
+	for i, m := range moons {
+		names[i] = m.Name
+	}
+	return names
+}
+
+// Returns the total number of days that have passed since day 1 of year 1
+func (g GameDate) AbsoluteDay() int {
+	return (g.Year-1)*365 + g.Day
+}
+
+// Returns the phase (0-7) of a moon for the given date.
+// 0 is a new moon, 4 is a full moon.
+// Returns -1 if the moon name is not recognized.
+func GetMoonPhase(moonName string, gd GameDate) int {
+
+	if moonName == `` {
+		moonName = DefaultMoon
+	}
+	moonName = strings.ToLower(moonName)
+
+	for _, m := range moons {
+		if m.Name != moonName {
+			continue
+		}
+
+		dayOfCycle := (gd.AbsoluteDay() + m.DayOffset) % m.CycleDays
+
+		return (dayOfCycle * len(moonPhaseNames)) / m.CycleDays
+	}
+
+	return -1
+}
+
+// Returns a friendly name for a moon phase, such as "full moon"
+func MoonPhaseName(phase int) string {
+	if phase < 0 || phase >= len(moonPhaseNames) {
+		return `unknown`
+	}
+	return moonPhaseNames[phase]
+}
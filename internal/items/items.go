@@ -47,6 +47,7 @@ type Item struct {
 	Enchantments  uint8          `yaml:"enchantments,omitempty"` // Is this item enchanted?
 	Adjectives    []string       `yaml:"adjectives,omitempty"`   // Decorative text for the name of the item (e.g. "exploding")
 	StashedBy     int            `yaml:"stashedby,omitempty"`    // userid of whoever stashed this item
+	PermData      map[string]any `yaml:"permdata,omitempty"`     // Data saved by scripts. Keys are prefixed with the script namespace.
 	tempDataStore map[string]any // Temporary data store for this item. Not saved to disk.
 }
 
@@ -150,6 +151,40 @@ func (i Item) IsDisabled() bool {
 	return i.ItemId < 0
 }
 
+func (i *Item) SetPermData(key string, value any) {
+
+	if i.PermData == nil {
+		i.PermData = make(map[string]any)
+	}
+
+	if value == nil {
+		delete(i.PermData, key)
+		return
+	}
+	i.PermData[key] = value
+}
+
+func (i *Item) GetPermData(key string) any {
+
+	if i.PermData == nil {
+		return nil
+	}
+
+	if value, ok := i.PermData[key]; ok {
+		return value
+	}
+	return nil
+}
+
+func (i *Item) GetPermDataKeys() []string {
+
+	allKeys := []string{}
+	for key := range i.PermData {
+		allKeys = append(allKeys, key)
+	}
+	return allKeys
+}
+
 func (i *Item) UniqueId() uint64 {
 
 	if i.uid == 0 {
@@ -209,6 +244,11 @@ func (i *Item) GetLongDescription() string {
 		longDesc.WriteString("\n")
 		longDesc.WriteString(` - When you find the right door, keys are added to your <ansi fg="command">keyring</ansi> automatically.`)
 
+	} else if iSpec.Type == Furniture {
+
+		longDesc.WriteString("\n")
+		longDesc.WriteString(fmt.Sprintf(` - This can be placed in a house with <ansi fg="command">house furnish</ansi>, and holds %d things.`, iSpec.Capacity))
+
 	} else if iSpec.Subtype == Wearable {
 
 		longDesc.WriteString("\n")
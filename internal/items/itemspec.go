@@ -62,6 +62,7 @@ func ItemTypes() []ItemTypeInfo {
 		{string(Gemstone), `This is a gemstone.`, 0},
 		{string(Lockpicks), `This allows use of the picklock skill.`, 0},
 		{string(Botanical), `This is an herb.`, 0},
+		{string(Furniture), `This can be placed in a house to store things in.`, 0},
 	}
 }
 
@@ -118,6 +119,7 @@ const (
 	Gemstone  ItemType = "gemstone"  // A gem
 	Lockpicks ItemType = "lockpicks" // Used for lockpicking
 	Botanical ItemType = "botanical" // A plant, herb, etc.
+	Furniture ItemType = "furniture" // Can be placed in a house as a container
 
 	// Subtypes for wearables
 	Wearable  ItemSubType = "wearable"
@@ -215,6 +217,7 @@ type ItemSpec struct {
 	BreakChance     uint8             `yaml:"breakchance,omitempty"` // Chance in 100 that the item will break when used, or when the character is hit with it equipped, or if it is in the characters inventory during an explosion, etc.
 	Cursed          bool              `yaml:"cursed,omitempty"`      // Can't be removed once equipped
 	KeyLockId       string            `yaml:"keylockid,omitempty"`   // Example: `778-north` - If it's a key, what lock does it open? roomid-exitname etc.
+	Capacity        int               `yaml:"capacity,omitempty"`    // If it's furniture, how many items it holds once placed in a house
 }
 
 func (i Element) String() string {
@@ -35,7 +35,17 @@ func Go(rest string, mob *mobs.Mob, room *rooms.Room) (bool, error) {
 
 			mob.GoingHome = true
 
-			if len(mob.RoomStack) == 0 {
+			if path, found := rooms.FindPath(room.RoomId, mob.HomeRoomId, mobPathOptions()); found && len(path) > 0 {
+
+				exitName = path[0].ExitName
+				goRoomId = path[0].RoomId
+
+				// The way back is never longer than the path
+				if len(mob.RoomStack) > len(path)-1 {
+					mob.RoomStack = mob.RoomStack[:len(path)-1]
+				}
+
+			} else if len(mob.RoomStack) == 0 {
 
 				if util.Rand(50) == 0 {
 					goRoomId = mob.HomeRoomId
@@ -71,10 +81,24 @@ func Go(rest string, mob *mobs.Mob, room *rooms.Room) (bool, error) {
 			return true, nil
 		}
 
+		// Mobs never wander into instances, but charmed mobs can follow whoever charmed them into one
+		if exitInfo.Instanced {
+			instRoomId, ok := rooms.FindInstanceRoom(mob.Character.GetCharmedUserId(), goRoomId)
+			if !ok {
+				return true, nil
+			}
+			goRoomId = instRoomId
+		}
+
 	}
 
 	if exitName != `` {
 
+		// Mobs don't open doors
+		if exitInfo, ok := room.GetExitInfo(exitName); ok && exitInfo.HasDoor() && !exitInfo.Door.Open {
+			return true, nil
+		}
+
 		// Load current room details
 		destRoom := rooms.LoadRoom(goRoomId)
 		if destRoom == nil {
@@ -143,3 +167,11 @@ func Go(rest string, mob *mobs.Mob, room *rooms.Room) (bool, error) {
 
 	return false, nil
 }
+
+// Mobs stick to exits anyone can see and won't open or unlock doors.
+// Like mob movement, paths ignore items a biome requires, since mobs don't carry them.
+func mobPathOptions() rooms.PathOptions {
+	return rooms.PathOptions{
+		HasItem: func(itemId int) bool { return true },
+	}
+}
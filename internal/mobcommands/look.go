@@ -57,7 +57,7 @@ func Look(rest string, mob *mobs.Mob, room *rooms.Room) (bool, error) {
 	if exitName != `` {
 
 		exitInfo, _ := room.GetExitInfo(exitName)
-		if exitInfo.Lock.IsLocked() {
+		if exitInfo.IsClosed() {
 			return true, nil
 		}
 
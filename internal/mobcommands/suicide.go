@@ -60,6 +60,13 @@ func Suicide(rest string, mob *mobs.Mob, room *rooms.Room) (bool, error) {
 		fmt.Sprintf(`<ansi fg="mobname">%s</ansi> has died.`, mob.Character.Name),
 	)
 
+	// Let the zone and world scripts know
+	if sMob := scripting.GetMob(mob.InstanceId); sMob != nil {
+		sRoom := scripting.GetRoom(room.RoomId)
+		scripting.TryZoneScriptEvent(`onMobDeath`, room.Zone, sMob, sRoom)
+		scripting.TryWorldScriptEvent(`onMobDeath`, sMob, sRoom)
+	}
+
 	// Special handling of "The Guide"
 	// Mark this moment to prevent an immediate respawn
 	if mob.MobId == 38 {
@@ -56,7 +56,7 @@ func Wander(rest string, mob *mobs.Mob, room *rooms.Room) (bool, error) {
 
 	if exitName, roomId := room.GetRandomExit(); exitName != `` {
 		if r := rooms.LoadRoom(roomId); r != nil {
-			if !restrictZone || r.Zone == mob.Character.Zone {
+			if (!restrictZone || r.Zone == mob.Character.Zone) && withinWander(mob, roomId) {
 
 				mob.Command(fmt.Sprintf("go %s", exitName))
 
@@ -67,3 +67,18 @@ func Wander(rest string, mob *mobs.Mob, room *rooms.Room) (bool, error) {
 
 	return true, nil
 }
+
+// Whether a room is close enough to home for the mob to wander into
+func withinWander(mob *mobs.Mob, roomId int) bool {
+
+	if mob.MaxWander < 1 {
+		return true
+	}
+
+	opts := mobPathOptions()
+	opts.MaxSteps = mob.MaxWander
+
+	_, found := rooms.FindPath(mob.HomeRoomId, roomId, opts)
+
+	return found
+}
@@ -73,6 +73,7 @@ type Mob struct {
 	GoingHome       bool     `yaml:"-"`                         // WHether they are trying to get home
 	RoomStack       []int    `yaml:"-"`                         // Stack of rooms to get back home
 	PreventIdle     bool     `yaml:"-"`                         // Whether they can't possibly be idle
+	PossessedBy     int      `yaml:"-"`                         // UserId of an admin currently controlling this mob
 	ScriptTag       string   `yaml:"scripttag"`                 // Script for this mob: mobs/frostfang/scripts/{mobId}-{mobname}-{ScriptTag}.js
 	QuestFlags      []string `yaml:"questflags,omitempty,flow"` // What quest flags are set on this mob?
 	BuffIds         []int    `yaml:"buffids,omitempty"`         // Buff Id's this mob always has upon spawn
@@ -168,6 +168,15 @@ func (ml *MutatorList) Update(roundNow uint64) {
 	}
 }
 
+// Brings every mutator back as though it just spawned
+func (ml *MutatorList) Restore(roundNow uint64) {
+	for idx := range *ml {
+		(*ml)[idx].SpawnedRound = 0
+		(*ml)[idx].DespawnedRound = 0
+		(*ml)[idx].Update(roundNow)
+	}
+}
+
 // Returns a new list containing only active mutators
 func (ml *MutatorList) GetActive() MutatorList {
 	activeMuts := MutatorList{}
@@ -0,0 +1,354 @@
+package recordings
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/volte6/gomud/internal/configs"
+)
+
+// Recordings are written in the ttyrec format so that they can also be viewed
+// with standard tools such as ttyplay.
+// Every frame is a 12 byte header (seconds, microseconds, length as little
+// endian uint32's) followed by the data that was written.
+const (
+	OutputExtension = `.ttyrec`
+	InputExtension  = `.in.ttyrec`
+
+	frameHeaderSize = 12
+	maxFrameSize    = 1024 * 1024
+	fileTimeFormat  = `20060102-150405`
+)
+
+var (
+	ErrInvalidName = errors.New(`invalid recording name`)
+	ErrNotFound    = errors.New(`recording not found`)
+)
+
+type Frame struct {
+	Time time.Time
+	Data []byte
+}
+
+type RecordingInfo struct {
+	Name     string // Filename of the output recording
+	Path     string // Full path to the output recording
+	Size     int64
+	Modified time.Time
+}
+
+// Records the input and output of a single connection into two ttyrec files.
+// When the output file grows beyond the configured size a new pair of files
+// is started, and the oldest files are removed once there are too many.
+type Recorder struct {
+	lock      sync.Mutex
+	username  string
+	folder    string
+	maxBytes  int64
+	maxFiles  int
+	outFile   *os.File
+	inFile    *os.File
+	outBytes  int64
+	fileName  string
+	StartedBy string // Who started the recording (the user themselves or an admin)
+}
+
+// Starts a new recording for the username provided
+func NewRecorder(username string, startedBy string) (*Recorder, error) {
+
+	c := configs.GetConfig()
+
+	r := &Recorder{
+		username:  username,
+		folder:    Folder(username),
+		maxBytes:  int64(c.RecordingMaxFileKB) * 1024,
+		maxFiles:  int(c.RecordingMaxFiles),
+		StartedBy: startedBy,
+	}
+
+	if err := os.MkdirAll(r.folder, 0755); err != nil {
+		return nil, err
+	}
+
+	if err := r.rotate(); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// Returns the filename of the current output recording
+func (r *Recorder) FileName() string {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return r.fileName
+}
+
+// Records data that was sent to the user
+func (r *Recorder) WriteOutput(p []byte) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.outFile == nil {
+		return os.ErrClosed
+	}
+
+	if r.outBytes+int64(len(p)+frameHeaderSize) > r.maxBytes && r.outBytes > 0 {
+		if err := r.rotate(); err != nil {
+			return err
+		}
+	}
+
+	if err := WriteFrame(r.outFile, time.Now(), p); err != nil {
+		return err
+	}
+	r.outBytes += int64(len(p) + frameHeaderSize)
+
+	return nil
+}
+
+// Records data that was received from the user
+func (r *Recorder) WriteInput(p []byte) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.inFile == nil {
+		return os.ErrClosed
+	}
+
+	return WriteFrame(r.inFile, time.Now(), p)
+}
+
+func (r *Recorder) Close() {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.closeFiles()
+}
+
+func (r *Recorder) closeFiles() {
+	if r.outFile != nil {
+		r.outFile.Close()
+		r.outFile = nil
+	}
+	if r.inFile != nil {
+		r.inFile.Close()
+		r.inFile = nil
+	}
+}
+
+// Closes any open files, starts a new pair and prunes old recordings.
+// Expects the lock to be held (or the recorder not yet shared).
+func (r *Recorder) rotate() error {
+
+	r.closeFiles()
+
+	baseName := time.Now().Format(fileTimeFormat)
+	for i := 1; ; i++ {
+		if _, err := os.Stat(filepath.Join(r.folder, baseName+OutputExtension)); os.IsNotExist(err) {
+			break
+		}
+		baseName = fmt.Sprintf(`%s-%d`, time.Now().Format(fileTimeFormat), i)
+	}
+
+	outFile, err := os.Create(filepath.Join(r.folder, baseName+OutputExtension))
+	if err != nil {
+		return err
+	}
+
+	inFile, err := os.Create(filepath.Join(r.folder, baseName+InputExtension))
+	if err != nil {
+		outFile.Close()
+		return err
+	}
+
+	r.outFile = outFile
+	r.inFile = inFile
+	r.outBytes = 0
+	r.fileName = baseName + OutputExtension
+
+	prune(r.folder, r.maxFiles)
+
+	return nil
+}
+
+// Removes the oldest recordings in a folder until there are at most maxFiles left
+func prune(folder string, maxFiles int) {
+
+	files := listFolder(folder)
+	if len(files) <= maxFiles {
+		return
+	}
+
+	for _, info := range files[:len(files)-maxFiles] {
+		os.Remove(info.Path)
+		os.Remove(strings.TrimSuffix(info.Path, OutputExtension) + InputExtension)
+	}
+}
+
+// Returns the folder recordings for a user are stored in
+func Folder(username string) string {
+	return filepath.Join(string(configs.GetConfig().FolderRecordings), strings.ToLower(username))
+}
+
+// Returns all output recordings for a user, oldest first
+func List(username string) []RecordingInfo {
+	return listFolder(Folder(username))
+}
+
+// Returns the full path to the output recording for a user
+func GetPath(username string, name string) (string, error) {
+
+	if name == `` || name != filepath.Base(name) {
+		return ``, ErrInvalidName
+	}
+
+	if !strings.HasSuffix(name, OutputExtension) {
+		name += OutputExtension
+	}
+
+	path := filepath.Join(Folder(username), name)
+	if _, err := os.Stat(path); err != nil {
+		return ``, ErrNotFound
+	}
+
+	return path, nil
+}
+
+func listFolder(folder string) []RecordingInfo {
+
+	entries, err := os.ReadDir(folder)
+	if err != nil {
+		return nil
+	}
+
+	files := []RecordingInfo{}
+	for _, entry := range entries {
+
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, OutputExtension) || strings.HasSuffix(name, InputExtension) {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+
+		files = append(files, RecordingInfo{
+			Name:     name,
+			Path:     filepath.Join(folder, name),
+			Size:     info.Size(),
+			Modified: info.ModTime(),
+		})
+	}
+
+	// Filenames are timestamps, so sorting by name sorts by age
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Name < files[j].Name
+	})
+
+	return files
+}
+
+// Writes a single ttyrec frame
+func WriteFrame(w io.Writer, t time.Time, data []byte) error {
+
+	header := make([]byte, frameHeaderSize, frameHeaderSize+len(data))
+	binary.LittleEndian.PutUint32(header[0:4], uint32(t.Unix()))
+	binary.LittleEndian.PutUint32(header[4:8], uint32(t.Nanosecond()/1000))
+	binary.LittleEndian.PutUint32(header[8:12], uint32(len(data)))
+
+	_, err := w.Write(append(header, data...))
+	return err
+}
+
+// Reads a single ttyrec frame. Returns io.EOF when there are no more frames.
+func ReadFrame(rd io.Reader) (Frame, error) {
+
+	header := make([]byte, frameHeaderSize)
+	if _, err := io.ReadFull(rd, header); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			return Frame{}, io.EOF
+		}
+		return Frame{}, err
+	}
+
+	sec := binary.LittleEndian.Uint32(header[0:4])
+	usec := binary.LittleEndian.Uint32(header[4:8])
+	length := binary.LittleEndian.Uint32(header[8:12])
+
+	if length > maxFrameSize {
+		return Frame{}, fmt.Errorf(`frame too large: %d bytes`, length)
+	}
+
+	data := make([]byte, length)
+	if _, err := io.ReadFull(rd, data); err != nil {
+		return Frame{}, io.EOF
+	}
+
+	return Frame{Time: time.Unix(int64(sec), int64(usec)*1000), Data: data}, nil
+}
+
+// Plays a recording back by passing each frame to the output function.
+// The delay between frames is divided by speed, and capped at maxDelay so
+// long idle periods don't stall playback.
+// Playback ends early if the stop channel is closed.
+func Play(path string, speed float64, maxDelay time.Duration, output func([]byte), stop <-chan struct{}) error {
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if speed <= 0 {
+		speed = 1
+	}
+
+	var lastTime time.Time
+	for {
+
+		frame, err := ReadFrame(f)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if !lastTime.IsZero() {
+
+			delay := time.Duration(float64(frame.Time.Sub(lastTime)) / speed)
+			if delay > maxDelay {
+				delay = maxDelay
+			}
+
+			if delay > 0 {
+				select {
+				case <-stop:
+					return nil
+				case <-time.After(delay):
+				}
+			}
+		}
+		lastTime = frame.Time
+
+		select {
+		case <-stop:
+			return nil
+		default:
+		}
+
+		output(frame.Data)
+	}
+}
@@ -0,0 +1,42 @@
+package recordings
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestFrame_RoundTrip(t *testing.T) {
+
+	start := time.Unix(1700000000, 250000*1000)
+	frames := []Frame{
+		{Time: start, Data: []byte("Welcome!\r\n")},
+		{Time: start.Add(1500 * time.Millisecond), Data: []byte("\x1b[31mred\x1b[0m")},
+		{Time: start.Add(3 * time.Second), Data: []byte{}},
+	}
+
+	buf := bytes.Buffer{}
+	for _, f := range frames {
+		if err := WriteFrame(&buf, f.Time, f.Data); err != nil {
+			t.Fatalf("WriteFrame() returned error: %v", err)
+		}
+	}
+
+	for i, expected := range frames {
+		got, err := ReadFrame(&buf)
+		if err != nil {
+			t.Fatalf("ReadFrame() #%d returned error: %v", i, err)
+		}
+		if !got.Time.Equal(expected.Time) {
+			t.Errorf("ReadFrame() #%d time = %s, expected %s", i, got.Time, expected.Time)
+		}
+		if !bytes.Equal(got.Data, expected.Data) {
+			t.Errorf("ReadFrame() #%d data = %q, expected %q", i, got.Data, expected.Data)
+		}
+	}
+
+	if _, err := ReadFrame(&buf); err != io.EOF {
+		t.Errorf("ReadFrame() after last frame = %v, expected io.EOF", err)
+	}
+}
@@ -2,6 +2,13 @@ package rooms
 
 import "strings"
 
+const (
+	// Action points it costs to move into a room, unless the biome or room says otherwise
+	DefaultMoveCost = 10
+	// The most a room can set its move cost to
+	MaxMoveCost = 50
+)
+
 type BiomeInfo struct {
 	name           string
 	symbol         rune
@@ -11,6 +18,8 @@ type BiomeInfo struct {
 	requiredItemId int  // item id required to move into any room with this biome
 	usesItem       bool // Whether it "uses" the item (i.e. consumes it or decreases its uses left) when moving into a room with this biome
 	burns          bool // Does this area catch fire? (brush etc.)
+	sheltered      bool // Whether it is out of the weather
+	moveCost       int  // Action points it costs to move into a room with this biome
 }
 
 func (bi BiomeInfo) Name() string {
@@ -49,6 +58,17 @@ func (bi BiomeInfo) Burns() bool {
 	return bi.burns
 }
 
+func (bi BiomeInfo) IsSheltered() bool {
+	return bi.sheltered
+}
+
+func (bi BiomeInfo) MoveCost() int {
+	if bi.moveCost < 1 {
+		return DefaultMoveCost
+	}
+	return bi.moveCost
+}
+
 var (
 	AllBiomes = map[string]BiomeInfo{
 		`city`: {
@@ -67,6 +87,7 @@ var (
 			name:        `Road`,
 			symbol:      '•',
 			description: `Roads are well traveled paths, often extending out into the countryside.`,
+			moveCost:    8,
 		},
 		`house`: {
 			name:        `House`,
@@ -74,67 +95,80 @@ var (
 			litArea:     true,
 			description: `A standard dwelling, houses can appear almost anywhere. They are usually safe, but may be abandoned or occupied by hostile creatures.`,
 			burns:       true,
+			sheltered:   true,
 		},
 		`shore`: {
 			name:        `Shore`,
 			symbol:      '~',
 			description: `Shores are the transition between land and water. You can usually fish from them.`,
+			moveCost:    12,
 		},
 		`water`: {
 			name:           `Deep Water`,
 			symbol:         '≈',
 			description:    `Deep water is dangerous and usually requires some sort of assistance to cross.`,
 			requiredItemId: 20030,
+			moveCost:       20,
 		},
 		`forest`: {
 			name:        `Forest`,
 			symbol:      '♣',
 			description: `Forests are wild areas full of trees. Animals and monsters often live here.`,
 			burns:       true,
+			moveCost:    15,
 		},
 		`mountains`: {
 			name:        `Mountains`,
 			symbol:      '⩕', //'▲',
 			description: `Mountains are difficult to traverse, with roads that don't often follow a straight line.`,
+			moveCost:    25,
 		},
 		`cliffs`: {
 			name:        `Cliffs`,
 			symbol:      '▼',
 			description: `Cliffs are steep, rocky areas that are difficult to traverse. They can be climbed up or down with the right skills and equipment.`,
+			moveCost:    30,
 		},
 		`swamp`: {
 			name:        `Swamp`,
 			symbol:      '♨',
 			darkArea:    true,
 			description: `Swamps are wet, muddy areas that are difficult to traverse.`,
+			moveCost:    25,
 		},
 		`snow`: {
 			name:        `Snow`,
 			symbol:      '❄',
 			description: `Snow is cold and wet. It can be difficult to traverse, but is usually safe.`,
+			moveCost:    20,
 		},
 		`spiderweb`: {
 			name:        `Spiderweb`,
 			symbol:      '🕸',
 			darkArea:    true,
 			description: `Spiderwebs are usually found where larger spiders live. They are very dangerous areas.`,
+			moveCost:    30,
 		},
 		`cave`: {
 			name:        `Cave`,
 			symbol:      '⌬',
 			darkArea:    true,
 			description: `The land is covered in caves of all sorts. You never know what you'll find in them.`,
+			sheltered:   true,
+			moveCost:    12,
 		},
 		`desert`: {
 			name:        `Desert`,
 			symbol:      '*',
 			description: `The harsh desert is unforgiving and dry.`,
+			moveCost:    20,
 		},
 		`farmland`: {
 			name:        `Farmland`,
 			symbol:      ',',
 			description: `Wheat or other food is grown here.`,
 			burns:       true,
+			moveCost:    12,
 		},
 	}
 )
@@ -0,0 +1,38 @@
+package rooms
+
+import (
+	"testing"
+)
+
+func TestGetMoveCost(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		biome    string
+		moveCost int
+		want     int
+	}{
+		{`city uses the default`, `city`, 0, DefaultMoveCost},
+		{`mountains cost more`, `mountains`, 0, AllBiomes[`mountains`].moveCost},
+		{`unknown biome uses the default`, `nowhere-biome`, 0, DefaultMoveCost},
+		{`room overrides the biome`, `mountains`, 40, 40},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Room{Biome: tt.biome, MoveCost: tt.moveCost}
+			if got := r.GetMoveCost(); got != tt.want {
+				t.Errorf("GetMoveCost() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+
+	if AllBiomes[`mountains`].MoveCost() <= AllBiomes[`city`].MoveCost() {
+		t.Error("mountains should cost more to move into than a city")
+	}
+
+	r := &Room{Title: `Cliff`, Description: `A sheer cliff.`, MoveCost: MaxMoveCost + 1}
+	if err := r.Validate(); err == nil {
+		t.Errorf("Validate() expected an error for a move cost over %d", MaxMoveCost)
+	}
+}
@@ -10,6 +10,7 @@ type Container struct {
 	Items        []items.Item  `yaml:"items,omitempty"`        // Save contents now, since players can put new items in there
 	Gold         int           `yaml:"gold,omitempty"`         // Save contents now, since players can put new items in there
 	DespawnRound uint64        `yaml:"despawnround,omitempty"` // If this is set, it's a chest that will disappear with time.
+	Capacity     int           `yaml:"capacity,omitempty"`     // How many items it holds. If zero, extra items fall out once it has more than 10.
 }
 
 func (c Container) HasLock() bool {
@@ -0,0 +1,134 @@
+package rooms
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/volte6/gomud/internal/exit"
+)
+
+// Finds an exit with a door, by the exit name or the name of the door, such as "north" or "gate"
+func (r *Room) FindDoor(name string) (exitName string, exitInfo exit.RoomExit, found bool) {
+
+	if exitName, _ = r.FindExitByName(name); exitName != `` {
+		if exitInfo, ok := r.GetExitInfo(exitName); ok && exitInfo.HasDoor() {
+			return exitName, exitInfo, true
+		}
+	}
+
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == `` {
+		return ``, exit.RoomExit{}, false
+	}
+
+	exitNames := make([]string, 0, len(r.Exits))
+	for exitName := range r.Exits {
+		exitNames = append(exitNames, exitName)
+	}
+	sort.Strings(exitNames)
+
+	// Exact door names first, then partial
+	for _, exactMatch := range []bool{true, false} {
+		for _, exitName := range exitNames {
+			exitInfo := r.Exits[exitName]
+			if !exitInfo.HasDoor() {
+				continue
+			}
+			doorName := strings.ToLower(exitInfo.Door.Name)
+			if doorName == name || (!exactMatch && strings.Contains(doorName, name)) {
+				return exitName, exitInfo, true
+			}
+		}
+	}
+
+	return ``, exit.RoomExit{}, false
+}
+
+// Returns the room and exit on the other side of a door, if the exit back has a door too
+func (r *Room) GetDoorPair(exitName string) (*Room, string) {
+
+	exitInfo, ok := r.Exits[exitName]
+	if !ok || !exitInfo.HasDoor() || exitInfo.RoomId == r.RoomId {
+		return nil, ``
+	}
+
+	otherRoom := LoadRoom(exitInfo.RoomId)
+	if otherRoom == nil {
+		return nil, ``
+	}
+
+	otherExitNames := make([]string, 0, len(otherRoom.Exits))
+	for otherExitName := range otherRoom.Exits {
+		otherExitNames = append(otherExitNames, otherExitName)
+	}
+	sort.Strings(otherExitNames)
+
+	for _, otherExitName := range otherExitNames {
+		otherExit := otherRoom.Exits[otherExitName]
+		if otherExit.RoomId == r.RoomId && otherExit.HasDoor() {
+			return otherRoom, otherExitName
+		}
+	}
+
+	return nil, ``
+}
+
+// Opens or closes the door across an exit, along with the door on the other side.
+// Returns false if the exit has no door.
+func (r *Room) SetDoorOpen(exitName string, open bool) bool {
+
+	exitInfo, ok := r.Exits[exitName]
+	if !ok || !exitInfo.HasDoor() {
+
+		for mut := range r.ActiveMutators {
+			spec := mut.GetSpec()
+			if exitInfo, ok = spec.Exits[exitName]; ok && exitInfo.HasDoor() {
+				exitInfo.Door.Open = open
+				spec.Exits[exitName] = exitInfo
+				return true
+			}
+		}
+
+		return false
+	}
+
+	exitInfo.Door.Open = open
+	r.Exits[exitName] = exitInfo
+
+	if otherRoom, otherExitName := r.GetDoorPair(exitName); otherRoom != nil {
+		otherExit := otherRoom.Exits[otherExitName]
+		otherExit.Door.Open = open
+		otherRoom.Exits[otherExitName] = otherExit
+	}
+
+	return true
+}
+
+// Copies the state of doors from the other side, for rooms that were loaded after a door was opened or unlocked
+func (r *Room) syncDoors() {
+
+	for exitName, exitInfo := range r.Exits {
+
+		if !exitInfo.HasDoor() {
+			continue
+		}
+
+		otherRoom, ok := roomManager.rooms[exitInfo.RoomId]
+		if !ok {
+			continue
+		}
+
+		for _, otherExit := range otherRoom.Exits {
+			if otherExit.RoomId != r.RoomId || !otherExit.HasDoor() {
+				continue
+			}
+
+			exitInfo.Door.Open = otherExit.Door.Open
+			if exitInfo.HasLock() && otherExit.HasLock() {
+				exitInfo.Lock.UnlockedRound = otherExit.Lock.UnlockedRound
+			}
+			r.Exits[exitName] = exitInfo
+			break
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package rooms
+
+import (
+	"testing"
+
+	"github.com/volte6/gomud/internal/exit"
+	"github.com/volte6/gomud/internal/gamelock"
+	"github.com/volte6/gomud/internal/util"
+)
+
+func TestDoors(t *testing.T) {
+
+	zone := `Door Test`
+
+	// 1 -north (oak door)-> 2 -south (oak door)-> 1
+	r1 := newTestRoom(t, 900301, zone)
+	r2 := newTestRoom(t, 900302, zone)
+	defer ClearPathCache(zone)
+
+	r1.Exits[`north`] = exit.RoomExit{RoomId: r2.RoomId, Door: exit.Door{Name: `oak door`}, Lock: gamelock.Lock{Difficulty: 3}}
+	r2.Exits[`south`] = exit.RoomExit{RoomId: r1.RoomId, Door: exit.Door{Name: `oak door`}, Lock: gamelock.Lock{Difficulty: 3}}
+
+	ClearPathCache(zone)
+
+	roundNow := util.GetRoundCount()
+	util.SetRoundCount(10)
+	defer util.SetRoundCount(roundNow)
+
+	for _, name := range []string{`north`, `oak door`, `oak`} {
+		if exitName, _, found := r1.FindDoor(name); !found || exitName != `north` {
+			t.Errorf("FindDoor(%q) = %q, %v, expected north", name, exitName, found)
+		}
+	}
+
+	if otherRoom, otherExitName := r1.GetDoorPair(`north`); otherRoom != r2 || otherExitName != `south` {
+		t.Errorf("GetDoorPair() = %v, %q, expected room %d south", otherRoom, otherExitName, r2.RoomId)
+	}
+
+	r1.SetExitLock(`north`, false)
+	if r2.Exits[`south`].Lock.UnlockedRound != 10 {
+		t.Errorf("SetExitLock() didn't unlock the other side of the door")
+	}
+
+	canUnlock := func(string, gamelock.Lock) bool { return true }
+
+	if _, found := FindPath(r1.RoomId, r2.RoomId, PathOptions{CanUnlock: canUnlock}); found {
+		t.Errorf("FindPath() went through a closed door")
+	}
+
+	if !r1.SetDoorOpen(`north`, true) || !r2.Exits[`south`].Door.Open {
+		t.Errorf("SetDoorOpen() didn't open the other side of the door")
+	}
+
+	if _, found := FindPath(r1.RoomId, r2.RoomId, PathOptions{CanUnlock: canUnlock}); !found {
+		t.Errorf("FindPath() didn't go through an open door")
+	}
+
+	r2.SetDoorOpen(`south`, false)
+	if r1.Exits[`north`].Door.Open {
+		t.Errorf("SetDoorOpen() didn't close the other side of the door")
+	}
+
+	if _, found := FindPath(r1.RoomId, r2.RoomId, PathOptions{OpenDoors: true, CanUnlock: canUnlock}); !found {
+		t.Errorf("FindPath() with OpenDoors didn't go through a closed door")
+	}
+}
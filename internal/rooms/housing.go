@@ -0,0 +1,700 @@
+package rooms
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/volte6/gomud/internal/configs"
+	"github.com/volte6/gomud/internal/gametime"
+	"github.com/volte6/gomud/internal/items"
+	"github.com/volte6/gomud/internal/mobs"
+	"github.com/volte6/gomud/internal/users"
+	"github.com/volte6/gomud/internal/util"
+
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// House room ids are this plus the owner's user id, past the ids instances use
+	houseRoomIdStart = 1500000000
+	// How often rent is due if the room for sale doesn't say
+	houseDefaultRentPeriod = `1 month`
+	// How many pieces of furniture fit if the room for sale doesn't say
+	houseDefaultFurniture = 5
+	// How many rent payments can be missed before eviction if the room for sale doesn't say
+	houseDefaultEvictAfter = 3
+	// Who mail about houses comes from
+	houseMailFrom = `The Landlord`
+)
+
+var (
+	houses = map[int]*House{} // owner userId => house
+
+	errNoHouse = errors.New(`no such house`)
+)
+
+// Set on a room to let players buy their own copy of it as a house
+type Housing struct {
+	Price      int    `yaml:"price,omitempty"`      // Gold taken from the buyer's bank. If zero, the room isn't for sale.
+	Rent       int    `yaml:"rent,omitempty"`       // Gold taken from the owner's bank every rent period
+	RentPeriod string `yaml:"rentperiod,omitempty"` // How often rent is due, such as "1 week". Defaults to 1 month.
+	Furniture  int    `yaml:"furniture,omitempty"`  // How many pieces of furniture fit. Defaults to 5.
+	EvictAfter int    `yaml:"evictafter,omitempty"` // How many rent payments can be missed in a row before eviction. Defaults to 3.
+}
+
+func (h Housing) ForSale() bool {
+	return h.Price > 0
+}
+
+func (h Housing) GetRentPeriod() string {
+	if h.RentPeriod == `` {
+		return houseDefaultRentPeriod
+	}
+	return h.RentPeriod
+}
+
+func (h Housing) GetFurnitureLimit() int {
+	if h.Furniture < 1 {
+		return houseDefaultFurniture
+	}
+	return h.Furniture
+}
+
+func (h Housing) GetEvictAfter() int {
+	if h.EvictAfter < 1 {
+		return houseDefaultEvictAfter
+	}
+	return h.EvictAfter
+}
+
+// A house a player owns: their own copy of a room that was for sale.
+// Saved next to the owner's user file.
+type House struct {
+	OwnerUserId    int                       `yaml:"owneruserid"`
+	OwnerUsername  string                    `yaml:"ownerusername"`
+	OwnerName      string                    `yaml:"ownername"`             // Character name of the owner
+	TemplateRoomId int                       `yaml:"templateroomid"`        // The room it was bought from, and is a copy of
+	Title          string                    `yaml:"title,omitempty"`       // If set, replaces the title of the room
+	Description    string                    `yaml:"description,omitempty"` // If set, replaces the description of the room
+	Furniture      map[string]HouseFurniture `yaml:"furniture,omitempty"`   // container name => furniture
+	CoOwners       map[int]string            `yaml:"coowners,omitempty"`    // userId => character name. Can do anything the owner can except sell.
+	Guests         map[int]string            `yaml:"guests,omitempty"`      // userId => character name. Can come and go.
+	RentDueRound   uint64                    `yaml:"rentdueround,omitempty"`
+	MissedRent     int                       `yaml:"missedrent,omitempty"`     // Rent payments missed in a row
+	PricePaid      int                       `yaml:"pricepaid,omitempty"`      // What it was bought for. Selling refunds half of it.
+	FurnitureLimit int                       `yaml:"furniturelimit,omitempty"` // How much furniture fitted when it was bought
+}
+
+// A piece of furniture placed in a house, and what is stored in it
+type HouseFurniture struct {
+	Item  items.Item   `yaml:"item"`
+	Items []items.Item `yaml:"items,omitempty"`
+	Gold  int          `yaml:"gold,omitempty"`
+}
+
+// Returns the house a player owns, or nil
+func GetHouse(ownerUserId int) *House {
+	return houses[ownerUserId]
+}
+
+// Finds a house by the name of its owner
+func FindHouse(ownerName string) *House {
+	for _, h := range houses {
+		if strings.EqualFold(h.OwnerName, ownerName) {
+			return h
+		}
+	}
+	return nil
+}
+
+// Returns every house a player may enter, their own first
+func GetHousesFor(userId int) []*House {
+
+	result := []*House{}
+	for _, h := range houses {
+		if h.CanEnter(userId) {
+			result = append(result, h)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if (result[i].OwnerUserId == userId) != (result[j].OwnerUserId == userId) {
+			return result[i].OwnerUserId == userId
+		}
+		return result[i].OwnerName < result[j].OwnerName
+	})
+
+	return result
+}
+
+func (h *House) RoomId() int {
+	return houseRoomIdStart + h.OwnerUserId
+}
+
+// Whether a player can furnish the house, change its description and decide who else gets in
+func (h *House) CanManage(userId int) bool {
+	if userId == h.OwnerUserId {
+		return true
+	}
+	_, ok := h.CoOwners[userId]
+	return ok
+}
+
+func (h *House) CanEnter(userId int) bool {
+	if h.CanManage(userId) {
+		return true
+	}
+	_, ok := h.Guests[userId]
+	return ok
+}
+
+// Returns the terms the house was sold under
+func (h *House) GetHousing() Housing {
+	if r := peekRoom(h.TemplateRoomId); r != nil {
+		return r.Housing
+	}
+	return Housing{}
+}
+
+// How many pieces of furniture fit, as it was when the house was bought
+func (h *House) GetFurnitureLimit() int {
+	if h.FurnitureLimit > 0 {
+		return h.FurnitureLimit
+	}
+	return h.GetHousing().GetFurnitureLimit()
+}
+
+// Returns the owner's user id if the room is a house, otherwise 0
+func (r *Room) HouseOwnerId() int {
+	return r.houseOwnerId
+}
+
+// Buys a copy of a room that is for sale, paying from the bank
+func BuyHouse(user *users.UserRecord, templateRoomId int) (*House, error) {
+
+	templateRoom := LoadRoom(templateRoomId)
+	if templateRoom == nil || !templateRoom.Housing.ForSale() {
+		return nil, errors.New(`this room isn't for sale`)
+	}
+
+	if houses[user.UserId] != nil {
+		return nil, errors.New(`you already own a house`)
+	}
+
+	if user.Character.Bank < templateRoom.Housing.Price {
+		return nil, fmt.Errorf(`you need %d gold in the bank to buy this house`, templateRoom.Housing.Price)
+	}
+
+	user.Character.Bank -= templateRoom.Housing.Price
+
+	h := &House{
+		OwnerUserId:    user.UserId,
+		OwnerUsername:  user.Username,
+		OwnerName:      user.Character.Name,
+		TemplateRoomId: templateRoomId,
+		PricePaid:      templateRoom.Housing.Price,
+		FurnitureLimit: templateRoom.Housing.GetFurnitureLimit(),
+		Furniture:      map[string]HouseFurniture{},
+		CoOwners:       map[int]string{},
+		Guests:         map[int]string{},
+	}
+
+	if templateRoom.Housing.Rent > 0 {
+		h.RentDueRound = gametime.GetDate().AddPeriod(templateRoom.Housing.GetRentPeriod())
+	}
+
+	houses[h.OwnerUserId] = h
+
+	if err := saveHouse(h); err != nil {
+		slog.Error("BuyHouse()", "error", err)
+	}
+
+	slog.Info("House bought", "owner", h.OwnerName, "templateRoomId", templateRoomId, "price", templateRoom.Housing.Price)
+
+	return h, nil
+}
+
+// Gives up a house, refunding half of what it cost.
+// Everyone inside is moved out, and the furniture ends up in the owner's storage.
+// Returns the refund.
+func SellHouse(ownerUserId int) (int, error) {
+
+	h := houses[ownerUserId]
+	if h == nil {
+		return 0, errNoHouse
+	}
+
+	pricePaid := h.PricePaid
+	if pricePaid == 0 { // Bought before the price was kept
+		pricePaid = h.GetHousing().Price
+	}
+	refund := pricePaid / 2
+
+	if err := h.remove(refund, fmt.Sprintf(`You sold your house for %d gold, which is now in your bank.`, refund)); err != nil {
+		return 0, err
+	}
+
+	return refund, nil
+}
+
+func (h *House) SetTitle(title string) {
+	h.Title = title
+	if r, ok := roomManager.rooms[h.RoomId()]; ok {
+		if title == `` {
+			if templateRoom := peekRoom(h.TemplateRoomId); templateRoom != nil {
+				title = templateRoom.Title
+			}
+		}
+		r.Title = title
+	}
+	h.save()
+}
+
+func (h *House) SetDescription(description string) {
+	h.Description = description
+	if r, ok := roomManager.rooms[h.RoomId()]; ok {
+		if description == `` {
+			if templateRoom := peekRoom(h.TemplateRoomId); templateRoom != nil {
+				description = templateRoom.Description
+			}
+		}
+		r.Description = description
+	}
+	h.save()
+}
+
+// Adds or removes a guest. A removed guest who is inside is shown out.
+func (h *House) SetGuest(userId int, name string, isGuest bool) {
+	if isGuest {
+		h.Guests[userId] = name
+	} else {
+		delete(h.Guests, userId)
+		h.showOutIfNotAllowed(userId)
+	}
+	h.save()
+}
+
+// Adds or removes a co-owner
+func (h *House) SetCoOwner(userId int, name string, isCoOwner bool) {
+	if isCoOwner {
+		h.CoOwners[userId] = name
+		delete(h.Guests, userId)
+	} else {
+		delete(h.CoOwners, userId)
+		h.showOutIfNotAllowed(userId)
+	}
+	h.save()
+}
+
+// Moves a player out of the house if they are inside but may no longer enter
+func (h *House) showOutIfNotAllowed(userId int) {
+
+	if h.CanEnter(userId) {
+		return
+	}
+
+	r, ok := roomManager.rooms[h.RoomId()]
+	if !ok {
+		return
+	}
+
+	for _, playerId := range r.players {
+		if playerId == userId {
+			h.showOut(userId)
+			return
+		}
+	}
+}
+
+// Moves a player from inside the house back to where it was bought
+func (h *House) showOut(userId int) {
+	if user := users.GetByUserId(userId); user != nil {
+		user.SendText(`You are shown out of the house.`)
+		if err := MoveToRoom(userId, h.TemplateRoomId); err != nil {
+			MoveToRoom(userId, StartRoomIdAlias)
+		}
+	}
+}
+
+// Places a piece of furniture, which becomes a container in the house.
+// Returns the name of the container.
+func (h *House) AddFurniture(itm items.Item) (string, error) {
+
+	spec := itm.GetSpec()
+	if spec.Type != items.Furniture {
+		return ``, errors.New(`that isn't furniture`)
+	}
+
+	if len(h.Furniture) >= h.GetFurnitureLimit() {
+		return ``, errors.New(`there is no room for more furniture`)
+	}
+
+	r := LoadRoom(h.RoomId())
+	if r == nil {
+		return ``, errNoHouse
+	}
+
+	baseName := strings.ToLower(spec.NameSimple)
+	if baseName == `` {
+		baseName = strings.ToLower(spec.Name)
+	}
+
+	containerName := baseName
+	for i := 2; ; i++ {
+		if _, ok := r.Containers[containerName]; !ok {
+			break
+		}
+		containerName = fmt.Sprintf(`%s %d`, baseName, i)
+	}
+
+	h.Furniture[containerName] = HouseFurniture{Item: itm}
+
+	if r.Containers == nil {
+		r.Containers = map[string]Container{}
+	}
+	r.Containers[containerName] = Container{Capacity: spec.Capacity}
+
+	h.save()
+
+	return containerName, nil
+}
+
+// Takes back a piece of furniture. It has to be empty.
+func (h *House) RemoveFurniture(containerName string) (items.Item, error) {
+
+	r := LoadRoom(h.RoomId())
+	if r == nil {
+		return items.Item{}, errNoHouse
+	}
+
+	h.syncFromRoom(r)
+
+	furniture, ok := h.Furniture[containerName]
+	if !ok {
+		return items.Item{}, errors.New(`there is no furniture by that name`)
+	}
+
+	if len(furniture.Items) > 0 || furniture.Gold > 0 {
+		return items.Item{}, errors.New(`it has to be emptied first`)
+	}
+
+	delete(h.Furniture, containerName)
+	delete(r.Containers, containerName)
+
+	h.save()
+
+	return furniture.Item, nil
+}
+
+// Builds the room for a house from the room it was bought from
+func loadHouseRoom(roomId int) *Room {
+
+	h := houses[roomId-houseRoomIdStart]
+	if h == nil {
+		return nil
+	}
+
+	r, err := copyRoomForInstance(h.TemplateRoomId)
+	if err != nil {
+		slog.Error("loadHouseRoom()", "roomId", roomId, "error", err)
+		return nil
+	}
+
+	r.RoomId = roomId
+	r.houseOwnerId = h.OwnerUserId
+	r.sourceRoomId = h.TemplateRoomId
+	r.Housing = Housing{}
+	r.SpawnInfo = nil
+	r.ZoneConfig = ZoneConfig{}
+
+	if h.Title != `` {
+		r.Title = h.Title
+	}
+	if h.Description != `` {
+		r.Description = h.Description
+	}
+
+	r.Containers = map[string]Container{}
+	for containerName, furniture := range h.Furniture {
+		r.Containers[containerName] = Container{
+			Items:    append([]items.Item{}, furniture.Items...),
+			Gold:     furniture.Gold,
+			Capacity: furniture.Item.GetSpec().Capacity,
+		}
+	}
+
+	roomManager.rooms[roomId] = r
+
+	return r
+}
+
+// Copies what is stored in the furniture of a loaded house room back to the house
+func (h *House) syncFromRoom(r *Room) {
+	for containerName, furniture := range h.Furniture {
+		if c, ok := r.Containers[containerName]; ok {
+			furniture.Items = append([]items.Item{}, c.Items...)
+			furniture.Gold = c.Gold
+			h.Furniture[containerName] = furniture
+		}
+	}
+}
+
+func (h *House) save() {
+	if err := saveHouse(h); err != nil {
+		slog.Error("House.save()", "owner", h.OwnerName, "error", err)
+	}
+}
+
+func houseFilePath(username string) string {
+	return util.FilePath(string(configs.GetConfig().FolderUserData), `/`, strings.ToLower(username)+`-house.yaml`)
+}
+
+func saveHouse(h *House) error {
+
+	if r, ok := roomManager.rooms[h.RoomId()]; ok {
+		h.syncFromRoom(r)
+	}
+
+	data, err := yaml.Marshal(h)
+	if err != nil {
+		return err
+	}
+
+	path := houseFilePath(h.OwnerUsername)
+
+	saveFilePath := path
+	if configs.GetConfig().CarefulSaveFiles { // careful save first saves a {filename}.new file
+		saveFilePath += `.new`
+	}
+
+	if err := os.WriteFile(saveFilePath, data, 0777); err != nil {
+		return err
+	}
+
+	if saveFilePath != path {
+		return os.Rename(saveFilePath, path)
+	}
+
+	return nil
+}
+
+// Saves every house
+func saveAllHouses() {
+	for _, h := range houses {
+		h.save()
+	}
+}
+
+// Loads every house file in the user data folder
+func loadHouses() {
+
+	clear(houses)
+
+	matches, err := filepath.Glob(util.FilePath(string(configs.GetConfig().FolderUserData), `/*-house.yaml`))
+	if err != nil {
+		slog.Error("loadHouses()", "error", err)
+		return
+	}
+
+	for _, path := range matches {
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			slog.Error("loadHouses()", "path", path, "error", err)
+			continue
+		}
+
+		h := &House{}
+		if err := yaml.Unmarshal(data, h); err != nil {
+			slog.Error("loadHouses()", "path", path, "error", err)
+			continue
+		}
+
+		if h.Furniture == nil {
+			h.Furniture = map[string]HouseFurniture{}
+		}
+		if h.CoOwners == nil {
+			h.CoOwners = map[int]string{}
+		}
+		if h.Guests == nil {
+			h.Guests = map[int]string{}
+		}
+
+		houses[h.OwnerUserId] = h
+	}
+
+	slog.Info("loadHouses()", "count", len(houses))
+}
+
+// Collects rent that is due, and evicts owners who have missed too many payments
+func houseMaintenance() bool {
+
+	roundNow := util.GetRoundCount()
+
+	ownerIds := []int{}
+	for ownerUserId, h := range houses {
+		if h.RentDueRound > 0 && roundNow >= h.RentDueRound {
+			ownerIds = append(ownerIds, ownerUserId)
+		}
+	}
+	sort.Ints(ownerIds)
+
+	for _, ownerUserId := range ownerIds {
+		houses[ownerUserId].collectRent()
+	}
+
+	return len(ownerIds) > 0
+}
+
+// Takes rent from the owner's bank, whether they're online or not
+func (h *House) collectRent() {
+
+	housing := h.GetHousing()
+
+	// The room stopped charging rent
+	if housing.Rent < 1 {
+		h.RentDueRound = 0
+		h.MissedRent = 0
+		h.save()
+		return
+	}
+
+	h.RentDueRound = gametime.GetDate(h.RentDueRound).AddPeriod(housing.GetRentPeriod())
+
+	paid := false
+	err := updateUser(h.OwnerUserId, h.OwnerUsername, func(u *users.UserRecord) {
+		if u.Character.Bank >= housing.Rent {
+			u.Character.Bank -= housing.Rent
+			paid = true
+			u.SendText(fmt.Sprintf(`<ansi fg="gold">%d gold</ansi> was taken from your bank to pay the rent on your house.`, housing.Rent))
+		}
+	})
+
+	if err != nil {
+		slog.Error("House.collectRent()", "owner", h.OwnerName, "error", err)
+		h.save()
+		return
+	}
+
+	if paid {
+		h.MissedRent = 0
+		h.save()
+		return
+	}
+
+	h.MissedRent++
+
+	if h.MissedRent >= housing.GetEvictAfter() {
+		slog.Info("House eviction", "owner", h.OwnerName, "missedRent", h.MissedRent)
+		h.remove(0, `You missed too many rent payments and were evicted from your house.`)
+		return
+	}
+
+	h.save()
+
+	updateUser(h.OwnerUserId, h.OwnerUsername, func(u *users.UserRecord) {
+		msg := fmt.Sprintf(`You couldn't pay the %d gold rent on your house. If you miss %d more payments you will be evicted.`, housing.Rent, housing.GetEvictAfter()-h.MissedRent)
+		u.Inbox.Add(users.Message{FromName: houseMailFrom, Message: msg})
+		u.SendText(`<ansi fg="alert-4">` + msg + `</ansi>`)
+	})
+}
+
+// Gets rid of a house. Everyone inside is moved out, the furniture and what's in it go to the owner's storage,
+// and the refund goes to their bank.
+// If the owner can't be given their things, the house is left as it is so it can be tried again.
+func (h *House) remove(refund int, reason string) error {
+
+	r, loaded := roomManager.rooms[h.RoomId()]
+	if loaded {
+		h.syncFromRoom(r)
+	}
+
+	err := updateUser(h.OwnerUserId, h.OwnerUsername, func(u *users.UserRecord) {
+
+		gold := refund
+		itemCt := 0
+		for _, furniture := range h.Furniture {
+			for _, itm := range append([]items.Item{furniture.Item}, furniture.Items...) {
+				if u.ItemStorage.AddItem(itm) {
+					itemCt++
+				}
+			}
+			gold += furniture.Gold
+		}
+		u.Character.Bank += gold
+
+		msg := reason
+		if itemCt > 0 {
+			msg += fmt.Sprintf(` Your furniture and everything in it (%d items) were put in your storage.`, itemCt)
+		}
+		if gold > refund {
+			msg += fmt.Sprintf(` The %d gold left in your furniture was put in your bank.`, gold-refund)
+		}
+
+		u.Inbox.Add(users.Message{FromName: houseMailFrom, Message: msg})
+		u.SendText(`<ansi fg="alert-4">` + msg + `</ansi>`)
+	})
+
+	if err != nil {
+		slog.Error("House.remove()", "owner", h.OwnerName, "error", err)
+		h.save()
+		return err
+	}
+
+	if loaded {
+
+		for _, userId := range append([]int{}, r.players...) {
+			h.showOut(userId)
+		}
+
+		// Pets and anything else that followed someone in
+		if toRoom := LoadRoom(h.TemplateRoomId); toRoom != nil {
+			for _, mobInstanceId := range append([]int{}, r.mobs...) {
+				r.RemoveMob(mobInstanceId)
+				toRoom.AddMob(mobInstanceId)
+			}
+		} else {
+			for _, mobInstanceId := range r.mobs {
+				mobs.DestroyInstance(mobInstanceId)
+			}
+		}
+
+		delete(roomManager.rooms, r.RoomId)
+		delete(roomManager.roomsWithUsers, r.RoomId)
+		delete(roomManager.roomsWithMobs, r.RoomId)
+		delete(pathNodes, r.RoomId)
+	}
+
+	delete(houses, h.OwnerUserId)
+
+	if err := os.Remove(houseFilePath(h.OwnerUsername)); err != nil && !os.IsNotExist(err) {
+		slog.Error("House.remove()", "owner", h.OwnerName, "error", err)
+	}
+
+	slog.Info("House removed", "owner", h.OwnerName, "templateRoomId", h.TemplateRoomId, "refund", refund)
+
+	return nil
+}
+
+// Makes a change to a user whether they are online or not. Offline users are loaded and saved again.
+func updateUser(userId int, username string, update func(u *users.UserRecord)) error {
+
+	if u := users.GetByUserId(userId); u != nil {
+		update(u)
+		return nil
+	}
+
+	u, err := users.LoadUser(username, true)
+	if err != nil {
+		return err
+	}
+
+	update(u)
+
+	return users.SaveUser(*u)
+}
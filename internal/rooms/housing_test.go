@@ -0,0 +1,85 @@
+package rooms
+
+import (
+	"testing"
+
+	"github.com/volte6/gomud/internal/exit"
+	"github.com/volte6/gomud/internal/items"
+)
+
+func TestHouseRoom(t *testing.T) {
+
+	zone := `Housing Test`
+
+	street := newTestRoom(t, 900401, zone)
+	forSale := newTestRoom(t, 900402, zone)
+
+	forSale.Exits[`out`] = exit.RoomExit{RoomId: street.RoomId}
+	forSale.Housing = Housing{Price: 1000, Rent: 50}
+
+	h := &House{
+		OwnerUserId:    7,
+		OwnerName:      `Tester`,
+		TemplateRoomId: forSale.RoomId,
+		Title:          `Tester's Cottage`,
+		Furniture: map[string]HouseFurniture{
+			`chest`: {Item: items.Item{ItemId: 28}, Items: []items.Item{{ItemId: 1}}, Gold: 5},
+		},
+		CoOwners: map[int]string{},
+		Guests:   map[int]string{8: `Guest`},
+	}
+	houses[h.OwnerUserId] = h
+	defer func() {
+		delete(houses, h.OwnerUserId)
+		delete(roomManager.rooms, h.RoomId())
+	}()
+
+	r := LoadRoom(h.RoomId())
+	if r == nil || r.HouseOwnerId() != h.OwnerUserId || r.SourceRoomId() != forSale.RoomId {
+		t.Fatalf("LoadRoom(%d) = %v, expected a copy of room %d", h.RoomId(), r, forSale.RoomId)
+	}
+
+	if r.Title != h.Title || r.Housing.ForSale() || r.Exits[`out`].RoomId != street.RoomId {
+		t.Errorf("house room = %q, for sale %t, expected the house title, not for sale, and the same exits", r.Title, r.Housing.ForSale())
+	}
+
+	chest, ok := r.Containers[`chest`]
+	if !ok || len(chest.Items) != 1 || chest.Gold != 5 {
+		t.Errorf("house room containers = %+v, expected the chest and what's in it", r.Containers)
+	}
+
+	if !CanEnterRoom(7, r.RoomId) || !CanEnterRoom(8, r.RoomId) || CanEnterRoom(9, r.RoomId) {
+		t.Errorf("CanEnterRoom() should only let in the owner and guests")
+	}
+
+	if h.CanManage(8) {
+		t.Errorf("CanManage() let a guest manage the house")
+	}
+
+	chest.AddItem(items.Item{ItemId: 2})
+	r.Containers[`chest`] = chest
+	h.syncFromRoom(r)
+	if len(h.Furniture[`chest`].Items) != 2 {
+		t.Errorf("syncFromRoom() = %+v, expected 2 items in the chest", h.Furniture[`chest`])
+	}
+
+	if found := FindHouse(`tester`); found != h {
+		t.Errorf("FindHouse() = %v, expected the house of Tester", found)
+	}
+
+	if housing := h.GetHousing(); housing.GetEvictAfter() != houseDefaultEvictAfter || housing.GetRentPeriod() != houseDefaultRentPeriod {
+		t.Errorf("GetHousing() = %+v, expected default eviction and rent period", housing)
+	}
+
+	// The limit the house was bought with is kept if the room for sale changes
+	h.FurnitureLimit = 2
+	forSale.Housing.Furniture = 1
+	if limit := h.GetFurnitureLimit(); limit != 2 {
+		t.Errorf("GetFurnitureLimit() = %d, expected the 2 it was bought with", limit)
+	}
+
+	forSale.Housing.RentPeriod = `1 xx`
+	if err := forSale.Validate(); err == nil {
+		t.Errorf("Validate() expected an error for a bad rent period")
+	}
+}
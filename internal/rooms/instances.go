@@ -0,0 +1,448 @@
+package rooms
+
+import (
+	"fmt"
+	"log/slog"
+	"sort"
+
+	"github.com/volte6/gomud/internal/configs"
+	"github.com/volte6/gomud/internal/exit"
+	"github.com/volte6/gomud/internal/mobs"
+	"github.com/volte6/gomud/internal/parties"
+	"github.com/volte6/gomud/internal/users"
+	"github.com/volte6/gomud/internal/util"
+
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// Instance room ids count up from here, well past the id of any real room
+	instanceRoomIdStart = 1000000000
+	// How long an instance lasts after the last player leaves it
+	instanceEmptySeconds = 120
+	// How long an instance lasts at most, even with players inside
+	instanceTimeoutSeconds = 3600
+	// How long before the timeout players inside are warned
+	instanceWarningSeconds = 60
+)
+
+var (
+	instances          = map[int]*Instance{} // instanceId => instance
+	nextInstanceId     = 1
+	nextInstanceRoomId = instanceRoomIdStart
+)
+
+// A private copy of a zone that belongs to a party.
+// Its rooms only exist in memory and are never saved.
+type Instance struct {
+	InstanceId        int
+	Zone              string
+	OwnerUserId       int    // The player whose party the instance belongs to
+	EntranceRoomId    int    // Where players are sent when the instance is torn down
+	RootRoomId        int    // The copy of the zone's root room, which holds the zone config
+	CreatedRound      uint64 // When the instance was created
+	LastOccupiedRound uint64 // The last round a player was inside
+	warned            bool   // Whether the players inside were warned of the timeout
+	roomIds           map[int]int
+}
+
+// Returns the instance with the given id, or nil
+func GetInstance(instanceId int) *Instance {
+	return instances[instanceId]
+}
+
+// Returns every instance, sorted by id
+func GetAllInstances() []*Instance {
+
+	all := make([]*Instance, 0, len(instances))
+	for _, inst := range instances {
+		all = append(all, inst)
+	}
+
+	sort.Slice(all, func(i, j int) bool {
+		return all[i].InstanceId < all[j].InstanceId
+	})
+
+	return all
+}
+
+// Returns the room ids of the instance, sorted
+func (inst *Instance) GetRoomIds() []int {
+
+	roomIds := make([]int, 0, len(inst.roomIds))
+	for _, roomId := range inst.roomIds {
+		roomIds = append(roomIds, roomId)
+	}
+	sort.Ints(roomIds)
+
+	return roomIds
+}
+
+// Returns the user ids of every player inside the instance
+func (inst *Instance) GetPlayers() []int {
+
+	userIds := []int{}
+	for _, roomId := range inst.GetRoomIds() {
+		if r, ok := roomManager.rooms[roomId]; ok {
+			userIds = append(userIds, r.players...)
+		}
+	}
+
+	return userIds
+}
+
+// Whether a player may enter the instance.
+// Its owner may, as may anyone in a party with the owner or with someone already inside.
+func (inst *Instance) CanEnter(userId int) bool {
+
+	if userId == inst.OwnerUserId {
+		return true
+	}
+
+	party := parties.Get(userId)
+	if party == nil || !party.IsMember(userId) {
+		return false
+	}
+
+	if party.IsMember(inst.OwnerUserId) {
+		return true
+	}
+
+	for _, insideUserId := range inst.GetPlayers() {
+		if party.IsMember(insideUserId) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Returns the id of the instance the room is part of, or 0 if it isn't part of one
+func (r *Room) InstanceId() int {
+	return r.instanceId
+}
+
+// Returns the id of the room an instanced room was copied from, or its own id if it isn't part of an instance
+func (r *Room) SourceRoomId() int {
+	if r.sourceRoomId > 0 {
+		return r.sourceRoomId
+	}
+	return r.RoomId
+}
+
+// Returns the zone config of the room's zone, or of the instance's copy of it
+func (r *Room) getZoneConfig() *ZoneConfig {
+
+	if inst := instances[r.instanceId]; inst != nil {
+		if rootRoom, ok := roomManager.rooms[inst.RootRoomId]; ok {
+			return &rootRoom.ZoneConfig
+		}
+	}
+
+	return GetZoneConfig(r.Zone)
+}
+
+// Whether a player is allowed in a room.
+// Only rooms that are part of an instance or a house can be off limits.
+func CanEnterRoom(userId int, roomId int) bool {
+
+	r, ok := roomManager.rooms[roomId]
+	if !ok {
+		return true
+	}
+
+	if r.houseOwnerId > 0 {
+		if h := houses[r.houseOwnerId]; h != nil {
+			return h.CanEnter(userId)
+		}
+		return false
+	}
+
+	if r.instanceId == 0 {
+		return true
+	}
+
+	if inst := instances[r.instanceId]; inst != nil {
+		return inst.CanEnter(userId)
+	}
+
+	return false
+}
+
+// Finds the room a player arrives in when going through an instanced exit that leads to toRoomId.
+// The first member of a party to go through gets a copy of the zone made, which the rest of the party shares.
+func EnterInstance(userId int, entranceRoomId int, toRoomId int) (int, error) {
+
+	toRoom := peekRoom(toRoomId)
+	if toRoom == nil {
+		return 0, fmt.Errorf(`room %d not found`, toRoomId)
+	}
+
+	// Already inside of an instance
+	if toRoom.instanceId > 0 {
+		return toRoomId, nil
+	}
+
+	if instRoomId, ok := FindInstanceRoom(userId, toRoomId); ok {
+		return instRoomId, nil
+	}
+
+	ownerUserId := userId
+	if party := parties.Get(userId); party != nil && party.IsMember(userId) {
+		ownerUserId = party.LeaderUserId
+	}
+
+	inst, err := newInstance(toRoom.Zone, ownerUserId, entranceRoomId)
+	if err != nil {
+		return 0, err
+	}
+
+	instRoomId, ok := inst.roomIds[toRoomId]
+	if !ok {
+		return 0, fmt.Errorf(`room %d is not part of zone %s`, toRoomId, toRoom.Zone)
+	}
+
+	return instRoomId, nil
+}
+
+// Finds the copy of a room in an instance the player can already enter, without making a new instance
+func FindInstanceRoom(userId int, sourceRoomId int) (int, bool) {
+
+	for _, inst := range GetAllInstances() {
+		if instRoomId, ok := inst.roomIds[sourceRoomId]; ok && inst.CanEnter(userId) {
+			return instRoomId, true
+		}
+	}
+
+	return 0, false
+}
+
+// Copies every room in a zone into a new instance
+func newInstance(zone string, ownerUserId int, entranceRoomId int) (*Instance, error) {
+
+	sourceRoomIds := GetZoneRoomIds(zone)
+	if len(sourceRoomIds) == 0 {
+		return nil, fmt.Errorf(`zone %s has no rooms`, zone)
+	}
+	sort.Ints(sourceRoomIds)
+
+	roundNow := util.GetRoundCount()
+
+	inst := &Instance{
+		InstanceId:        nextInstanceId,
+		Zone:              zone,
+		OwnerUserId:       ownerUserId,
+		EntranceRoomId:    entranceRoomId,
+		CreatedRound:      roundNow,
+		LastOccupiedRound: roundNow,
+		roomIds:           make(map[int]int, len(sourceRoomIds)),
+	}
+
+	instRooms := make([]*Room, 0, len(sourceRoomIds))
+	for _, sourceRoomId := range sourceRoomIds {
+
+		r, err := copyRoomForInstance(sourceRoomId)
+		if err != nil {
+			return nil, err
+		}
+
+		r.RoomId = nextInstanceRoomId + len(instRooms)
+		r.instanceId = inst.InstanceId
+		r.sourceRoomId = sourceRoomId
+		r.lastVisited = roundNow
+		r.Housing = Housing{} // A house bought here would be a copy of a room that goes away
+
+		inst.roomIds[sourceRoomId] = r.RoomId
+		instRooms = append(instRooms, r)
+	}
+
+	for _, r := range instRooms {
+
+		// Exits within the zone lead to the instance's copies, anything else stays as it was.
+		for exitName, exitInfo := range r.Exits {
+			if instRoomId, ok := inst.roomIds[exitInfo.RoomId]; ok {
+				exitInfo.RoomId = instRoomId
+				exitInfo.Instanced = false
+				r.Exits[exitName] = exitInfo
+			}
+		}
+
+		if r.ZoneConfig.RoomId == r.sourceRoomId {
+			r.ZoneConfig.RoomId = r.RoomId
+			inst.RootRoomId = r.RoomId
+		}
+
+		roomManager.rooms[r.RoomId] = r
+	}
+
+	nextInstanceId++
+	nextInstanceRoomId += len(instRooms)
+
+	instances[inst.InstanceId] = inst
+
+	slog.Info("Instance created", "instanceId", inst.InstanceId, "zone", zone, "ownerUserId", ownerUserId, "rooms", len(instRooms))
+
+	return inst, nil
+}
+
+// Makes a fresh copy of a room, without anything players left behind in it
+func copyRoomForInstance(sourceRoomId int) (*Room, error) {
+
+	sourceRoom := peekRoom(sourceRoomId)
+	if sourceRoom == nil {
+		return nil, fmt.Errorf(`room %d not found`, sourceRoomId)
+	}
+
+	data, err := yaml.Marshal(sourceRoom)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Room{}
+	if err := yaml.Unmarshal(data, r); err != nil {
+		return nil, err
+	}
+
+	r.Items = nil
+	r.Stash = nil
+	r.Gold = 0
+	r.Signs = nil
+	r.ScriptTimers = nil
+
+	for name, c := range r.Containers {
+		c.Items = nil
+		c.Gold = 0
+		r.Containers[name] = c
+	}
+
+	for idx := range r.SpawnInfo {
+		r.SpawnInfo[idx].InstanceId = 0
+		r.SpawnInfo[idx].DespawnedRound = 0
+	}
+
+	if r.Exits == nil {
+		r.Exits = make(map[string]exit.RoomExit)
+	}
+	r.Effects = map[EffectType]AreaEffect{}
+	r.players = []int{}
+	r.visitors = make(map[VisitorType]map[int]uint64)
+	r.tempDataStore = make(map[string]any)
+
+	// Share the description with the original, the same as loaded rooms do
+	if description := r.GetDescription(); description != `` {
+		hash := util.Hash(description)
+		if _, ok := roomManager.roomDescriptionCache[hash]; !ok {
+			roomManager.roomDescriptionCache[hash] = description
+		}
+		r.Description = fmt.Sprintf(`h:%s`, hash)
+	}
+
+	return r, nil
+}
+
+// Tears down instances that have been empty too long or have timed out
+func instanceMaintenance() bool {
+
+	if len(instances) == 0 {
+		return false
+	}
+
+	cfg := configs.GetConfig()
+	roundNow := util.GetRoundCount()
+
+	emptyRounds := uint64(cfg.SecondsToRounds(instanceEmptySeconds))
+	timeoutRounds := uint64(cfg.SecondsToRounds(instanceTimeoutSeconds))
+	warningRounds := uint64(cfg.SecondsToRounds(instanceTimeoutSeconds - instanceWarningSeconds))
+
+	tornDown := false
+	for _, inst := range GetAllInstances() {
+
+		playersInside := inst.GetPlayers()
+		if len(playersInside) > 0 {
+			inst.LastOccupiedRound = roundNow
+		}
+
+		if roundNow-inst.LastOccupiedRound >= emptyRounds || roundNow-inst.CreatedRound >= timeoutRounds {
+			inst.teardown()
+			tornDown = true
+			continue
+		}
+
+		if !inst.warned && roundNow-inst.CreatedRound >= warningRounds {
+			inst.warned = true
+			for _, userId := range playersInside {
+				if user := users.GetByUserId(userId); user != nil {
+					user.SendText(`The air shimmers. You sense this place won't last much longer.`)
+				}
+			}
+		}
+	}
+
+	return tornDown
+}
+
+// Sends everyone inside back to the entrance and removes the instance's rooms and mobs
+func (inst *Instance) teardown() {
+
+	for _, userId := range inst.GetPlayers() {
+
+		user := users.GetByUserId(userId)
+		if user == nil {
+			continue
+		}
+
+		fromRoom := roomManager.rooms[user.Character.RoomId]
+
+		user.SendText(`The world around you fades away, and you find yourself back where you entered.`)
+
+		if err := MoveToRoom(userId, inst.EntranceRoomId); err != nil {
+			MoveToRoom(userId, StartRoomIdAlias)
+		}
+
+		// Charmed mobs go with whoever charmed them
+		if fromRoom != nil {
+			if toRoom := roomManager.rooms[user.Character.RoomId]; toRoom != nil {
+				for _, mobInstanceId := range fromRoom.GetMobs(FindCharmed) {
+					if mob := mobs.GetInstance(mobInstanceId); mob != nil && mob.Character.IsCharmed(userId) {
+						fromRoom.RemoveMob(mobInstanceId)
+						toRoom.AddMob(mobInstanceId)
+					}
+				}
+			}
+		}
+	}
+
+	for _, roomId := range inst.GetRoomIds() {
+
+		room, ok := roomManager.rooms[roomId]
+		if !ok {
+			continue
+		}
+
+		for _, mobInstanceId := range room.mobs {
+			mobs.DestroyInstance(mobInstanceId)
+		}
+
+		// Mobs spawned here may have wandered out of the instance
+		for _, spawnDetails := range room.SpawnInfo {
+			if spawnDetails.InstanceId == 0 {
+				continue
+			}
+			if m := mobs.GetInstance(spawnDetails.InstanceId); m != nil {
+				if mobRoom, ok := roomManager.rooms[m.Character.RoomId]; ok {
+					mobRoom.RemoveMob(spawnDetails.InstanceId)
+				}
+				mobs.DestroyInstance(spawnDetails.InstanceId)
+			}
+		}
+
+		delete(roomManager.rooms, roomId)
+		delete(roomManager.roomsWithUsers, roomId)
+		delete(roomManager.roomsWithMobs, roomId)
+		delete(pathNodes, roomId)
+	}
+
+	delete(instances, inst.InstanceId)
+
+	slog.Info("Instance torn down", "instanceId", inst.InstanceId, "zone", inst.Zone, "ownerUserId", inst.OwnerUserId)
+}
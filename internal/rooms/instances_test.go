@@ -0,0 +1,70 @@
+package rooms
+
+import (
+	"testing"
+
+	"github.com/volte6/gomud/internal/exit"
+)
+
+func TestEnterInstance(t *testing.T) {
+
+	zone := `Instance Test`
+
+	// entrance -down (instanced)-> 1 -east-> 2 -up-> entrance
+	entrance := newTestRoom(t, 900601, `Instance Test Entrance`)
+	r1 := newTestRoom(t, 900602, zone)
+	r2 := newTestRoom(t, 900603, zone)
+
+	entrance.Exits[`down`] = exit.RoomExit{RoomId: r1.RoomId, Instanced: true}
+	r1.Exits[`east`] = exit.RoomExit{RoomId: r2.RoomId}
+	r2.Exits[`up`] = exit.RoomExit{RoomId: entrance.RoomId}
+	r1.ZoneConfig.RoomId = r1.RoomId
+
+	instRoomId, err := EnterInstance(1, entrance.RoomId, r1.RoomId)
+	if err != nil {
+		t.Fatalf("EnterInstance() error = %v", err)
+	}
+
+	instRoom := LoadRoom(instRoomId)
+	if instRoom == nil || instRoom.InstanceId() == 0 || instRoom.SourceRoomId() != r1.RoomId {
+		t.Fatalf("EnterInstance() = %d, expected a copy of room %d", instRoomId, r1.RoomId)
+	}
+
+	inst := GetInstance(instRoom.InstanceId())
+	defer inst.teardown()
+
+	if inst.RootRoomId != instRoomId || instRoom.getZoneConfig() != &instRoom.ZoneConfig {
+		t.Errorf("instance root room = %d, expected %d", inst.RootRoomId, instRoomId)
+	}
+
+	instEast := LoadRoom(instRoom.Exits[`east`].RoomId)
+	if instEast == nil || instEast.InstanceId() != inst.InstanceId || instEast.SourceRoomId() != r2.RoomId {
+		t.Errorf("instanced east exit leads to %d, expected the instance's copy of %d", instRoom.Exits[`east`].RoomId, r2.RoomId)
+	} else if instEast.Exits[`up`].RoomId != entrance.RoomId {
+		t.Errorf("exit out of the instance leads to %d, expected %d", instEast.Exits[`up`].RoomId, entrance.RoomId)
+	}
+
+	if againRoomId, _ := EnterInstance(1, entrance.RoomId, r1.RoomId); againRoomId != instRoomId {
+		t.Errorf("EnterInstance() again = %d, expected the same instance room %d", againRoomId, instRoomId)
+	}
+
+	if CanEnterRoom(2, instRoomId) {
+		t.Errorf("CanEnterRoom() let another player into the instance")
+	}
+
+	otherRoomId, err := EnterInstance(2, entrance.RoomId, r1.RoomId)
+	if err != nil || otherRoomId == instRoomId {
+		t.Errorf("EnterInstance() for another player = %d, %v, expected a separate instance", otherRoomId, err)
+	}
+	if otherRoom := LoadRoom(otherRoomId); otherRoom != nil {
+		otherInst := GetInstance(otherRoom.InstanceId())
+		otherInst.teardown()
+		if LoadRoom(otherRoomId) != nil || GetInstance(otherInst.InstanceId) != nil {
+			t.Errorf("teardown() left room %d behind", otherRoomId)
+		}
+	}
+
+	if SaveRoom(*instRoom) != nil {
+		t.Errorf("SaveRoom() of an instanced room returned an error")
+	}
+}
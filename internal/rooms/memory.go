@@ -12,6 +12,8 @@ func GetMemoryUsage() map[string]util.MemoryResult {
 	ret["zones"] = util.MemoryResult{util.MemoryUsage(roomManager.zones), len(roomManager.zones)}
 	ret["roomsWithUsers"] = util.MemoryResult{util.MemoryUsage(roomManager.roomsWithUsers), len(roomManager.roomsWithUsers)}
 	ret["roomIdToFileCache"] = util.MemoryResult{util.MemoryUsage(roomManager.roomIdToFileCache), len(roomManager.roomIdToFileCache)}
+	ret["instances"] = util.MemoryResult{util.MemoryUsage(instances), len(instances)}
+	ret["houses"] = util.MemoryResult{util.MemoryUsage(houses), len(houses)}
 
 	return ret
 }
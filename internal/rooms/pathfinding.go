@@ -0,0 +1,429 @@
+package rooms
+
+import (
+	"log/slog"
+	"sort"
+	"strings"
+
+	"github.com/volte6/gomud/internal/fileloader"
+	"github.com/volte6/gomud/internal/gamelock"
+	"github.com/volte6/gomud/internal/util"
+)
+
+const (
+	// How many rooms a path search looks at before giving up
+	pathMaxRooms = 5000
+)
+
+var (
+	pathNodes     = map[int]*pathNode{} // roomId => its exits, for every room in a cached zone
+	pathZoneRooms = map[string][]int{}  // zone => room ids cached for it
+)
+
+// A single move along a path
+type PathStep struct {
+	ExitName string
+	RoomId   int // The room the exit leads to
+}
+
+// Decides which exits and rooms a path may use
+type PathOptions struct {
+	SecretExits bool                                         // Whether secret exits can be used
+	OpenDoors   bool                                         // Whether closed doors can be opened. If false, only doors that are open are used.
+	CanUnlock   func(lockId string, lock gamelock.Lock) bool // Whether a locked exit can be opened. If nil, locked exits are avoided.
+	HasItem     func(itemId int) bool                        // Whether the item a biome requires is carried. If nil, those rooms are avoided.
+	Zone        string                                       // If set, the path never leaves this zone
+	MaxSteps    int                                          // If above zero, longer paths aren't searched
+}
+
+// The parts of a room that pathfinding needs, so that rooms don't have to be loaded to find a path through them
+type pathNode struct {
+	zone           string
+	legend         string
+	isBank         bool
+	isStorage      bool
+	requiredItemId int // Item required to enter, from the biome
+	exits          []pathExit
+}
+
+type pathExit struct {
+	name           string
+	roomId         int
+	secret         bool
+	door           bool
+	lockId         string
+	lockDifficulty uint8 // 0 if it has no lock
+}
+
+func newPathNode(r *Room) *pathNode {
+
+	node := &pathNode{
+		zone:           r.Zone,
+		legend:         r.MapLegend,
+		isBank:         r.IsBank,
+		isStorage:      r.IsStorage,
+		requiredItemId: r.GetBiome().RequiredItemId(),
+		exits:          make([]pathExit, 0, len(r.Exits)),
+	}
+
+	for exitName, exitInfo := range r.Exits {
+		node.exits = append(node.exits, pathExit{
+			name:           exitName,
+			roomId:         exitInfo.RoomId,
+			secret:         exitInfo.Secret,
+			door:           exitInfo.HasDoor(),
+			lockId:         exitInfo.LockId(r.RoomId, exitName),
+			lockDifficulty: exitInfo.Lock.Difficulty,
+		})
+	}
+
+	// Sorted so that paths of equal length are always chosen the same way
+	sort.Slice(node.exits, func(i, j int) bool {
+		return node.exits[i].name < node.exits[j].name
+	})
+
+	return node
+}
+
+// Returns a room that is in memory, or reads it from its file without loading it into memory
+func peekRoom(roomId int) *Room {
+
+	if r, ok := roomManager.rooms[roomId]; ok {
+		return r
+	}
+
+	filePath, ok := roomManager.roomIdToFileCache[roomId]
+	if !ok {
+		return LoadRoom(roomId)
+	}
+
+	r, err := fileloader.LoadFlatFile[*Room](util.FilePath(roomDataFilesPath, `/`, filePath))
+	if err != nil {
+		return nil
+	}
+
+	return r
+}
+
+// Caches the exits of every room in a zone
+func cacheZonePaths(zone string) {
+
+	roomIds := GetZoneRoomIds(zone)
+	sort.Ints(roomIds)
+
+	for _, roomId := range roomIds {
+		if r := peekRoom(roomId); r != nil {
+			pathNodes[roomId] = newPathNode(r)
+		}
+	}
+
+	pathZoneRooms[zone] = roomIds
+
+	slog.Debug("rooms.cacheZonePaths()", "zone", zone, "rooms", len(roomIds))
+}
+
+func getPathNode(roomId int) *pathNode {
+
+	if node, ok := pathNodes[roomId]; ok {
+		return node
+	}
+
+	r := peekRoom(roomId)
+	if r == nil {
+		return nil
+	}
+
+	// Instanced rooms and houses aren't part of their zone's list of rooms
+	if r.instanceId > 0 || r.houseOwnerId > 0 {
+		pathNodes[roomId] = newPathNode(r)
+		return pathNodes[roomId]
+	}
+
+	if _, ok := pathZoneRooms[r.Zone]; ok {
+		// The zone is cached but the room is new
+		pathNodes[roomId] = newPathNode(r)
+		pathZoneRooms[r.Zone] = append(pathZoneRooms[r.Zone], roomId)
+	} else {
+		cacheZonePaths(r.Zone)
+	}
+
+	return pathNodes[roomId]
+}
+
+// Forgets the cached exits of a zone, so they are read again the next time a path is needed.
+// Called when exits are changed without saving the room.
+func ClearPathCache(zone string) {
+	for _, roomId := range pathZoneRooms[zone] {
+		delete(pathNodes, roomId)
+	}
+	delete(pathZoneRooms, zone)
+}
+
+// Updates the cached exits of a room after it changes
+func updatePathCache(r *Room) {
+
+	if _, ok := pathNodes[r.RoomId]; !ok {
+		return
+	}
+
+	oldNode := pathNodes[r.RoomId]
+	if oldNode.zone != r.Zone {
+		ClearPathCache(oldNode.zone)
+		ClearPathCache(r.Zone)
+		return
+	}
+
+	pathNodes[r.RoomId] = newPathNode(r)
+}
+
+// Whether the path may use an exit out of a room
+func (o PathOptions) canUseExit(fromRoomId int, e pathExit) bool {
+
+	if e.secret && !o.SecretExits {
+		return false
+	}
+
+	// Doors in rooms that aren't in memory are closed
+	if e.door && !o.OpenDoors {
+		doorOpen := false
+		if r, ok := roomManager.rooms[fromRoomId]; ok {
+			if exitInfo, ok := r.GetExitInfo(e.name); ok {
+				doorOpen = exitInfo.Door.Open
+			}
+		}
+		if !doorOpen {
+			return false
+		}
+	}
+
+	if e.lockDifficulty == 0 {
+		return true
+	}
+
+	// Rooms that aren't in memory have all of their locks locked
+	lock := gamelock.Lock{Difficulty: e.lockDifficulty}
+	if r, ok := roomManager.rooms[fromRoomId]; ok {
+		if exitInfo, ok := r.GetExitInfo(e.name); ok {
+			lock = exitInfo.Lock
+		}
+	}
+
+	if !lock.IsLocked() {
+		return true
+	}
+
+	return o.CanUnlock != nil && o.CanUnlock(e.lockId, lock)
+}
+
+// Whether the path may enter a room
+func (o PathOptions) canEnter(node *pathNode) bool {
+
+	if o.Zone != `` && node.zone != o.Zone {
+		return false
+	}
+
+	if node.requiredItemId > 0 {
+		return o.HasItem != nil && o.HasItem(node.requiredItemId)
+	}
+
+	return true
+}
+
+// The exits of a room, including any added by its active mutators
+func pathExits(roomId int, node *pathNode) []pathExit {
+
+	r, ok := roomManager.rooms[roomId]
+	if !ok {
+		return node.exits
+	}
+
+	exits := node.exits
+	for mut := range r.ActiveMutators {
+		spec := mut.GetSpec()
+
+		mutExitNames := make([]string, 0, len(spec.Exits))
+		for exitName := range spec.Exits {
+			mutExitNames = append(mutExitNames, exitName)
+		}
+		sort.Strings(mutExitNames)
+
+		for _, exitName := range mutExitNames {
+			exitInfo := spec.Exits[exitName]
+			exits = append(exits, pathExit{
+				name:           exitName,
+				roomId:         exitInfo.RoomId,
+				secret:         exitInfo.Secret,
+				door:           exitInfo.HasDoor(),
+				lockId:         exitInfo.LockId(roomId, exitName),
+				lockDifficulty: exitInfo.Lock.Difficulty,
+			})
+		}
+	}
+
+	return exits
+}
+
+// Finds the shortest path between two rooms.
+// Returns an empty path if they are the same room.
+func FindPath(fromRoomId int, toRoomId int, opts PathOptions) (path []PathStep, found bool) {
+	return findPathWhere(fromRoomId, func(roomId int, _ *pathNode) bool { return roomId == toRoomId }, opts)
+}
+
+// Finds the nearest room with a map legend matching the landmark, such as "bank" or "inn".
+// If no room matches, the landmark can also be the name of a zone, which leads to its root room.
+// Returns the path and the room it leads to.
+func FindPathToLandmark(fromRoomId int, landmark string, opts PathOptions) (path []PathStep, roomId int, found bool) {
+
+	landmark = normalizeLandmark(landmark)
+	if landmark == `` {
+		return nil, 0, false
+	}
+
+	isLandmark := func(checkRoomId int, node *pathNode) bool {
+		if normalizeLandmark(node.legend) == landmark {
+			return true
+		}
+		return (landmark == `bank` && node.isBank) || (landmark == `storage` && node.isStorage)
+	}
+
+	if path, found = findPathWhere(fromRoomId, isLandmark, opts); found {
+		roomId = fromRoomId
+		if len(path) > 0 {
+			roomId = path[len(path)-1].RoomId
+		}
+		return path, roomId, true
+	}
+
+	zoneName := ``
+	for _, z := range GetAllZoneNames() {
+		if normalizeLandmark(z) == landmark {
+			zoneName = z
+			break
+		}
+	}
+	if zoneName == `` {
+		zoneName = FindZoneName(landmark)
+	}
+
+	if zoneName != `` {
+		if rootRoomId, err := GetZoneRoot(zoneName); err == nil {
+			if path, found = FindPath(fromRoomId, rootRoomId, opts); found {
+				return path, rootRoomId, true
+			}
+		}
+	}
+
+	return nil, 0, false
+}
+
+// Returns the landmarks in a zone that can be found with FindPathToLandmark()
+func GetLandmarks(zone string) []string {
+
+	if _, ok := pathZoneRooms[zone]; !ok {
+		cacheZonePaths(zone)
+	}
+
+	found := map[string]struct{}{}
+	for _, roomId := range pathZoneRooms[zone] {
+		node := pathNodes[roomId]
+		if node == nil {
+			continue
+		}
+		if node.legend != `` {
+			found[strings.ToLower(node.legend)] = struct{}{}
+		}
+		if node.isBank {
+			found[`bank`] = struct{}{}
+		}
+		if node.isStorage {
+			found[`storage`] = struct{}{}
+		}
+	}
+
+	landmarks := make([]string, 0, len(found))
+	for landmark := range found {
+		landmarks = append(landmarks, landmark)
+	}
+	sort.Strings(landmarks)
+
+	return landmarks
+}
+
+// Lowercases and treats dashes, underscores and spaces the same, so "East Gate" matches "East-Gate"
+func normalizeLandmark(s string) string {
+	s = strings.ToLower(strings.TrimSpace(s))
+	s = strings.ReplaceAll(s, `-`, ` `)
+	s = strings.ReplaceAll(s, `_`, ` `)
+	return s
+}
+
+// Breadth first search from a room to the nearest room that is a goal
+func findPathWhere(fromRoomId int, isGoal func(roomId int, node *pathNode) bool, opts PathOptions) ([]PathStep, bool) {
+
+	startNode := getPathNode(fromRoomId)
+	if startNode == nil {
+		return nil, false
+	}
+
+	if isGoal(fromRoomId, startNode) {
+		return []PathStep{}, true
+	}
+
+	type cameFrom struct {
+		roomId   int
+		exitName string
+		steps    int
+	}
+
+	visited := map[int]cameFrom{fromRoomId: {}}
+	queue := []int{fromRoomId}
+
+	for len(queue) > 0 && len(visited) < pathMaxRooms {
+
+		roomId := queue[0]
+		queue = queue[1:]
+
+		steps := visited[roomId].steps
+		if opts.MaxSteps > 0 && steps >= opts.MaxSteps {
+			continue
+		}
+
+		node := getPathNode(roomId)
+		if node == nil {
+			continue
+		}
+
+		for _, e := range pathExits(roomId, node) {
+
+			if _, ok := visited[e.roomId]; ok {
+				continue
+			}
+
+			if !opts.canUseExit(roomId, e) {
+				continue
+			}
+
+			nextNode := getPathNode(e.roomId)
+			if nextNode == nil || !opts.canEnter(nextNode) {
+				continue
+			}
+
+			visited[e.roomId] = cameFrom{roomId: roomId, exitName: e.name, steps: steps + 1}
+
+			if isGoal(e.roomId, nextNode) {
+
+				path := make([]PathStep, steps+1)
+				for stepRoomId := e.roomId; stepRoomId != fromRoomId; stepRoomId = visited[stepRoomId].roomId {
+					from := visited[stepRoomId]
+					path[from.steps-1] = PathStep{ExitName: from.exitName, RoomId: stepRoomId}
+				}
+
+				return path, true
+			}
+
+			queue = append(queue, e.roomId)
+		}
+	}
+
+	return nil, false
+}
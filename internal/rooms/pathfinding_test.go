@@ -0,0 +1,65 @@
+package rooms
+
+import (
+	"testing"
+
+	"github.com/volte6/gomud/internal/exit"
+	"github.com/volte6/gomud/internal/gamelock"
+)
+
+func TestFindPath(t *testing.T) {
+
+	zone := `Path Test`
+
+	// 1 -east-> 2 -east-> 3 -east-> 4 (a bank)
+	// 1 -secret-> 4, 1 -locked-> 4, 1 -south-> 5 (deep water) -east-> 4
+	r1 := newTestRoom(t, 900001, zone)
+	r2 := newTestRoom(t, 900002, zone)
+	r3 := newTestRoom(t, 900003, zone)
+	r4 := newTestRoom(t, 900004, zone)
+	r5 := newTestRoom(t, 900005, zone)
+	defer ClearPathCache(zone)
+
+	r1.Exits[`east`] = exit.RoomExit{RoomId: r2.RoomId}
+	r1.Exits[`hole`] = exit.RoomExit{RoomId: r4.RoomId, Secret: true}
+	r1.Exits[`gate`] = exit.RoomExit{RoomId: r4.RoomId, Lock: gamelock.Lock{Difficulty: 5}}
+	r1.Exits[`south`] = exit.RoomExit{RoomId: r5.RoomId}
+	r2.Exits[`east`] = exit.RoomExit{RoomId: r3.RoomId}
+	r3.Exits[`east`] = exit.RoomExit{RoomId: r4.RoomId}
+	r5.Exits[`east`] = exit.RoomExit{RoomId: r4.RoomId}
+	r5.Biome = `water`
+	r4.IsBank = true
+
+	ClearPathCache(zone)
+
+	tests := []struct {
+		name     string
+		opts     PathOptions
+		wantExit string
+		wantLen  int
+	}{
+		{`visible exits only`, PathOptions{}, `east`, 3},
+		{`secret exits`, PathOptions{SecretExits: true}, `hole`, 1},
+		{`unlockable`, PathOptions{CanUnlock: func(string, gamelock.Lock) bool { return true }}, `gate`, 1},
+		{`biome item`, PathOptions{HasItem: func(int) bool { return true }}, `south`, 2},
+	}
+
+	for _, tt := range tests {
+		path, found := FindPath(r1.RoomId, r4.RoomId, tt.opts)
+		if !found || len(path) != tt.wantLen || path[0].ExitName != tt.wantExit {
+			t.Errorf("%s: FindPath() = %v, %v, expected %d steps starting %s", tt.name, path, found, tt.wantLen, tt.wantExit)
+		}
+	}
+
+	if _, found := FindPath(r1.RoomId, r4.RoomId, PathOptions{MaxSteps: 2}); found {
+		t.Errorf("FindPath() found a path longer than MaxSteps")
+	}
+
+	if path, roomId, found := FindPathToLandmark(r1.RoomId, `Bank`, PathOptions{}); !found || roomId != r4.RoomId || len(path) != 3 {
+		t.Errorf("FindPathToLandmark(bank) = %v, %d, %v, expected 3 steps to %d", path, roomId, found, r4.RoomId)
+	}
+
+	if path, found := FindPath(r4.RoomId, r4.RoomId, PathOptions{}); !found || len(path) != 0 {
+		t.Errorf("FindPath() to the same room = %v, %v, expected an empty path", path, found)
+	}
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/volte6/gomud/internal/term"
 	"github.com/volte6/gomud/internal/users"
 	"github.com/volte6/gomud/internal/util"
+	"github.com/volte6/gomud/internal/weather"
 )
 
 type RoomTemplateDetails struct {
@@ -35,6 +36,7 @@ type RoomTemplateDetails struct {
 	IsDark         bool
 	IsNight        bool
 	IsBurning      bool
+	Weather        string // Description of the weather, if outside and it isn't clear
 	TrackingString string
 	RoomAlerts     []string // Messages to show below room description as a special alert
 	ShowPvp        bool     // Whether to display that the room is PVP
@@ -78,6 +80,7 @@ func GetDetails(r *Room, user *users.UserRecord) RoomTemplateDetails {
 		IsDark:         b.IsDark(),
 		IsNight:        gametime.IsNight(),
 		IsBurning:      r.IsBurning(),
+		Weather:        weather.GetInfo(r.GetWeather()).Description(),
 		TrackingString: ``,
 		ShowPvp:        showPvp,
 	}
@@ -17,6 +17,7 @@ type roomNode struct {
 	SecretExits map[string]struct{} // Just a flag for whether an exit key is secret
 	xPos        int                 // Its x position relative to the root node
 	yPos        int                 // Its y position relative to the root node
+	zPos        int                 // Its level relative to the root node. Up is positive.
 	Sprawl      int                 // how far from the start point this is
 	MobIds      []int               // all mob instance ids in this room
 	UserIds     []int               // all user ids in this room
@@ -34,6 +35,9 @@ type RoomGraph struct {
 	maxX            int                    // The largest tracked x position
 	minY            int                    // The smallest tracked y position
 	maxY            int                    // The largest tracked y position
+	minZ            int                    // The lowest tracked level
+	maxZ            int                    // The highest tracked level
+	level           int                    // The level Generate2DMap() draws, relative to the level of the center room
 	width           int                    // The width of the 2D map
 	height          int                    // The height of the 2D map
 	limitWidth      int                    // The maximum width at which we should stop crawling
@@ -63,6 +67,8 @@ type Map2DNode struct {
 	Legend      string // The name for this room on the legend (if needed)
 	Exits       []directionDelta
 	SecretExits []directionDelta
+	Up          bool // Whether it has an exit to the level above
+	Down        bool // Whether it has an exit to the level below
 }
 type Map1D []*Map2DNode
 type Map2D []Map1D
@@ -123,7 +129,15 @@ var (
 		"northeast-gap3": {3, -3, 0, ' '},
 		"southwest-gap3": {-3, 3, 0, ' '},
 		"southeast-gap3": {3, 3, 0, ' '},
+		// Exits to other levels aren't drawn as connections
+		"up":   {0, 0, 1, ' '},
+		"down": {0, 0, -1, ' '},
 	}
+
+	// Symbols that mark rooms with exits to other levels
+	upSymbol     = '▲'
+	downSymbol   = '▼'
+	upDownSymbol = '↕'
 )
 
 type MapMode uint8
@@ -254,6 +268,29 @@ func (r *RoomGraph) RoomIdsWithMobs() []int {
 	return allRoomIds
 }
 
+// Sets which level Generate2DMap() draws, relative to the level of the center room.
+// 1 is the level above, -1 the level below.
+func (r *RoomGraph) SetLevel(level int) {
+	r.level = level
+}
+
+func (r *RoomGraph) GetLevel() int {
+	return r.level
+}
+
+// Returns the lowest and highest levels found, relative to the root room
+func (r *RoomGraph) LevelRange() (minLevel int, maxLevel int) {
+	return r.minZ, r.maxZ
+}
+
+// Keeps the graph from crawling into any room that isn't listed, such as rooms in other zones.
+// Must be called before Build().
+func (r *RoomGraph) LimitToRooms(roomIds ...int) {
+	for _, roomId := range roomIds {
+		r.roomLimits[roomId] = struct{}{}
+	}
+}
+
 // Whatever the room normally shows, it will show this instead.
 func (r *RoomGraph) AddRoomSymbolOverrides(symbol rune, legend string, roomIds ...int) {
 	for _, roomId := range roomIds {
@@ -307,6 +344,8 @@ func (r *RoomGraph) Generate2DMap(mapMaxWidth int, mapMaxHeight int, centerRoomI
 		yTopPad = 0
 	}
 
+	level := centerRoom.zPos + r.level
+
 	// Create the 2D map
 	map2DResult = make(Map2D, mapMaxHeight)
 	for i := range map2DResult {
@@ -322,6 +361,10 @@ func (r *RoomGraph) Generate2DMap(mapMaxWidth int, mapMaxHeight int, centerRoomI
 		if r.maxSprawl > 0 && roomNode.Sprawl > r.maxSprawl {
 			continue
 		}
+		// Other levels would be drawn on top of this one
+		if roomNode.zPos != level {
+			continue
+		}
 		if boundaryCheck(roomNode.xPos, roomNode.yPos, xStart, xEnd, yStart, yEnd) {
 
 			symbol := roomNode.Symbol
@@ -347,12 +390,29 @@ func (r *RoomGraph) Generate2DMap(mapMaxWidth int, mapMaxHeight int, centerRoomI
 
 			for exitDirection, _ := range roomNode.Exits {
 				if directionDelta, ok := DirectionDeltas[exitDirection]; ok {
-					node2D.Exits = append(node2D.Exits, directionDelta)
+					if directionDelta.Dz > 0 {
+						node2D.Up = true
+					} else if directionDelta.Dz < 0 {
+						node2D.Down = true
+					} else {
+						node2D.Exits = append(node2D.Exits, directionDelta)
+					}
+				}
+			}
+
+			// Exits to other levels can't be drawn as connections, so the room is marked instead
+			if _, ok := r.forceRoomSymbol[roomNode.RoomId]; !ok {
+				if node2D.Up && node2D.Down {
+					node2D.Symbol, node2D.Legend = upDownSymbol, `Stairs`
+				} else if node2D.Up {
+					node2D.Symbol, node2D.Legend = upSymbol, `Up`
+				} else if node2D.Down {
+					node2D.Symbol, node2D.Legend = downSymbol, `Down`
 				}
 			}
 
 			for exitDirection, _ := range roomNode.SecretExits {
-				if directionDelta, ok := DirectionDeltas[exitDirection]; ok {
+				if directionDelta, ok := DirectionDeltas[exitDirection]; ok && directionDelta.Dz == 0 {
 					node2D.SecretExits = append(node2D.SecretExits, directionDelta)
 				}
 			}
@@ -396,6 +456,12 @@ func (r *RoomGraph) addNode(sourceRoomNode *roomNode, direction string, roomId i
 	// 3. If it has not, add the new room to the graph and add the exit to the new room linked to the new room node
 	//   Then return a list of new exits found in the new room, so that they can be added to the graph
 	//  This is to avoid a depth-first search, and instead stay breadth-first
+	if len(r.roomLimits) > 0 {
+		if _, ok := r.roomLimits[roomId]; !ok {
+			return nil
+		}
+	}
+
 	// Get the target room data
 	newRoomData := LoadRoom(roomId)
 
@@ -462,6 +528,7 @@ func (r *RoomGraph) addNode(sourceRoomNode *roomNode, direction string, roomId i
 	if exitDelta, ok := DirectionDeltas[direction]; ok {
 		newRoomNode.xPos += sourceRoomNode.xPos + exitDelta.Dx
 		newRoomNode.yPos += sourceRoomNode.yPos + exitDelta.Dy
+		newRoomNode.zPos += sourceRoomNode.zPos + exitDelta.Dz
 	}
 
 	// Mark it as tracked so that we don't recurse into it again
@@ -488,6 +555,12 @@ func (r *RoomGraph) addNode(sourceRoomNode *roomNode, direction string, roomId i
 		r.maxY = newRoomNode.yPos
 	}
 
+	if newRoomNode.zPos < r.minZ {
+		r.minZ = newRoomNode.zPos
+	} else if newRoomNode.zPos > r.maxZ {
+		r.maxZ = newRoomNode.zPos
+	}
+
 	// Calculate╱set the new width╱height
 	r.width = r.maxX - r.minX + 1
 	r.height = r.maxY - r.minY + 1
@@ -617,6 +690,10 @@ func (r *RoomGraph) Build(rootRoomId int, overrideRoomIdSymbols map[int]rune) er
 		// Possibly override the symbol for this room
 		if overrideRoomIdSymbols != nil && overrideRoomIdSymbols[nextRoomExit.roomNode.RoomId] != 0 {
 			nextRoomExit.roomNode.Symbol = overrideRoomIdSymbols[nextRoomExit.roomNode.RoomId]
+			// Keeps it from being replaced by a stairs marker
+			if _, ok := r.forceRoomSymbol[nextRoomExit.roomNode.RoomId]; !ok {
+				r.forceRoomSymbol[nextRoomExit.roomNode.RoomId] = symbolOverride{Symbol: nextRoomExit.roomNode.Symbol, Legend: nextRoomExit.roomNode.Legend}
+			}
 		}
 
 		for directionName, roomExit := range nextRoomExit.exits {
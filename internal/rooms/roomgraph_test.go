@@ -0,0 +1,60 @@
+package rooms
+
+import (
+	"testing"
+
+	"github.com/volte6/gomud/internal/exit"
+)
+
+func TestGenerate2DMap_Levels(t *testing.T) {
+
+	zone := `Level Test`
+
+	// 1 -east-> 2, 1 -up-> 3 (directly above 1), 3 -east-> 4
+	r1 := newTestRoom(t, 900101, zone)
+	r2 := newTestRoom(t, 900102, zone)
+	r3 := newTestRoom(t, 900103, zone)
+	r4 := newTestRoom(t, 900104, zone)
+
+	r1.Exits[`east`] = exit.RoomExit{RoomId: r2.RoomId}
+	r1.Exits[`stairs`] = exit.RoomExit{RoomId: r3.RoomId, MapDirection: `up`}
+	r3.Exits[`down`] = exit.RoomExit{RoomId: r1.RoomId}
+	r3.Exits[`east`] = exit.RoomExit{RoomId: r4.RoomId}
+
+	rGraph := NewRoomGraph(100, 100, 0, MapModeAll)
+	if err := rGraph.Build(r1.RoomId, nil); err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	if minLevel, maxLevel := rGraph.LevelRange(); minLevel != 0 || maxLevel != 1 {
+		t.Errorf("LevelRange() = %d, %d, expected 0, 1", minLevel, maxLevel)
+	}
+
+	mapRoomIds := func() map[int]*Map2DNode {
+		map2D, _, _ := rGraph.Generate2DMap(0, 0, r1.RoomId)
+		found := map[int]*Map2DNode{}
+		for _, row := range map2D {
+			for _, node := range row {
+				if node != nil {
+					found[node.RoomId] = node
+				}
+			}
+		}
+		return found
+	}
+
+	ground := mapRoomIds()
+	if len(ground) != 2 || ground[r1.RoomId] == nil || ground[r2.RoomId] == nil {
+		t.Errorf("Generate2DMap() on level 0 = %v, expected rooms %d and %d", ground, r1.RoomId, r2.RoomId)
+	} else if !ground[r1.RoomId].Up || ground[r1.RoomId].Symbol != upSymbol {
+		t.Errorf("Generate2DMap() room %d Up = %v, Symbol = %c, expected an up marker", r1.RoomId, ground[r1.RoomId].Up, ground[r1.RoomId].Symbol)
+	}
+
+	rGraph.SetLevel(1)
+	above := mapRoomIds()
+	if len(above) != 2 || above[r3.RoomId] == nil || above[r4.RoomId] == nil {
+		t.Errorf("Generate2DMap() on level 1 = %v, expected rooms %d and %d", above, r3.RoomId, r4.RoomId)
+	} else if !above[r3.RoomId].Down {
+		t.Errorf("Generate2DMap() room %d Down = false, expected true", r3.RoomId)
+	}
+}
@@ -48,6 +48,7 @@ type RoomManager struct {
 	topRoomItems         []int               // list of the top room items
 	roomDescriptionCache map[string]string   // key is a hash, value is the description
 	roomIdToFileCache    map[int]string      // key is room id, value is the file path
+	roomsWithTimers      []int               // rooms that had script timers saved when the server started
 }
 
 const (
@@ -57,10 +58,11 @@ const (
 )
 
 type ZoneInfo struct {
-	RootRoomId      int
-	DefaultBiome    string // city, swamp etc. see biomes.go
-	HasZoneMutators bool   // does it have any zone mutators assigned?
-	RoomIds         map[int]struct{}
+	RootRoomId       int
+	DefaultBiome     string // city, swamp etc. see biomes.go
+	HasZoneMutators  bool   // does it have any zone mutators assigned?
+	HasResetSchedule bool   // does it reset on a schedule?
+	RoomIds          map[int]struct{}
 }
 
 func GetNextRoomId() int {
@@ -218,6 +220,11 @@ func RoomMaintenance() bool {
 			continue
 		}
 
+		// Instanced rooms are removed along with their instance, and houses along with their owner's house
+		if room.instanceId > 0 || room.houseOwnerId > 0 {
+			continue
+		}
+
 		// Consider unloading rooms from memory?
 		if roundCount%roomUnloadTimeoutRounds == 0 {
 			if room.lastVisited < unloadRoundThreshold {
@@ -234,9 +241,26 @@ func RoomMaintenance() bool {
 		roomsUpdated = true
 	}
 
+	if instanceMaintenance() {
+		roomsUpdated = true
+	}
+
+	if zoneResetMaintenance() {
+		roomsUpdated = true
+	}
+
+	if houseMaintenance() {
+		roomsUpdated = true
+	}
+
 	return roomsUpdated
 }
 
+// Returns the rooms that had script timers saved when the server started
+func GetRoomsWithScriptTimers() []int {
+	return append([]int{}, roomManager.roomsWithTimers...)
+}
+
 func GetAllZoneNames() []string {
 
 	var zoneNames []string = make([]string, len(roomManager.zones))
@@ -285,6 +309,20 @@ func MoveToRoom(userId int, toRoomId int, isSpawn ...bool) error {
 		return fmt.Errorf(`room %d not found`, toRoomId)
 	}
 
+	// Instances only let in the party they belong to, though anyone already inside can move around
+	if newRoom.instanceId > 0 && newRoom.instanceId != currentRoom.instanceId && user.Permission != users.PermissionAdmin {
+		if !CanEnterRoom(userId, newRoom.RoomId) {
+			return fmt.Errorf(`room %d is part of an instance belonging to another party`, toRoomId)
+		}
+	}
+
+	// Houses only let in their owners and guests
+	if newRoom.houseOwnerId > 0 && user.Permission != users.PermissionAdmin {
+		if !CanEnterRoom(userId, newRoom.RoomId) {
+			return fmt.Errorf(`room %d is a house belonging to someone else`, toRoomId)
+		}
+	}
+
 	// r.prepare locks, so do it before the upcoming lock
 	if len(newRoom.players) == 0 {
 		newRoom.Prepare(true)
@@ -326,6 +364,27 @@ func MoveToRoom(userId int, toRoomId int, isSpawn ...bool) error {
 	user.Character.Zone = newRoom.Zone
 	user.Character.RememberRoom(newRoom.RoomId) // Mark this room as remembered.
 
+	// Remember where the instance or house was entered from, in case it is gone when they next log in
+	if inst := instances[newRoom.instanceId]; inst != nil {
+		if currentRoom.instanceId != newRoom.instanceId {
+			user.Character.SetMiscData(`InstanceEntrance`, inst.EntranceRoomId)
+		}
+	} else if newRoom.houseOwnerId > 0 {
+		user.Character.SetMiscData(`InstanceEntrance`, newRoom.sourceRoomId)
+	} else {
+		user.Character.SetMiscData(`InstanceEntrance`, nil)
+	}
+
+	if formerRoomId != newRoom.RoomId {
+		events.AddToQueue(events.RoomChange{
+			UserId:     userId,
+			FromRoomId: formerRoomId,
+			ToRoomId:   newRoom.RoomId,
+			FromZone:   currentRoom.Zone,
+			ToZone:     newRoom.Zone,
+		})
+	}
+
 	roundNow := util.GetRoundCount()
 
 	if user.Character.Level < 5 && toRoomId > -1 {
@@ -613,9 +672,19 @@ func SaveAllRooms() error {
 		saveModes = append(saveModes, fileloader.SaveCareful)
 	}
 
-	saveCt, err := fileloader.SaveAllFlatFiles[int, *Room](roomDataFilesPath, roomManager.rooms, saveModes...)
+	// Instanced rooms are never saved, and houses are saved to their own files
+	saveRooms := make(map[int]*Room, len(roomManager.rooms))
+	for roomId, loadedRoom := range roomManager.rooms {
+		if loadedRoom.instanceId == 0 && loadedRoom.houseOwnerId == 0 {
+			saveRooms[roomId] = loadedRoom
+		}
+	}
+
+	saveAllHouses()
 
-	slog.Info("SaveAllRooms()", "savedCount", saveCt, "expectedCt", len(roomManager.rooms), "Time Taken", time.Since(start))
+	saveCt, err := fileloader.SaveAllFlatFiles[int, *Room](roomDataFilesPath, saveRooms, saveModes...)
+
+	slog.Info("SaveAllRooms()", "savedCount", saveCt, "expectedCt", len(saveRooms), "Time Taken", time.Since(start))
 
 	return err
 }
@@ -676,6 +745,10 @@ func loadAllRoomZones() error {
 		// Cache the file path for every roomId
 		roomManager.roomIdToFileCache[loadedRoom.RoomId] = loadedRoom.Filepath()
 
+		if len(loadedRoom.ScriptTimers) > 0 {
+			roomManager.roomsWithTimers = append(roomManager.roomsWithTimers, loadedRoom.RoomId)
+		}
+
 		// Update the zone info cache
 		if _, ok := roomManager.zones[loadedRoom.Zone]; !ok {
 			roomManager.zones[loadedRoom.Zone] = ZoneInfo{
@@ -695,6 +768,10 @@ func loadAllRoomZones() error {
 			if len(loadedRoom.ZoneConfig.Mutators) > 0 {
 				zoneInfo.HasZoneMutators = true
 			}
+
+			if loadedRoom.ZoneConfig.Reset.Interval != `` {
+				zoneInfo.HasResetSchedule = true
+			}
 		}
 
 		roomManager.zones[loadedRoom.Zone] = zoneInfo
@@ -705,6 +782,35 @@ func loadAllRoomZones() error {
 	return nil
 }
 
+// Creates an empty room that only exists in memory.
+// Unlike NewRoom(), the room id is chosen by the caller and isn't reserved, so the room should never be saved.
+// Used for script test fixtures.
+func NewTemporaryRoom(roomId int, zone string) *Room {
+
+	r := &Room{
+		RoomId:        roomId,
+		Zone:          zone,
+		Title:         "An empty room.",
+		Description:   "This is an empty room that was never given a description.",
+		Exits:         make(map[string]exit.RoomExit),
+		Effects:       map[EffectType]AreaEffect{},
+		players:       []int{},
+		visitors:      make(map[VisitorType]map[int]uint64),
+		tempDataStore: make(map[string]any),
+	}
+
+	roomManager.rooms[r.RoomId] = r
+
+	zoneInfo, ok := roomManager.zones[r.Zone]
+	if !ok {
+		zoneInfo = ZoneInfo{RootRoomId: r.RoomId, RoomIds: make(map[int]struct{})}
+	}
+	zoneInfo.RoomIds[r.RoomId] = struct{}{}
+	roomManager.zones[r.Zone] = zoneInfo
+
+	return r
+}
+
 // Saves a room to disk and unloads it from memory
 func removeRoomFromMemory(r *Room) {
 
@@ -714,7 +820,7 @@ func removeRoomFromMemory(r *Room) {
 		return
 	}
 
-	if len(room.players) > 0 {
+	if len(room.players) > 0 || room.instanceId > 0 || room.houseOwnerId > 0 {
 		return
 	}
 
@@ -828,6 +934,8 @@ func loadRoomFromFile(roomFilePath string) (*Room, error) {
 
 	addRoomToMemory(roomPtr)
 
+	roomPtr.syncDoors()
+
 	return roomPtr, err
 }
 
@@ -875,6 +983,16 @@ func LoadRoom(roomId int) *Room {
 		return room
 	}
 
+	// Houses are built from the room they were bought from
+	if roomId >= houseRoomIdStart {
+		return loadHouseRoom(roomId)
+	}
+
+	// Instanced rooms only exist in memory, so once the instance is gone there is nothing to load
+	if roomId >= instanceRoomIdStart {
+		return nil
+	}
+
 	filename := findRoomFile(roomId)
 	retRoom, _ := loadRoomFromFile(util.FilePath(roomDataFilesPath, `/`, filename))
 
@@ -883,6 +1001,19 @@ func LoadRoom(roomId int) *Room {
 
 func SaveRoom(r Room) error {
 
+	// Instanced rooms are thrown away with their instance
+	if r.instanceId > 0 {
+		return nil
+	}
+
+	// Houses are saved to their own files
+	if r.houseOwnerId > 0 {
+		if h := houses[r.houseOwnerId]; h != nil {
+			return saveHouse(h)
+		}
+		return nil
+	}
+
 	if strings.HasPrefix(r.Description, `h:`) {
 		hash := strings.TrimPrefix(r.Description, `h:`)
 		if description, ok := roomManager.roomDescriptionCache[hash]; ok {
@@ -890,6 +1021,8 @@ func SaveRoom(r Room) error {
 		}
 	}
 
+	updatePathCache(&r)
+
 	data, err := yaml.Marshal(&r)
 	if err != nil {
 		return err
@@ -927,6 +1060,11 @@ func ZoneNameSanitize(zone string) string {
 	return strings.ToLower(zone)
 }
 
+// Returns the path to the optional script shared by every room in a zone
+func GetZoneScriptPath(zone string) string {
+	return roomDataFilesPath + `/` + zoneToFolder(zone) + `zone.js`
+}
+
 func zoneToFolder(zone string) string {
 	zone = ZoneNameSanitize(zone)
 	// Lowercase it all, and add a slash at the end
@@ -969,6 +1107,58 @@ func GetZoneBiome(zone string) string {
 	return ``
 }
 
+// Returns all room id's that belong to a zone
+func GetZoneRoomIds(zone string) []int {
+
+	zoneInfo, ok := roomManager.zones[zone]
+	if !ok {
+		return []int{}
+	}
+
+	roomIds := make([]int, 0, len(zoneInfo.RoomIds))
+	for roomId := range zoneInfo.RoomIds {
+		roomIds = append(roomIds, roomId)
+	}
+
+	return roomIds
+}
+
+// Adds a mutator to the zone config of a zone.
+// Returns false if the zone or mutator couldn't be found.
+func AddZoneMutator(zone string, mutatorId string) bool {
+
+	zoneInfo, ok := roomManager.zones[zone]
+	if !ok {
+		return false
+	}
+
+	zoneConfig := GetZoneConfig(zone)
+	if zoneConfig == nil {
+		return false
+	}
+
+	if !zoneConfig.Mutators.Add(mutatorId) {
+		return false
+	}
+
+	zoneInfo.HasZoneMutators = true
+	roomManager.zones[zone] = zoneInfo
+
+	return true
+}
+
+// Removes a mutator from the zone config of a zone.
+// Returns false if the zone couldn't be found or the mutator wasn't live.
+func RemoveZoneMutator(zone string, mutatorId string) bool {
+
+	zoneConfig := GetZoneConfig(zone)
+	if zoneConfig == nil {
+		return false
+	}
+
+	return zoneConfig.Mutators.Remove(mutatorId)
+}
+
 func MoveToZone(roomId int, newZoneName string) error {
 
 	room, ok := roomManager.rooms[roomId]
@@ -1145,7 +1335,7 @@ func ConnectRoom(fromRoomId int, toRoomId int, exitName string, mapDirection ...
 }
 
 func GetMapForDataString(dataStr string) string {
-	// roomid:[1]/size:[wide/normal]/secrets:false/height:[18]/name:[Map of Frostfang]
+	// roomid:[1]/size:[wide/normal]/secrets:false/height:[18]/name:[Map of Frostfang]/level:[0]
 	mapProperties := map[string]string{
 		`roomid`:  ``,
 		`size`:    `normal`, // wide?
@@ -1154,6 +1344,7 @@ func GetMapForDataString(dataStr string) string {
 		`name`:    `A useful map`,
 		`secrets`: `false`,
 		`markers`: ``,
+		`level`:   `0`,
 	}
 	mapDetails := strings.Split(dataStr, `/`)
 
@@ -1174,10 +1365,11 @@ func GetMapForDataString(dataStr string) string {
 		mapWidth, _ := strconv.Atoi(mapProperties[`width`])
 		mapName := mapProperties[`name`]
 		showAll, _ := strconv.ParseBool(mapProperties[`secrets`])
+		mapLevel, _ := strconv.Atoi(mapProperties[`level`])
 
 		mapMarkers := []string{mapProperties[`markers`]}
 
-		return GetSpecificMap(mapRoomId, mapSize, mapHeight, mapWidth, mapName, showAll, mapMarkers)
+		return GetSpecificMap(mapRoomId, mapSize, mapHeight, mapWidth, mapName, showAll, mapLevel, mapMarkers)
 
 	}
 	return ""
@@ -1288,7 +1480,8 @@ func GetTinyMap(mapRoomId int) []string {
 	return returnResult
 }
 
-func GetSpecificMap(mapRoomId int, mapSize string, mapHeight int, mapWidth int, mapName string, showSecrets bool, mapMarkers []string) string {
+// mapLevel is the level to draw, relative to the level of mapRoomId. 1 is the level above.
+func GetSpecificMap(mapRoomId int, mapSize string, mapHeight int, mapWidth int, mapName string, showSecrets bool, mapLevel int, mapMarkers []string) string {
 
 	mapMode := MapModeAllButSecrets
 	if showSecrets {
@@ -1319,6 +1512,8 @@ func GetSpecificMap(mapRoomId int, mapSize string, mapHeight int, mapWidth int,
 				}
 			}
 
+			rGraph.SetLevel(mapLevel)
+
 			mapData, err = DrawZoneMap(rGraph, mapName, mapWidth, mapHeight)
 		} else {
 
@@ -1339,6 +1534,8 @@ func GetSpecificMap(mapRoomId int, mapSize string, mapHeight int, mapWidth int,
 				}
 			}
 
+			rGraph.SetLevel(mapLevel)
+
 			mapData, err = DrawZoneMap(rGraph, mapName, mapWidth, mapHeight)
 		}
 
@@ -1378,4 +1575,6 @@ func LoadDataFiles() {
 		panic(err)
 	}
 
+	loadHouses()
+
 }
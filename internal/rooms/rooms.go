@@ -18,6 +18,7 @@ import (
 	"github.com/volte6/gomud/internal/mutators"
 	"github.com/volte6/gomud/internal/users"
 	"github.com/volte6/gomud/internal/util"
+	"github.com/volte6/gomud/internal/weather"
 )
 
 const roomDataFilesPath = "_datafiles/rooms"
@@ -62,6 +63,14 @@ const (
 	VisitorMob  = "mob"
 )
 
+// A timer created by a room script that is saved with the room
+type ScriptTimer struct {
+	Function       string `yaml:"function"`                 // Name of the script function to call
+	Args           []any  `yaml:"args,omitempty,flow"`      // Arguments to pass to the function
+	DueRound       uint64 `yaml:"dueround"`                 // Round the function should be called on
+	IntervalRounds int    `yaml:"intervalrounds,omitempty"` // If set, repeats every this many rounds
+}
+
 type Room struct {
 	//mutex
 	RoomId            int        // a unique numeric index of the room. Also the filename.
@@ -70,11 +79,13 @@ type Room struct {
 	IsBank            bool       `yaml:"isbank,omitempty"`          // Is this a bank room? If so, players can deposit/withdraw gold here.
 	IsStorage         bool       `yaml:"isstorage,omitempty"`       // Is this a storage room? If so, players can add/remove objects here.
 	IsCharacterRoom   bool       `yaml:"ischaracterroom,omitempty"` // Is this a room where characters can create new characters to swap between them?
+	Housing           Housing    `yaml:"housing,omitempty"`         // If it has a price, players can buy their own copy of this room as a house.
 	Title             string
 	Description       string
 	MapSymbol         string               `yaml:"mapsymbol,omitempty"`  // The symbol to use when generating a map of the zone
 	MapLegend         string               `yaml:"maplegend,omitempty"`  // The text to display in the legend for this room. Should be one word.
 	Biome             string               `yaml:"biome,omitempty"`      // The biome of the room. Used for weather generation.
+	MoveCost          int                  `yaml:"movecost,omitempty"`   // Action points it costs to move into this room. 0 uses the biome cost.
 	Containers        map[string]Container `yaml:"containers,omitempty"` // If this room has a chest, what is in it?
 	Exits             map[string]exit.RoomExit
 	ExitsTemp         map[string]exit.TemporaryRoomExit `yaml:"-"`               // Temporary exits that will be removed after a certain time. Don't bother saving on sever shutting down.
@@ -88,6 +99,7 @@ type Room struct {
 	IdleMessages      []string                          `yaml:"idlemessages,omitempty"`      // list of messages that can be displayed to players in the room
 	LastIdleMessage   uint8                             `yaml:"-"`                           // index of the last idle message displayed
 	LongTermDataStore map[string]any                    `yaml:"longtermdatastore,omitempty"` // Long term data store for the room
+	ScriptTimers      []ScriptTimer                     `yaml:"scripttimers,omitempty"`      // Script timers that should survive a restart
 	Mutators          mutators.MutatorList              `yaml:"mutators,omitempty"`          // mutators this room spawns with.
 	Effects           map[EffectType]AreaEffect         `yaml:"-"`
 	Pvp               bool                              `yaml:"pvp,omitempty"` // config pvp is set to `limited`, uses this value
@@ -96,6 +108,9 @@ type Room struct {
 	visitors          map[VisitorType]map[int]uint64    `yaml:"-"`             // list of user IDs that have visited this room, and the last round they did
 	lastVisited       uint64                            `yaml:"-"`             // last round a visitor was in the room
 	tempDataStore     map[string]any                    `yaml:"-"`             // Temporary data store for the room
+	instanceId        int                               `yaml:"-"`             // If this room is part of an instance, which one
+	sourceRoomId      int                               `yaml:"-"`             // If this room is part of an instance or is a house, the room it was copied from
+	houseOwnerId      int                               `yaml:"-"`             // If this room is a house, the user id of its owner
 }
 
 type TrainingRange struct {
@@ -145,6 +160,11 @@ func (r *Room) GetVisibility() int {
 		}
 	}
 
+	// Fog and storms make it harder to see outside
+	if r.IsOutside() {
+		visibility += weather.GetInfo(r.GetWeather()).VisibilityMod()
+	}
+
 	// Apply any mutators
 	for mut := range r.ActiveMutators {
 		spec := mut.GetSpec()
@@ -259,6 +279,21 @@ func (r *Room) GetLongTermData(key string) any {
 	return nil
 }
 
+// Returns the spawn info that a mob instance was spawned from, if any
+func (r *Room) GetSpawnInfo(mobInstanceId int) *SpawnInfo {
+
+	if mobInstanceId < 1 {
+		return nil
+	}
+
+	for idx := range r.SpawnInfo {
+		if r.SpawnInfo[idx].InstanceId == mobInstanceId {
+			return &r.SpawnInfo[idx]
+		}
+	}
+	return nil
+}
+
 func (r *Room) SetTempData(key string, value any) {
 
 	if r.tempDataStore == nil {
@@ -300,8 +335,15 @@ func (r *Room) GetScript() string {
 
 func (r *Room) GetScriptPath() string {
 
+	roomFilepath := r.Filepath()
+
+	// Instanced rooms run their own copy of the script of the room they were copied from
+	if r.sourceRoomId > 0 {
+		roomFilepath = util.FilePath(ZoneNameSanitize(r.Zone), `/`, fmt.Sprintf("%d.yaml", r.sourceRoomId))
+	}
+
 	// Load any script for the room
-	return strings.Replace(roomDataFilesPath+`/`+r.Filepath(), `.yaml`, `.js`, 1)
+	return strings.Replace(roomDataFilesPath+`/`+roomFilepath, `.yaml`, `.js`, 1)
 }
 
 func (r *Room) FindTemporaryExitByUserId(userId int) (exit.TemporaryRoomExit, bool) {
@@ -512,7 +554,7 @@ func (r *Room) Prepare(checkAdjacentRooms bool) {
 			} else {
 
 				// Get the zone settings, check for scaling
-				if zConfig := GetZoneConfig(r.Zone); zConfig != nil {
+				if zConfig := r.getZoneConfig(); zConfig != nil {
 
 					if zConfig.MobAutoScale.Minimum > 0 {
 						forceLevel = zConfig.GenerateRandomLevel()
@@ -741,6 +783,14 @@ func (r *Room) SetExitLock(exitName string, locked bool) {
 		}
 		r.Exits[exitName] = exitInfo
 
+		// Both sides of a door share a lock
+		if otherRoom, otherExitName := r.GetDoorPair(exitName); otherRoom != nil {
+			if otherExit := otherRoom.Exits[otherExitName]; otherExit.HasLock() {
+				otherExit.Lock.UnlockedRound = exitInfo.Lock.UnlockedRound
+				otherRoom.Exits[otherExitName] = otherExit
+			}
+		}
+
 	} else {
 		for mut := range r.ActiveMutators {
 			spec := mut.GetSpec()
@@ -785,11 +835,11 @@ func (r *Room) GetRandomExit() (exitName string, roomId int) {
 		if exit.Secret {
 			continue
 		}
-		if exit.Lock.IsLocked() {
+		if exit.IsClosed() {
 			continue
 		}
 
-		allExits[exitName] = roomId
+		allExits[exitName] = exit.RoomId
 	}
 
 	for mut := range r.ActiveMutators {
@@ -798,10 +848,10 @@ func (r *Room) GetRandomExit() (exitName string, roomId int) {
 			if exit.Secret {
 				continue
 			}
-			if exit.Lock.IsLocked() {
+			if exit.IsClosed() {
 				continue
 			}
-			allExits[exitName] = roomId
+			allExits[exitName] = exit.RoomId
 		}
 	}
 
@@ -837,6 +887,23 @@ func (r *Room) RemoveItem(i items.Item, stash bool) {
 
 }
 
+// Replaces an item on the floor or in the stash with an updated copy of it
+func (r *Room) UpdateItem(originalItm items.Item, replacement items.Item) bool {
+	for j := len(r.Items) - 1; j >= 0; j-- {
+		if r.Items[j].Equals(originalItm) {
+			r.Items[j] = replacement
+			return true
+		}
+	}
+	for j := len(r.Stash) - 1; j >= 0; j-- {
+		if r.Stash[j].Equals(originalItm) {
+			r.Stash[j] = replacement
+			return true
+		}
+	}
+	return false
+}
+
 func (r *Room) GetAllFloorItems(stash bool) []items.Item {
 
 	found := []items.Item{}
@@ -2036,6 +2103,16 @@ func (r *Room) Validate() error {
 		}
 	}
 
+	if r.MoveCost < 0 || r.MoveCost > MaxMoveCost {
+		return fmt.Errorf("movecost must be between 0 and %d: %d", MaxMoveCost, r.MoveCost)
+	}
+
+	if r.Housing.RentPeriod != `` {
+		if err := gametime.ValidatePeriod(r.Housing.RentPeriod, true); err != nil {
+			return fmt.Errorf("invalid housing rent period: %w", err)
+		}
+	}
+
 	// Make sure all items are validated (and have uids)
 	for i := range r.Items {
 		r.Items[i].Validate()
@@ -2056,7 +2133,9 @@ func (r *Room) Validate() error {
 		r.ZoneConfig = ZoneConfig{}
 	} else {
 
-		r.ZoneConfig.Validate()
+		if err := r.ZoneConfig.Validate(); err != nil {
+			return err
+		}
 
 	}
 
@@ -2079,6 +2158,14 @@ func (r *Room) Filepath() string {
 	return util.FilePath(zone, `/`, fmt.Sprintf("%d.yaml", r.RoomId))
 }
 
+// Returns the action points it costs to move into this room
+func (r *Room) GetMoveCost() int {
+	if r.MoveCost > 0 {
+		return r.MoveCost
+	}
+	return r.GetBiome().MoveCost()
+}
+
 func (r *Room) GetBiome() BiomeInfo {
 
 	if r.Biome == `` {
@@ -2092,10 +2179,23 @@ func (r *Room) GetBiome() BiomeInfo {
 	return bInfo
 }
 
+// Whether the room is out in the weather
+func (r *Room) IsOutside() bool {
+	return !r.GetBiome().IsSheltered()
+}
+
+// Returns the weather in the room, or an empty string if it is sheltered
+func (r *Room) GetWeather() weather.Weather {
+	if !r.IsOutside() {
+		return ``
+	}
+	return weather.Get(r.Zone)
+}
+
 func (r *Room) ActiveMutators(yield func(mutators.Mutator) bool) {
 
 	var activeMutators mutators.MutatorList
-	if zoneConfig := GetZoneConfig(r.Zone); zoneConfig != nil {
+	if zoneConfig := r.getZoneConfig(); zoneConfig != nil {
 		activeMutators = append(r.Mutators.GetActive(), zoneConfig.Mutators.GetActive()...)
 	}
 
@@ -0,0 +1,35 @@
+package rooms
+
+import (
+	"testing"
+
+	"github.com/volte6/gomud/internal/exit"
+)
+
+// Creates an empty room for a test, which is removed along with its zone once the test is done
+func newTestRoom(t *testing.T, roomId int, zone string) *Room {
+	t.Helper()
+
+	r := NewTemporaryRoom(roomId, zone)
+	t.Cleanup(func() {
+		delete(roomManager.rooms, roomId)
+		delete(roomManager.zones, zone)
+	})
+
+	return r
+}
+
+func TestGetRandomExit(t *testing.T) {
+
+	zone := `Random Exit Test`
+
+	from := newTestRoom(t, 900501, zone)
+	to := newTestRoom(t, 900502, zone)
+
+	from.Exits[`east`] = exit.RoomExit{RoomId: to.RoomId}
+	from.Exits[`hidden`] = exit.RoomExit{RoomId: to.RoomId, Secret: true}
+
+	if exitName, roomId := from.GetRandomExit(); exitName != `east` || roomId != to.RoomId {
+		t.Errorf("GetRandomExit() = %s, %d, expected east, %d", exitName, roomId, to.RoomId)
+	}
+}
@@ -1,6 +1,10 @@
 package rooms
 
 import (
+	"fmt"
+	"strings"
+
+	"github.com/volte6/gomud/internal/gametime"
 	"github.com/volte6/gomud/internal/mutators"
 	"github.com/volte6/gomud/internal/util"
 )
@@ -12,9 +16,33 @@ type ZoneConfig struct {
 		Maximum int `yaml:"maximum,omitempty"` // level scaling maximum
 	} `yaml:"autoscale,omitempty"` // level scaling range if any
 	Mutators mutators.MutatorList `yaml:"mutators,omitempty"` // mutators defined here apply to entire zone
+	Reset    ZoneReset            `yaml:"reset,omitempty"`    // optional schedule for resetting the entire zone
+}
+
+// A schedule for putting a zone back the way it was built
+type ZoneReset struct {
+	Interval  string   `yaml:"interval,omitempty"`  // How often it resets, such as "30 real minutes" or "1 day". Empty means never.
+	Condition string   `yaml:"condition,omitempty"` // ResetWhenEmpty or ResetAlways. Empty is the same as ResetWhenEmpty.
+	Messages  []string `yaml:"messages,omitempty"`  // One is picked at random and sent to players in the zone when it resets
 }
 
-func (z *ZoneConfig) Validate() {
+const (
+	ResetWhenEmpty = `empty`  // Only reset when no players are in the zone
+	ResetAlways    = `always` // Reset even with players in the zone
+)
+
+func (z *ZoneConfig) Validate() error {
+	if z.Reset.Interval != `` {
+		if err := gametime.ValidatePeriod(z.Reset.Interval, true); err != nil {
+			return fmt.Errorf("invalid zone reset interval: %w", err)
+		}
+	}
+
+	z.Reset.Condition = strings.ToLower(z.Reset.Condition)
+	if z.Reset.Condition != ResetAlways && z.Reset.Condition != ResetWhenEmpty {
+		z.Reset.Condition = ``
+	}
+
 	if z.MobAutoScale.Minimum < 0 {
 		z.MobAutoScale.Minimum = 0
 	}
@@ -35,6 +63,7 @@ func (z *ZoneConfig) Validate() {
 		}
 	}
 
+	return nil
 }
 
 // Generates a random number between min and max
@@ -0,0 +1,332 @@
+package rooms
+
+import (
+	"fmt"
+	"html"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// Sizes in pixels for SVG exports
+	svgCellWidth   = 110 // Space given to each map position
+	svgCellHeight  = 70
+	svgRoomWidth   = 84
+	svgRoomHeight  = 36
+	svgMargin      = 20
+	svgTitleHeight = 30 // Space above each level for its title
+	svgStubLength  = 30 // Length of exits that lead out of the zone
+	svgTitleChars  = 14 // Room titles are cut to this many characters
+)
+
+var (
+	// Fill colors of rooms in exports, by biome
+	exportBiomeColors = map[string]string{
+		`city`:      `#e6e6e6`,
+		`fort`:      `#d9d2c3`,
+		`road`:      `#e8dfcc`,
+		`house`:     `#f2dcc0`,
+		`shore`:     `#d6ebf7`,
+		`water`:     `#a9d1f2`,
+		`forest`:    `#b9dcb0`,
+		`mountains`: `#d1c8b8`,
+		`cliffs`:    `#c6beae`,
+		`swamp`:     `#b9c9a1`,
+		`snow`:      `#fafafa`,
+		`spiderweb`: `#cfcfcf`,
+		`cave`:      `#b3aba3`,
+		`desert`:    `#f4e3b1`,
+		`farmland`:  `#e6efb3`,
+	}
+	exportDefaultColor = `#ffffff`
+)
+
+// Returns the rooms and exits of a zone as a Graphviz DOT graph.
+// Secret exits are dashed and locked exits are red. Rooms in other zones that exits lead to are dashed,
+// and rooms that don't exist are red, so broken links stand out.
+// Temporary exits aren't included.
+func ExportZoneDOT(zone string) (string, error) {
+
+	roomIds := GetZoneRoomIds(zone)
+	if len(roomIds) == 0 {
+		return ``, fmt.Errorf(`zone not found: %s`, zone)
+	}
+	sort.Ints(roomIds)
+
+	rootRoomId, _ := GetZoneRoot(zone)
+
+	inZone := make(map[int]struct{}, len(roomIds))
+	for _, roomId := range roomIds {
+		inZone[roomId] = struct{}{}
+	}
+
+	nodes := strings.Builder{}
+	edges := strings.Builder{}
+	outsideRoomIds := []int{}
+
+	for _, roomId := range roomIds {
+
+		r := peekRoom(roomId)
+		if r == nil {
+			nodes.WriteString(fmt.Sprintf("\tr%d [label=%s, color=red, fontcolor=red];\n", roomId, dotQuote(fmt.Sprintf("%d\nmissing", roomId))))
+			continue
+		}
+
+		biome := r.GetBiome()
+
+		attrs := []string{
+			`label=` + dotQuote(fmt.Sprintf("%d\n%s\n%s", roomId, r.Title, strings.ToLower(biome.Name()))),
+			`fillcolor=` + dotQuote(exportRoomColor(biome)),
+		}
+		if roomId == rootRoomId {
+			attrs = append(attrs, `penwidth=2`)
+		}
+		nodes.WriteString(fmt.Sprintf("\tr%d [%s];\n", roomId, strings.Join(attrs, `, `)))
+
+		for _, exitName := range sortedExitNames(r) {
+
+			exitInfo := r.Exits[exitName]
+
+			label := exitName
+			attrs := []string{}
+			if exitInfo.HasLock() {
+				label += fmt.Sprintf(` (lock %d)`, exitInfo.Lock.Difficulty)
+				attrs = append(attrs, `color=red`, `fontcolor=red`)
+			}
+			if exitInfo.Secret {
+				label += ` (secret)`
+				attrs = append(attrs, `style=dashed`)
+			}
+			attrs = append([]string{`label=` + dotQuote(label)}, attrs...)
+
+			edges.WriteString(fmt.Sprintf("\tr%d -> r%d [%s];\n", roomId, exitInfo.RoomId, strings.Join(attrs, `, `)))
+
+			if _, ok := inZone[exitInfo.RoomId]; !ok {
+				inZone[exitInfo.RoomId] = struct{}{}
+				outsideRoomIds = append(outsideRoomIds, exitInfo.RoomId)
+			}
+		}
+	}
+
+	sort.Ints(outsideRoomIds)
+	for _, roomId := range outsideRoomIds {
+		if r := peekRoom(roomId); r != nil {
+			nodes.WriteString(fmt.Sprintf("\tr%d [label=%s, style=\"rounded,dashed\"];\n", roomId, dotQuote(fmt.Sprintf("%d\n%s\n(%s)", roomId, r.Title, r.Zone))))
+		} else {
+			nodes.WriteString(fmt.Sprintf("\tr%d [label=%s, style=\"rounded,dashed\", color=red, fontcolor=red];\n", roomId, dotQuote(fmt.Sprintf("%d\nmissing", roomId))))
+		}
+	}
+
+	sb := strings.Builder{}
+	sb.WriteString(fmt.Sprintf("digraph %s {\n", dotQuote(zone)))
+	sb.WriteString(fmt.Sprintf("\tgraph [label=%s, labelloc=t];\n", dotQuote(zone)))
+	sb.WriteString("\tnode [shape=box, style=\"rounded,filled\", fillcolor=\"#ffffff\"];\n")
+	sb.WriteString(nodes.String())
+	sb.WriteString(edges.String())
+	sb.WriteString("}\n")
+
+	return sb.String(), nil
+}
+
+// Draws the rooms and exits of a zone as an SVG image.
+// Rooms are placed where the in-game map puts them, starting from the root room of the zone,
+// and each level is drawn separately. Rooms that can't be reached from the root room are listed below.
+// Temporary exits aren't included.
+func ExportZoneSVG(zone string) (string, error) {
+
+	rootRoomId, err := GetZoneRoot(zone)
+	if err != nil {
+		return ``, err
+	}
+
+	roomIds := GetZoneRoomIds(zone)
+	sort.Ints(roomIds)
+
+	rGraph := NewRoomGraph(500, 500, 0, MapModeAll)
+	rGraph.LimitToRooms(roomIds...)
+	if err := rGraph.Build(rootRoomId, nil); err != nil {
+		return ``, err
+	}
+
+	unreachedRoomIds := []string{}
+	for _, roomId := range roomIds {
+		if _, ok := rGraph.trackedRoomIds[roomId]; !ok {
+			unreachedRoomIds = append(unreachedRoomIds, fmt.Sprint(roomId))
+		}
+	}
+
+	gridWidth := rGraph.maxX - rGraph.minX + 1
+	gridHeight := rGraph.maxY - rGraph.minY + 1
+	levelHeight := svgTitleHeight + gridHeight*svgCellHeight
+
+	width := svgMargin*2 + gridWidth*svgCellWidth
+	height := svgMargin*2 + (rGraph.maxZ-rGraph.minZ+1)*levelHeight
+	if len(unreachedRoomIds) > 0 {
+		height += svgTitleHeight
+	}
+
+	// The center of a room in the image. Higher levels are drawn first.
+	roomCenter := func(node *roomNode) (int, int) {
+		x := svgMargin + (node.xPos-rGraph.minX)*svgCellWidth + svgCellWidth/2
+		y := svgMargin + (rGraph.maxZ-node.zPos)*levelHeight + svgTitleHeight + (node.yPos-rGraph.minY)*svgCellHeight + svgCellHeight/2
+		return x, y
+	}
+
+	nodes := make([]*roomNode, 0, len(rGraph.trackedRoomIds))
+	for _, node := range rGraph.trackedRoomIds {
+		nodes = append(nodes, node)
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].RoomId < nodes[j].RoomId })
+
+	sb := strings.Builder{}
+	sb.WriteString(fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="sans-serif" font-size="11">`+"\n", width, height, width, height))
+	sb.WriteString(`<rect width="100%" height="100%" fill="#ffffff"/>` + "\n")
+
+	for z := rGraph.maxZ; z >= rGraph.minZ; z-- {
+		title := zone
+		if rGraph.minZ != rGraph.maxZ {
+			title = fmt.Sprintf(`%s (level %+d)`, zone, z)
+		}
+		sb.WriteString(fmt.Sprintf(`<text x="%d" y="%d" font-size="16" font-weight="bold">%s</text>`+"\n", svgMargin, svgMargin+(rGraph.maxZ-z)*levelHeight+svgTitleHeight/2, html.EscapeString(title)))
+	}
+
+	// Exits go underneath the rooms
+	for _, node := range nodes {
+
+		r := LoadRoom(node.RoomId)
+		if r == nil {
+			continue
+		}
+
+		x1, y1 := roomCenter(node)
+
+		for _, exitName := range sortedExitNames(r) {
+
+			exitInfo := r.Exits[exitName]
+
+			stroke := `#666666`
+			if exitInfo.HasLock() {
+				stroke = `#cc0000`
+			}
+			dash := ``
+			if exitInfo.Secret {
+				dash = ` stroke-dasharray="4,3"`
+			}
+
+			if target, ok := rGraph.trackedRoomIds[exitInfo.RoomId]; ok {
+
+				// Exits to other levels are marked on the room instead
+				if target.zPos != node.zPos {
+					continue
+				}
+
+				x2, y2 := roomCenter(target)
+				sb.WriteString(fmt.Sprintf(`<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="%s" stroke-width="2"%s><title>%s</title></line>`+"\n",
+					x1, y1, x2, y2, stroke, dash, html.EscapeString(fmt.Sprintf(`%s: %d to %d`, exitName, node.RoomId, exitInfo.RoomId))))
+				continue
+			}
+
+			// Leads out of the zone (or nowhere), so draw a short stub in its direction
+			direction := exitName
+			if exitInfo.MapDirection != `` {
+				direction = exitInfo.MapDirection
+			}
+			delta, ok := DirectionDeltas[direction]
+			if !ok || delta.Dz != 0 {
+				continue
+			}
+
+			leadsTo := `missing room`
+			if target := peekRoom(exitInfo.RoomId); target != nil {
+				leadsTo = target.Zone
+			}
+
+			x2, y2 := x1+sign(delta.Dx)*(svgRoomWidth/2+svgStubLength), y1+sign(delta.Dy)*(svgRoomHeight/2+svgStubLength)
+			sb.WriteString(fmt.Sprintf(`<g><title>%s</title><line x1="%d" y1="%d" x2="%d" y2="%d" stroke="%s" stroke-width="2" stroke-dasharray="2,2"/><circle cx="%d" cy="%d" r="4" fill="%s"/></g>`+"\n",
+				html.EscapeString(fmt.Sprintf(`%s: %d to %d (%s)`, exitName, node.RoomId, exitInfo.RoomId, leadsTo)), x1, y1, x2, y2, stroke, x2, y2, stroke))
+		}
+	}
+
+	for _, node := range nodes {
+
+		r := LoadRoom(node.RoomId)
+		if r == nil {
+			continue
+		}
+
+		x, y := roomCenter(node)
+
+		strokeWidth := 1
+		if node.RoomId == rootRoomId {
+			strokeWidth = 3
+		}
+
+		hasUp, hasDown := false, false
+		for direction := range node.Exits {
+			if delta := DirectionDeltas[direction]; delta.Dz > 0 {
+				hasUp = true
+			} else if delta.Dz < 0 {
+				hasDown = true
+			}
+		}
+
+		label := fmt.Sprint(node.RoomId)
+		if hasUp {
+			label += ` ` + string(upSymbol)
+		}
+		if hasDown {
+			label += ` ` + string(downSymbol)
+		}
+
+		biome := r.GetBiome()
+
+		title := r.Title
+		if utf8.RuneCountInString(title) > svgTitleChars {
+			title = string([]rune(title)[:svgTitleChars-1]) + `…`
+		}
+
+		sb.WriteString(fmt.Sprintf(`<g><title>%s</title>`, html.EscapeString(fmt.Sprintf(`%d: %s (%s)`, node.RoomId, r.Title, strings.ToLower(biome.Name())))))
+		sb.WriteString(fmt.Sprintf(`<rect x="%d" y="%d" width="%d" height="%d" rx="5" fill="%s" stroke="#333333" stroke-width="%d"/>`, x-svgRoomWidth/2, y-svgRoomHeight/2, svgRoomWidth, svgRoomHeight, exportRoomColor(biome), strokeWidth))
+		sb.WriteString(fmt.Sprintf(`<text x="%d" y="%d" text-anchor="middle" font-weight="bold">%s</text>`, x, y-3, html.EscapeString(label)))
+		sb.WriteString(fmt.Sprintf(`<text x="%d" y="%d" text-anchor="middle" font-size="9">%s</text></g>`+"\n", x, y+10, html.EscapeString(title)))
+	}
+
+	if len(unreachedRoomIds) > 0 {
+		sb.WriteString(fmt.Sprintf(`<text x="%d" y="%d" fill="#cc0000">%s</text>`+"\n", svgMargin, height-svgMargin, html.EscapeString(fmt.Sprintf(`Not reachable from room %d: %s`, rootRoomId, strings.Join(unreachedRoomIds, `, `)))))
+	}
+
+	sb.WriteString("</svg>\n")
+
+	return sb.String(), nil
+}
+
+func exportRoomColor(biome BiomeInfo) string {
+	if color, ok := exportBiomeColors[strings.ToLower(biome.Name())]; ok {
+		return color
+	}
+	return exportDefaultColor
+}
+
+func sortedExitNames(r *Room) []string {
+	exitNames := make([]string, 0, len(r.Exits))
+	for exitName := range r.Exits {
+		exitNames = append(exitNames, exitName)
+	}
+	sort.Strings(exitNames)
+	return exitNames
+}
+
+// Quotes and escapes a string for a DOT file
+func dotQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s) + `"`
+}
+
+func sign(n int) int {
+	if n < 0 {
+		return -1
+	} else if n > 0 {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,52 @@
+package rooms
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/volte6/gomud/internal/exit"
+	"github.com/volte6/gomud/internal/gamelock"
+)
+
+func TestExportZone(t *testing.T) {
+
+	zone := `Export Test`
+
+	// 1 -east-> 2, 1 -hole(secret)-> 2, 2 -gate(locked)-> 3, 3 -west-> 999999 (missing)
+	r1 := newTestRoom(t, 900201, zone)
+	r2 := newTestRoom(t, 900202, zone)
+	r3 := newTestRoom(t, 900203, zone)
+
+	r1.Exits[`east`] = exit.RoomExit{RoomId: r2.RoomId}
+	r1.Exits[`hole`] = exit.RoomExit{RoomId: r2.RoomId, Secret: true, MapDirection: `east`}
+	r2.Exits[`gate`] = exit.RoomExit{RoomId: r3.RoomId, MapDirection: `south`, Lock: gamelock.Lock{Difficulty: 3}}
+	r3.Exits[`west`] = exit.RoomExit{RoomId: 999999}
+
+	dot, err := ExportZoneDOT(zone)
+	if err != nil {
+		t.Fatalf("ExportZoneDOT() error = %v", err)
+	}
+
+	for _, want := range []string{
+		`r900201 -> r900202 [label="east"];`,
+		`r900201 -> r900202 [label="hole (secret)", style=dashed];`,
+		`r900202 -> r900203 [label="gate (lock 3)", color=red, fontcolor=red];`,
+		`r999999 [label="999999\nmissing"`,
+	} {
+		if !strings.Contains(dot, want) {
+			t.Errorf("ExportZoneDOT() is missing %s\n%s", want, dot)
+		}
+	}
+
+	svg, err := ExportZoneSVG(zone)
+	if err != nil {
+		t.Fatalf("ExportZoneSVG() error = %v", err)
+	}
+
+	if !strings.HasPrefix(svg, `<svg `) || strings.Count(svg, `<rect x=`) != 3 {
+		t.Errorf("ExportZoneSVG() = %s, expected an svg with 3 rooms", svg)
+	}
+	if !strings.Contains(svg, `(missing room)`) {
+		t.Errorf("ExportZoneSVG() doesn't mark the exit to a missing room\n%s", svg)
+	}
+}
@@ -0,0 +1,205 @@
+package rooms
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+
+	"github.com/volte6/gomud/internal/gametime"
+	"github.com/volte6/gomud/internal/mobs"
+	"github.com/volte6/gomud/internal/users"
+	"github.com/volte6/gomud/internal/util"
+)
+
+var (
+	zoneLastReset = map[string]uint64{} // zone => round it last reset
+)
+
+// Changes the reset schedule of a zone
+func SetZoneReset(zone string, reset ZoneReset) error {
+
+	zoneInfo, ok := roomManager.zones[zone]
+	if !ok {
+		return fmt.Errorf("zone %s does not exist.", zone)
+	}
+
+	zoneConfig := GetZoneConfig(zone)
+	if zoneConfig == nil {
+		return fmt.Errorf("zone %s has no root room.", zone)
+	}
+
+	if reset.Interval != `` {
+		if err := gametime.ValidatePeriod(reset.Interval, true); err != nil {
+			return err
+		}
+	}
+
+	// A new interval starts counting from now
+	if reset.Interval != zoneConfig.Reset.Interval {
+		zoneLastReset[zone] = util.GetRoundCount()
+	}
+
+	zoneConfig.Reset = reset
+	zoneConfig.Validate()
+
+	zoneInfo.HasResetSchedule = zoneConfig.Reset.Interval != ``
+	roomManager.zones[zone] = zoneInfo
+
+	return nil
+}
+
+// Returns the round a zone last reset, or 0 if it hasn't since the server started
+func GetZoneLastReset(zone string) uint64 {
+	return zoneLastReset[zone]
+}
+
+// Resets any zones whose reset is due
+func zoneResetMaintenance() bool {
+
+	roundNow := util.GetRoundCount()
+
+	zoneNames := []string{}
+	for zoneName, zoneInfo := range roomManager.zones {
+		if zoneInfo.HasResetSchedule {
+			zoneNames = append(zoneNames, zoneName)
+		}
+	}
+	sort.Strings(zoneNames)
+
+	resetAny := false
+	for _, zoneName := range zoneNames {
+
+		zoneConfig := GetZoneConfig(zoneName)
+		if zoneConfig == nil || zoneConfig.Reset.Interval == `` {
+			continue
+		}
+
+		// The first reset is one interval after the server starts
+		lastReset, ok := zoneLastReset[zoneName]
+		if !ok {
+			zoneLastReset[zoneName] = roundNow
+			continue
+		}
+
+		if roundNow < gametime.GetDate(lastReset).AddPeriod(zoneConfig.Reset.Interval) {
+			continue
+		}
+
+		if zoneConfig.Reset.Condition != ResetAlways && len(getZonePlayers(zoneName)) > 0 {
+			continue
+		}
+
+		if _, err := ResetZone(zoneName); err == nil {
+			resetAny = true
+		}
+	}
+
+	return resetAny
+}
+
+// Puts every loaded room in a zone back the way it was built, and tells any players in the zone.
+// Rooms that aren't loaded are left alone, since they have no mobs and nobody has seen them.
+// Returns how many rooms were reset.
+func ResetZone(zone string) (int, error) {
+
+	zoneInfo, ok := roomManager.zones[zone]
+	if !ok {
+		return 0, errors.New("zone doesn't exist")
+	}
+
+	roundNow := util.GetRoundCount()
+
+	roomIds := make([]int, 0, len(zoneInfo.RoomIds))
+	for roomId := range zoneInfo.RoomIds {
+		roomIds = append(roomIds, roomId)
+	}
+	sort.Ints(roomIds)
+
+	resetCt := 0
+	for _, roomId := range roomIds {
+		if r, ok := roomManager.rooms[roomId]; ok {
+			r.reset(roundNow)
+			resetCt++
+		}
+	}
+
+	if zoneConfig := GetZoneConfig(zone); zoneConfig != nil {
+
+		zoneConfig.Mutators.Restore(roundNow)
+
+		if len(zoneConfig.Reset.Messages) > 0 {
+			msg := zoneConfig.Reset.Messages[util.Rand(len(zoneConfig.Reset.Messages))]
+			for _, userId := range getZonePlayers(zone) {
+				if user := users.GetByUserId(userId); user != nil {
+					user.SendText(msg)
+				}
+			}
+		}
+	}
+
+	zoneLastReset[zone] = roundNow
+
+	slog.Info("Zone reset", "zone", zone, "rooms", resetCt)
+
+	return resetCt, nil
+}
+
+// Puts a room back the way it was built.
+// Missing mobs and items respawn right away, doors close, locks are locked again and mutators come back.
+// Mobs that are still alive and anything players left behind are left alone.
+func (r *Room) reset(roundNow uint64) {
+
+	for idx, spawnInfo := range r.SpawnInfo {
+
+		if spawnInfo.InstanceId > 0 && mobs.GetInstance(spawnInfo.InstanceId) != nil {
+			continue
+		}
+
+		spawnInfo.InstanceId = 0
+		spawnInfo.DespawnedRound = 0
+		r.SpawnInfo[idx] = spawnInfo
+	}
+
+	for exitName, exitInfo := range r.Exits {
+		if exitInfo.HasDoor() {
+			r.SetDoorOpen(exitName, false)
+		}
+		if exitInfo.HasLock() {
+			r.SetExitLock(exitName, true)
+		}
+	}
+
+	for containerName, container := range r.Containers {
+		if container.HasLock() {
+			container.Lock.SetLocked()
+			r.Containers[containerName] = container
+		}
+	}
+
+	r.Mutators.Restore(roundNow)
+
+	r.Prepare(false)
+}
+
+// Returns the user ids of every player in a zone
+func getZonePlayers(zone string) []int {
+
+	userIds := []int{}
+
+	zoneInfo, ok := roomManager.zones[zone]
+	if !ok {
+		return userIds
+	}
+
+	for roomId := range roomManager.roomsWithUsers {
+		if _, ok := zoneInfo.RoomIds[roomId]; !ok {
+			continue
+		}
+		if r, ok := roomManager.rooms[roomId]; ok {
+			userIds = append(userIds, r.players...)
+		}
+	}
+
+	return userIds
+}
@@ -0,0 +1,66 @@
+package rooms
+
+import (
+	"testing"
+
+	"github.com/volte6/gomud/internal/exit"
+	"github.com/volte6/gomud/internal/gamelock"
+)
+
+func TestResetZone(t *testing.T) {
+
+	zone := `Reset Test`
+
+	r1 := newTestRoom(t, 900701, zone)
+	r2 := newTestRoom(t, 900702, zone)
+	defer delete(zoneLastReset, zone)
+
+	r1.ZoneConfig.RoomId = r1.RoomId
+	r1.Exits[`gate`] = exit.RoomExit{RoomId: r2.RoomId, Lock: gamelock.Lock{Difficulty: 5, UnlockedRound: 10}}
+	r2.Containers = map[string]Container{`chest`: {Lock: gamelock.Lock{Difficulty: 3, UnlockedRound: 10}}}
+
+	if err := SetZoneReset(zone, ZoneReset{Interval: `30 real minutes`, Condition: `ALWAYS`}); err != nil {
+		t.Fatalf("SetZoneReset() error = %v", err)
+	}
+
+	if !roomManager.zones[zone].HasResetSchedule || r1.ZoneConfig.Reset.Condition != ResetAlways {
+		t.Errorf("SetZoneReset() = %+v, expected a schedule that always resets", r1.ZoneConfig.Reset)
+	}
+
+	resetCt, err := ResetZone(zone)
+	if err != nil || resetCt != 2 {
+		t.Fatalf("ResetZone() = %d, %v, expected 2 rooms reset", resetCt, err)
+	}
+
+	if r1.Exits[`gate`].Lock.UnlockedRound != 0 {
+		t.Errorf("ResetZone() didn't relock the gate")
+	}
+
+	if r2.Containers[`chest`].Lock.UnlockedRound != 0 {
+		t.Errorf("ResetZone() didn't relock the chest")
+	}
+
+	if _, ok := zoneLastReset[zone]; !ok {
+		t.Errorf("ResetZone() didn't record when the zone reset")
+	}
+
+	for _, interval := range []string{`5 xx`, `5 fortnights`, `often`} {
+		if err := SetZoneReset(zone, ZoneReset{Interval: interval}); err == nil {
+			t.Errorf("SetZoneReset() with interval %q expected an error", interval)
+		}
+	}
+
+	if r1.ZoneConfig.Reset.Interval != `30 real minutes` {
+		t.Errorf("SetZoneReset() with a bad interval changed the schedule to %q", r1.ZoneConfig.Reset.Interval)
+	}
+
+	r1.ZoneConfig.Reset.Interval = `5 xx`
+	if err := r1.Validate(); err == nil {
+		t.Errorf("Validate() expected an error for a bad reset interval")
+	}
+	r1.ZoneConfig.Reset.Interval = `30 real minutes`
+
+	if err := SetZoneReset(zone, ZoneReset{}); err != nil || roomManager.zones[zone].HasResetSchedule {
+		t.Errorf("SetZoneReset() with no interval left the zone on a schedule")
+	}
+}
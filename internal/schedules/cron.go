@@ -0,0 +1,170 @@
+package schedules
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// A parsed 5 field cron expression:
+// minute hour day-of-month month day-of-week
+type CronExpression struct {
+	minutes     [60]bool
+	hours       [24]bool
+	daysOfMonth [32]bool
+	months      [13]bool
+	daysOfWeek  [7]bool
+	domAny      bool // day-of-month was "*"
+	dowAny      bool // day-of-week was "*"
+}
+
+var (
+	cronShortcuts = map[string]string{
+		`@yearly`:   `0 0 1 1 *`,
+		`@annually`: `0 0 1 1 *`,
+		`@monthly`:  `0 0 1 * *`,
+		`@weekly`:   `0 0 * * 0`,
+		`@daily`:    `0 0 * * *`,
+		`@midnight`: `0 0 * * *`,
+		`@hourly`:   `0 * * * *`,
+	}
+
+	cronMonthNames = map[string]int{
+		`jan`: 1, `feb`: 2, `mar`: 3, `apr`: 4, `may`: 5, `jun`: 6,
+		`jul`: 7, `aug`: 8, `sep`: 9, `oct`: 10, `nov`: 11, `dec`: 12,
+	}
+
+	cronDayNames = map[string]int{
+		`sun`: 0, `mon`: 1, `tue`: 2, `wed`: 3, `thu`: 4, `fri`: 5, `sat`: 6,
+	}
+)
+
+// Parses a standard 5 field cron expression.
+// Supports *, lists (1,2,3), ranges (1-5), steps (*/15, 1-30/5),
+// month/day names (jan, fri) and shortcuts such as @daily or @hourly
+func ParseCron(expr string) (*CronExpression, error) {
+
+	expr = strings.ToLower(strings.TrimSpace(expr))
+
+	if shortcut, ok := cronShortcuts[expr]; ok {
+		expr = shortcut
+	}
+
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf(`cron expression "%s" must have 5 fields`, expr)
+	}
+
+	c := &CronExpression{}
+
+	if err := parseCronField(fields[0], 0, 59, nil, c.minutes[:]); err != nil {
+		return nil, fmt.Errorf(`minute: %w`, err)
+	}
+
+	if err := parseCronField(fields[1], 0, 23, nil, c.hours[:]); err != nil {
+		return nil, fmt.Errorf(`hour: %w`, err)
+	}
+
+	if err := parseCronField(fields[2], 1, 31, nil, c.daysOfMonth[:]); err != nil {
+		return nil, fmt.Errorf(`day of month: %w`, err)
+	}
+
+	if err := parseCronField(fields[3], 1, 12, cronMonthNames, c.months[:]); err != nil {
+		return nil, fmt.Errorf(`month: %w`, err)
+	}
+
+	// 7 is also accepted as sunday
+	dow := [8]bool{}
+	if err := parseCronField(fields[4], 0, 7, cronDayNames, dow[:]); err != nil {
+		return nil, fmt.Errorf(`day of week: %w`, err)
+	}
+	copy(c.daysOfWeek[:], dow[:7])
+	if dow[7] {
+		c.daysOfWeek[0] = true
+	}
+
+	c.domAny = fields[2] == `*`
+	c.dowAny = fields[4] == `*`
+
+	return c, nil
+}
+
+// Returns true if the time provided falls within the minute described by the expression
+func (c *CronExpression) Matches(t time.Time) bool {
+
+	if !c.minutes[t.Minute()] || !c.hours[t.Hour()] || !c.months[int(t.Month())] {
+		return false
+	}
+
+	domMatch := c.daysOfMonth[t.Day()]
+	dowMatch := c.daysOfWeek[int(t.Weekday())]
+
+	// Standard cron behavior: if both day fields are restricted, either may match
+	if !c.domAny && !c.dowAny {
+		return domMatch || dowMatch
+	}
+
+	return domMatch && dowMatch
+}
+
+func parseCronField(field string, min int, max int, names map[string]int, out []bool) error {
+
+	for _, part := range strings.Split(field, `,`) {
+
+		step := 1
+		if idx := strings.Index(part, `/`); idx != -1 {
+			var err error
+			if step, err = strconv.Atoi(part[idx+1:]); err != nil || step < 1 {
+				return fmt.Errorf(`invalid step "%s"`, part)
+			}
+			part = part[:idx]
+		}
+
+		start, end := min, max
+
+		if part != `*` {
+
+			rangeParts := strings.SplitN(part, `-`, 2)
+
+			var err error
+			if start, err = parseCronValue(rangeParts[0], names); err != nil {
+				return err
+			}
+
+			end = start
+			if len(rangeParts) > 1 {
+				if end, err = parseCronValue(rangeParts[1], names); err != nil {
+					return err
+				}
+			} else if step > 1 {
+				// "5/10" means starting at 5, every 10
+				end = max
+			}
+		}
+
+		if start < min || end > max || start > end {
+			return fmt.Errorf(`value out of range "%s" (%d-%d)`, field, min, max)
+		}
+
+		for i := start; i <= end; i += step {
+			out[i] = true
+		}
+	}
+
+	return nil
+}
+
+func parseCronValue(val string, names map[string]int) (int, error) {
+	if names != nil {
+		if num, ok := names[val]; ok {
+			return num, nil
+		}
+	}
+
+	num, err := strconv.Atoi(val)
+	if err != nil {
+		return 0, fmt.Errorf(`invalid value "%s"`, val)
+	}
+	return num, nil
+}
@@ -0,0 +1,49 @@
+package schedules
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCron_Matches(t *testing.T) {
+
+	// Friday, 20:00
+	friday8pm := time.Date(2024, time.March, 15, 20, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		expr    string
+		when    time.Time
+		matches bool
+	}{
+		{`0 20 * * fri`, friday8pm, true},
+		{`0 20 * * 5`, friday8pm, true},
+		{`0 20 * * 1-4`, friday8pm, false},
+		{`*/15 * * * *`, friday8pm.Add(45 * time.Minute), true},
+		{`*/15 * * * *`, friday8pm.Add(10 * time.Minute), false},
+		{`0 20 1 * *`, friday8pm, false},
+		{`0 20 1 * fri`, friday8pm, true}, // either day field may match when both are restricted
+		{`0 20 15 mar *`, friday8pm, true},
+		{`@hourly`, friday8pm, true},
+		{`@daily`, friday8pm, false},
+		{`0 20 * * 7`, friday8pm.AddDate(0, 0, 2), true}, // 7 is sunday
+	}
+
+	for _, tt := range tests {
+		c, err := ParseCron(tt.expr)
+		if err != nil {
+			t.Errorf("ParseCron(%q) returned error: %v", tt.expr, err)
+			continue
+		}
+		if got := c.Matches(tt.when); got != tt.matches {
+			t.Errorf("ParseCron(%q).Matches(%s) = %v, expected %v", tt.expr, tt.when, got, tt.matches)
+		}
+	}
+}
+
+func TestParseCron_Invalid(t *testing.T) {
+	for _, expr := range []string{``, `* * * *`, `60 * * * *`, `* 24 * * *`, `* * 0 * *`, `*/0 * * * *`, `a b c d e`} {
+		if _, err := ParseCron(expr); err == nil {
+			t.Errorf("ParseCron(%q) expected an error", expr)
+		}
+	}
+}
@@ -0,0 +1,546 @@
+package schedules
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/volte6/gomud/internal/configs"
+	"github.com/volte6/gomud/internal/events"
+	"github.com/volte6/gomud/internal/fileloader"
+	"github.com/volte6/gomud/internal/gametime"
+	"github.com/volte6/gomud/internal/mobs"
+	"github.com/volte6/gomud/internal/rooms"
+	"github.com/volte6/gomud/internal/scripting"
+	"github.com/volte6/gomud/internal/util"
+)
+
+// Game time periods a schedule can be triggered by
+const (
+	PeriodSunrise  = `sunrise`
+	PeriodSunset   = `sunset`
+	PeriodNoon     = `noon`
+	PeriodMidnight = `midnight`
+	PeriodNewMonth = `newmonth`
+	PeriodNewYear  = `newyear` // A new zodiac year
+	PeriodFullMoon = `fullmoon`
+	PeriodNewMoon  = `newmoon`
+)
+
+var (
+	gameTimePeriods = []string{
+		PeriodSunrise,
+		PeriodSunset,
+		PeriodNoon,
+		PeriodMidnight,
+		PeriodNewMonth,
+		PeriodNewYear,
+		PeriodFullMoon,
+		PeriodNewMoon,
+	}
+
+	loadedSchedules = &ScheduleFile{}
+
+	// The last real world minute that cron expressions were checked against
+	lastCronMinute time.Time
+)
+
+// A single thing that happens when a schedule runs.
+// Each action should only fill out one of the action fields (Broadcast, Message, etc.)
+// RoomId or Zone specify where the action happens, if relevant.
+type Action struct {
+	Broadcast      string `yaml:"broadcast,omitempty"`      // Text sent to every connected player
+	Message        string `yaml:"message,omitempty"`        // Text sent to the target room or zone
+	Command        string `yaml:"command,omitempty"`        // Mob command run by every MobId in the target room or zone
+	MobId          int    `yaml:"mobid,omitempty"`          // Which mobs run Command
+	MutatorAdd     string `yaml:"mutatoradd,omitempty"`     // Mutator added to the target room or zone
+	MutatorRemove  string `yaml:"mutatorremove,omitempty"`  // Mutator removed from the target room or zone
+	ScriptFunction string `yaml:"scriptfunction,omitempty"` // Function called in the target room script (or zone root room script)
+	RoomId         int    `yaml:"roomid,omitempty"`
+	Zone           string `yaml:"zone,omitempty"`
+}
+
+type Schedule struct {
+	ScheduleId   string    `yaml:"scheduleid"`
+	Description  string    `yaml:"description,omitempty"`
+	GameTime     string    `yaml:"gametime,omitempty"` // sunrise, sunset, noon, midnight, newmonth, newyear, fullmoon, newmoon
+	Moon         string    `yaml:"moon,omitempty"`     // Which moon fullmoon/newmoon refers to. Defaults to gametime.DefaultMoon
+	Months       []int     `yaml:"months,omitempty"`   // Optionally limit GameTime to specific game months
+	Cron         string    `yaml:"cron,omitempty"`     // Real time cron expression such as "0 20 * * fri"
+	Disabled     bool      `yaml:"disabled,omitempty"`
+	Actions      []Action  `yaml:"actions"`
+	LastRunRound uint64    `yaml:"-"` // Saved in the state file
+	LastRunTime  time.Time `yaml:"-"` // Saved in the state file
+
+	cron *CronExpression
+}
+
+type ScheduleFile struct {
+	Schedules []*Schedule `yaml:"schedules"`
+}
+
+func (sf *ScheduleFile) Filepath() string {
+	return filepath.Base(string(configs.GetConfig().FileSchedules))
+}
+
+// When a schedule last ran
+type ScheduleState struct {
+	LastRunRound uint64    `yaml:"lastrunround,omitempty"`
+	LastRunTime  time.Time `yaml:"lastruntime,omitempty"`
+}
+
+// Kept apart from the schedule file, since it is rewritten every time a schedule runs
+type ScheduleStateFile struct {
+	Schedules map[string]ScheduleState `yaml:"schedules"` // scheduleId => state
+}
+
+func (sf *ScheduleStateFile) Filepath() string {
+	return filepath.Base(string(configs.GetConfig().FileScheduleState))
+}
+
+func (sf *ScheduleStateFile) Validate() error {
+	if sf.Schedules == nil {
+		sf.Schedules = map[string]ScheduleState{}
+	}
+	return nil
+}
+
+func (sf *ScheduleFile) Validate() error {
+
+	seen := map[string]struct{}{}
+
+	for _, s := range sf.Schedules {
+
+		if err := s.Validate(); err != nil {
+			return err
+		}
+
+		if _, ok := seen[s.ScheduleId]; ok {
+			return fmt.Errorf(`duplicate scheduleid: %s`, s.ScheduleId)
+		}
+		seen[s.ScheduleId] = struct{}{}
+	}
+
+	return nil
+}
+
+func (s *Schedule) Validate() error {
+
+	s.ScheduleId = strings.ToLower(strings.TrimSpace(s.ScheduleId))
+	s.GameTime = strings.ToLower(strings.TrimSpace(s.GameTime))
+	s.Moon = strings.ToLower(strings.TrimSpace(s.Moon))
+
+	if s.ScheduleId == `` {
+		return errors.New(`scheduleid cannot be empty`)
+	}
+
+	if s.GameTime == `` && s.Cron == `` {
+		return fmt.Errorf(`schedule %s: either gametime or cron must be set`, s.ScheduleId)
+	}
+
+	if s.GameTime != `` && !IsGameTimePeriod(s.GameTime) {
+		return fmt.Errorf(`schedule %s: invalid gametime "%s"`, s.ScheduleId, s.GameTime)
+	}
+
+	if s.Moon != `` && !slices.Contains(gametime.GetMoonNames(), s.Moon) {
+		return fmt.Errorf(`schedule %s: unknown moon "%s"`, s.ScheduleId, s.Moon)
+	}
+
+	for _, month := range s.Months {
+		if month < 1 || month > gametime.MonthsPerYear() {
+			return fmt.Errorf(`schedule %s: month %d is not between 1 and %d`, s.ScheduleId, month, gametime.MonthsPerYear())
+		}
+	}
+
+	if s.Cron != `` {
+		cron, err := ParseCron(s.Cron)
+		if err != nil {
+			return fmt.Errorf(`schedule %s: %w`, s.ScheduleId, err)
+		}
+		s.cron = cron
+	}
+
+	if len(s.Actions) == 0 {
+		return fmt.Errorf(`schedule %s: no actions defined`, s.ScheduleId)
+	}
+
+	return nil
+}
+
+// Returns a short human readable description of when the schedule runs
+func (s *Schedule) When() string {
+	parts := []string{}
+
+	if s.GameTime != `` {
+		gt := s.GameTime
+		if (s.GameTime == PeriodFullMoon || s.GameTime == PeriodNewMoon) && s.Moon != `` {
+			gt += `(` + s.Moon + `)`
+		}
+		if len(s.Months) > 0 {
+			monthNames := []string{}
+			for _, m := range s.Months {
+				monthNames = append(monthNames, gametime.MonthName(m))
+			}
+			gt += ` in ` + strings.Join(monthNames, `/`)
+		}
+		parts = append(parts, gt)
+	}
+
+	if s.Cron != `` {
+		parts = append(parts, `cron "`+s.Cron+`"`)
+	}
+
+	return strings.Join(parts, ` or `)
+}
+
+// Returns true if the game time change between two dates should trigger this schedule
+func (s *Schedule) matchesGameTime(gdBefore gametime.GameDate, gdNow gametime.GameDate) bool {
+
+	if s.GameTime == `` {
+		return false
+	}
+
+	if len(s.Months) > 0 {
+		monthMatch := false
+		for _, m := range s.Months {
+			if m == gdNow.Month {
+				monthMatch = true
+				break
+			}
+		}
+		if !monthMatch {
+			return false
+		}
+	}
+
+	sunset := !gdBefore.Night && gdNow.Night
+
+	switch s.GameTime {
+	case PeriodSunrise:
+		return gdBefore.Night && !gdNow.Night
+	case PeriodSunset:
+		return sunset
+	case PeriodNoon:
+		return gdBefore.Hour24 < 12 && gdNow.Hour24 >= 12
+	case PeriodMidnight:
+		return gdBefore.Day != gdNow.Day
+	case PeriodNewMonth:
+		return gdBefore.Month != gdNow.Month
+	case PeriodNewYear:
+		return gdBefore.Year != gdNow.Year
+	case PeriodFullMoon, PeriodNewMoon:
+		// Moons are noticed when they rise at sunset, on the first night of the phase
+		if !sunset {
+			return false
+		}
+
+		wantPhase := gametime.MoonPhaseFull
+		if s.GameTime == PeriodNewMoon {
+			wantPhase = gametime.MoonPhaseNew
+		}
+
+		yesterday := gdNow.Add(0, -1, 0)
+		return gametime.GetMoonPhase(s.Moon, gdNow) == wantPhase && gametime.GetMoonPhase(s.Moon, yesterday) != wantPhase
+	}
+
+	return false
+}
+
+// Runs all actions of a schedule and records when it ran
+func (s *Schedule) Run() {
+
+	slog.Info(`Schedule Run`, `scheduleId`, s.ScheduleId, `when`, s.When())
+
+	for _, action := range s.Actions {
+		action.Run()
+	}
+
+	s.LastRunRound = util.GetRoundCount()
+	s.LastRunTime = time.Now()
+}
+
+// Returns the room id's targetted by an action
+func (a Action) targetRoomIds() []int {
+	if a.RoomId > 0 {
+		return []int{a.RoomId}
+	}
+	if a.Zone != `` {
+		return rooms.GetZoneRoomIds(a.Zone)
+	}
+	return []int{}
+}
+
+func (a Action) Run() {
+
+	if a.Broadcast != `` {
+		events.AddToQueue(events.Broadcast{
+			Text: a.Broadcast + "\n",
+		})
+	}
+
+	if a.Message != `` {
+		for _, roomId := range a.targetRoomIds() {
+			// Don't load rooms just to send them a message nobody will read
+			if !rooms.IsRoomLoaded(roomId) {
+				continue
+			}
+			if room := rooms.LoadRoom(roomId); room != nil {
+				room.SendText(a.Message)
+			}
+		}
+	}
+
+	if a.Command != `` && a.MobId > 0 {
+		roomIds := map[int]struct{}{}
+		for _, roomId := range a.targetRoomIds() {
+			roomIds[roomId] = struct{}{}
+		}
+
+		for _, mobInstId := range mobs.GetAllMobInstanceIds() {
+			mob := mobs.GetInstance(mobInstId)
+			if mob == nil || int(mob.MobId) != a.MobId {
+				continue
+			}
+			if len(roomIds) > 0 {
+				if _, ok := roomIds[mob.Character.RoomId]; !ok {
+					continue
+				}
+			}
+			mob.Command(a.Command)
+		}
+	}
+
+	if a.MutatorAdd != `` || a.MutatorRemove != `` {
+		if a.RoomId > 0 {
+			if room := rooms.LoadRoom(a.RoomId); room != nil {
+				if a.MutatorAdd != `` {
+					room.Mutators.Add(a.MutatorAdd)
+				}
+				if a.MutatorRemove != `` {
+					room.Mutators.Remove(a.MutatorRemove)
+				}
+			}
+		} else if a.Zone != `` {
+			if a.MutatorAdd != `` && !rooms.AddZoneMutator(a.Zone, a.MutatorAdd) {
+				slog.Error(`Schedule Action`, `error`, `could not add zone mutator`, `zone`, a.Zone, `mutatorId`, a.MutatorAdd)
+			}
+			if a.MutatorRemove != `` {
+				rooms.RemoveZoneMutator(a.Zone, a.MutatorRemove)
+			}
+		}
+	}
+
+	if a.ScriptFunction != `` {
+		roomId := a.RoomId
+		if roomId == 0 && a.Zone != `` {
+			roomId, _ = rooms.GetZoneRoot(a.Zone)
+		}
+		if roomId > 0 {
+			if _, err := scripting.TryRoomScriptEvent(a.ScriptFunction, 0, roomId); err != nil {
+				slog.Error(`Schedule Action`, `scriptFunction`, a.ScriptFunction, `roomId`, roomId, `error`, err)
+			}
+		}
+	}
+}
+
+// Returns true if the string is a supported game time period
+func IsGameTimePeriod(period string) bool {
+	for _, p := range gameTimePeriods {
+		if p == period {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns all supported game time periods
+func GetGameTimePeriods() []string {
+	return append([]string{}, gameTimePeriods...)
+}
+
+// Returns all schedules sorted by id
+func GetAll() []*Schedule {
+	all := append([]*Schedule{}, loadedSchedules.Schedules...)
+	sort.Slice(all, func(i, j int) bool {
+		return all[i].ScheduleId < all[j].ScheduleId
+	})
+	return all
+}
+
+func Get(scheduleId string) *Schedule {
+	scheduleId = strings.ToLower(scheduleId)
+	for _, s := range loadedSchedules.Schedules {
+		if s.ScheduleId == scheduleId {
+			return s
+		}
+	}
+	return nil
+}
+
+// Adds a new schedule and saves the schedule file
+func Add(s *Schedule) error {
+
+	if err := s.Validate(); err != nil {
+		return err
+	}
+
+	if Get(s.ScheduleId) != nil {
+		return fmt.Errorf(`schedule %s already exists`, s.ScheduleId)
+	}
+
+	loadedSchedules.Schedules = append(loadedSchedules.Schedules, s)
+
+	return Save()
+}
+
+// Removes a schedule and saves the schedule file
+func Remove(scheduleId string) error {
+
+	scheduleId = strings.ToLower(scheduleId)
+
+	for i, s := range loadedSchedules.Schedules {
+		if s.ScheduleId == scheduleId {
+			loadedSchedules.Schedules = append(loadedSchedules.Schedules[:i], loadedSchedules.Schedules[i+1:]...)
+			return Save()
+		}
+	}
+
+	return fmt.Errorf(`schedule %s not found`, scheduleId)
+}
+
+// Runs a schedule immediately, regardless of whether it is disabled
+func RunNow(scheduleId string) error {
+	s := Get(scheduleId)
+	if s == nil {
+		return fmt.Errorf(`schedule %s not found`, scheduleId)
+	}
+
+	s.Run()
+
+	return SaveState()
+}
+
+// Should be called once per round with the game date before and after the round advanced
+func RoundTick(gdBefore gametime.GameDate, gdNow gametime.GameDate) {
+
+	ranCt := 0
+
+	for _, s := range loadedSchedules.Schedules {
+		if s.Disabled {
+			continue
+		}
+
+		if s.matchesGameTime(gdBefore, gdNow) {
+			s.Run()
+			ranCt++
+		}
+	}
+
+	// Cron expressions are checked at most once per real world minute
+	nowMinute := time.Now().Truncate(time.Minute)
+	if nowMinute.After(lastCronMinute) {
+
+		lastCronMinute = nowMinute
+
+		for _, s := range loadedSchedules.Schedules {
+			if s.Disabled || s.cron == nil {
+				continue
+			}
+
+			// Already ran this minute (Possibly before a restart)
+			if !s.LastRunTime.Before(nowMinute) {
+				continue
+			}
+
+			if s.cron.Matches(nowMinute) {
+				s.Run()
+				ranCt++
+			}
+		}
+	}
+
+	if ranCt > 0 {
+		if err := SaveState(); err != nil {
+			slog.Error(`schedules.SaveState()`, `error`, err)
+		}
+	}
+}
+
+// Writes the schedules to the schedule file
+func Save() error {
+
+	path := string(configs.GetConfig().FileSchedules)
+
+	saveOptions := []fileloader.SaveOption{}
+	if configs.GetConfig().CarefulSaveFiles {
+		saveOptions = append(saveOptions, fileloader.SaveCareful)
+	}
+
+	return fileloader.SaveFlatFile[*ScheduleFile](filepath.Dir(path), loadedSchedules, saveOptions...)
+}
+
+// Writes when each schedule last ran to the state file
+func SaveState() error {
+
+	path := string(configs.GetConfig().FileScheduleState)
+
+	state := &ScheduleStateFile{Schedules: map[string]ScheduleState{}}
+	for _, s := range loadedSchedules.Schedules {
+		if s.LastRunRound > 0 || !s.LastRunTime.IsZero() {
+			state.Schedules[s.ScheduleId] = ScheduleState{LastRunRound: s.LastRunRound, LastRunTime: s.LastRunTime}
+		}
+	}
+
+	saveOptions := []fileloader.SaveOption{}
+	if configs.GetConfig().CarefulSaveFiles {
+		saveOptions = append(saveOptions, fileloader.SaveCareful)
+	}
+
+	return fileloader.SaveFlatFile[*ScheduleStateFile](filepath.Dir(path), state, saveOptions...)
+}
+
+func LoadDataFiles() {
+
+	start := time.Now()
+
+	path := string(configs.GetConfig().FileSchedules)
+
+	// The schedule file is optional
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		loadedSchedules = &ScheduleFile{}
+		slog.Info("schedules.LoadDataFiles()", "loadedCount", 0, "Time Taken", time.Since(start))
+		return
+	}
+
+	tmpSchedules, err := fileloader.LoadFlatFile[*ScheduleFile](path)
+	if err != nil {
+		panic(err)
+	}
+
+	loadedSchedules = tmpSchedules
+
+	// The state file doesn't exist until a schedule has run
+	statePath := string(configs.GetConfig().FileScheduleState)
+	if _, err := os.Stat(statePath); err == nil {
+
+		state, err := fileloader.LoadFlatFile[*ScheduleStateFile](statePath)
+		if err != nil {
+			panic(err)
+		}
+
+		for _, s := range loadedSchedules.Schedules {
+			if st, ok := state.Schedules[s.ScheduleId]; ok {
+				s.LastRunRound = st.LastRunRound
+				s.LastRunTime = st.LastRunTime
+			}
+		}
+	}
+
+	slog.Info("schedules.LoadDataFiles()", "loadedCount", len(loadedSchedules.Schedules), "Time Taken", time.Since(start))
+}
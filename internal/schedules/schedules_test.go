@@ -0,0 +1,32 @@
+package schedules
+
+import (
+	"testing"
+)
+
+func TestSchedule_Validate(t *testing.T) {
+
+	actions := []Action{{Broadcast: `Hello`}}
+
+	tests := []struct {
+		name     string
+		schedule Schedule
+		valid    bool
+	}{
+		{`gametime`, Schedule{ScheduleId: `dawn`, GameTime: `sunrise`, Actions: actions}, true},
+		{`known moon`, Schedule{ScheduleId: `howl`, GameTime: `fullmoon`, Moon: `Arcanix`, Actions: actions}, true},
+		{`unknown moon`, Schedule{ScheduleId: `howl`, GameTime: `fullmoon`, Moon: `luna`, Actions: actions}, false},
+		{`months`, Schedule{ScheduleId: `winter`, GameTime: `sunrise`, Months: []int{1, 12}, Actions: actions}, true},
+		{`month 0`, Schedule{ScheduleId: `winter`, GameTime: `sunrise`, Months: []int{0}, Actions: actions}, false},
+		{`month 13`, Schedule{ScheduleId: `winter`, GameTime: `sunrise`, Months: []int{13}, Actions: actions}, false},
+		{`no actions`, Schedule{ScheduleId: `dawn`, GameTime: `sunrise`}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.schedule.Validate(); (err == nil) != tt.valid {
+				t.Errorf("Validate() = %v, expected valid %t", err, tt.valid)
+			}
+		})
+	}
+}
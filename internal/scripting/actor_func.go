@@ -145,6 +145,22 @@ func (a ScriptActor) GetTempData(key string) any {
 	return nil
 }
 
+// Players save to their character, mobs save to the spawn definition they came from.
+func (a ScriptActor) SetPermData(key string, value any) bool {
+	return setPermData(a.getPermDataStore(), key, value)
+}
+
+func (a ScriptActor) GetPermData(key string) any {
+	return getPermData(a.getPermDataStore(), key)
+}
+
+func (a ScriptActor) getPermDataStore() PermDataStore {
+	if a.userRecord != nil {
+		return UserPermData(a.userRecord)
+	}
+	return MobPermData(a.mobRecord)
+}
+
 func (a ScriptActor) GetTameMastery() map[int]int {
 	return a.characterRecord.MobMastery.GetAllTame()
 }
@@ -331,6 +347,8 @@ func (a ScriptActor) TrainSkill(skillName string, skillLevel int) bool {
 		skillUpTxt, _ := templates.Process("character/skillup", skillData)
 		a.SendText(skillUpTxt)
 
+		TryPlayerHook(`onSkillGained`, a.userId, HookDetails{`skill`: skillName, `level`: newLevel})
+
 		return true
 
 	}
@@ -465,7 +483,7 @@ func (a ScriptActor) HasItemId(itemId int, excludeWorn ...bool) bool {
 func (a ScriptActor) GetBackpackItems() []ScriptItem {
 	itms := make([]ScriptItem, 0, 5)
 	for _, item := range a.characterRecord.GetAllBackpackItems() {
-		itms = append(itms, newScriptItem(item))
+		itms = append(itms, newScriptItem(item, a.characterRecord))
 	}
 	return itms
 }
@@ -698,6 +716,7 @@ func (a ScriptActor) GetLastInputRound() uint64 {
 // # These functions get exported to the scripting engine
 //
 // ////////////////////////////////////////////////////////
+
 func GetActor(userId int, mobInstanceId int) *ScriptActor {
 
 	if userId > 0 {
@@ -0,0 +1,23 @@
+package scripting
+
+import (
+	"github.com/dop251/goja"
+)
+
+//go:generate go run ./gendocs -src . -ts docs/gomud.d.ts -md docs/API_REFERENCE.md
+
+// Returns the Go values that every script VM receives as globals, such as GetRoom.
+// Objects such as console are returned as a map of their functions.
+// Used to generate the scripting API docs.
+func APIGlobals() map[string]any {
+
+	vm := goja.New()
+	setAllScriptingFunctions(vm)
+
+	globals := map[string]any{}
+	for _, name := range vm.GlobalObject().Keys() {
+		globals[name] = vm.Get(name).Export()
+	}
+
+	return globals
+}
@@ -24,7 +24,12 @@ func PruneBuffVMs(instanceIds ...int) {
 	// Do not prune, they dont' get a VM per buff instance.
 }
 
-func TryBuffScriptEvent(eventName string, userId int, mobInstanceId int, buffId int) (bool, error) {
+func TryBuffScriptEvent(eventName string, userId int, mobInstanceId int, buffId int) (handled bool, err error) {
+
+	defer recoverScriptPanic(&err, eventName, userId, mobInstanceId, 0, func() { delete(buffVMCache, buffId) })
+
+	pushScriptOwner(scriptOwner{})
+	defer popScriptOwner()
 
 	slog.Info("TryBuffScriptEvent()", "eventName", eventName, "buffId", buffId)
 	vmw, err := getBuffVM(buffId)
@@ -85,7 +90,12 @@ func TryBuffScriptEvent(eventName string, userId int, mobInstanceId int, buffId
 	return false, nil
 }
 
-func TryBuffCommand(cmd string, rest string, userId int, mobInstanceId int, buffId int) (bool, error) {
+func TryBuffCommand(cmd string, rest string, userId int, mobInstanceId int, buffId int) (handled bool, err error) {
+
+	defer recoverScriptPanic(&err, `onCommand_`+cmd, userId, mobInstanceId, 0, func() { delete(buffVMCache, buffId) })
+
+	pushScriptOwner(scriptOwner{})
+	defer popScriptOwner()
 
 	vmw, err := getBuffVM(buffId)
 	if err != nil {
@@ -196,10 +206,14 @@ func getBuffVM(buffId int) (*VMWrapper, error) {
 		return nil, errNoScript
 	}
 
+	namespace := fmt.Sprintf(`buff-%d`, buffId)
+	pushScriptNamespace(namespace)
+	defer popScriptNamespace()
+
 	vm := goja.New()
 	setAllScriptingFunctions(vm)
 
-	prg, err := goja.Compile(fmt.Sprintf(`buff-%d`, buffId), script, false)
+	prg, err := goja.Compile(namespace, script, false)
 	if err != nil {
 		finalErr := fmt.Errorf("Compile: %w", err)
 		return nil, finalErr
@@ -231,6 +245,8 @@ func getBuffVM(buffId int) (*VMWrapper, error) {
 	tmr.Stop()
 
 	vmw := newVMWrapper(vm, 0)
+	vmw.namespace = namespace
+	setScriptFile(namespace, bSpec.GetScriptPath())
 
 	buffVMCache[buffId] = vmw
 
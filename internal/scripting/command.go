@@ -0,0 +1,385 @@
+package scripting
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dop251/goja"
+	"github.com/volte6/gomud/internal/users"
+)
+
+const (
+	// Each script in this folder adds a new command
+	commandScriptsPath = `_datafiles/commands`
+)
+
+var (
+	// Guards scriptCommands, scriptCommandAliases and the commands' VMs.
+	// Only the world goroutine changes them, so it can read them without the lock,
+	// but autocomplete and help read them from connection goroutines.
+	scriptCommandsLock = sync.RWMutex{}
+	// command name => command script
+	scriptCommands = map[string]*ScriptCommand{}
+	// alias => command name
+	scriptCommandAliases = map[string]string{}
+	// file => modified time of command scripts that failed to load, so they aren't retried until they change
+	brokenCommandScripts = map[string]time.Time{}
+)
+
+// A command defined by a script in the commands folder
+type ScriptCommand struct {
+	Name              string
+	Aliases           []string
+	Help              string
+	Category          string
+	AdminOnly         bool
+	AllowedWhenDowned bool
+
+	file    string
+	modTime time.Time
+	vmw     *VMWrapper // nil until the command is first used after a reload
+}
+
+func (c *ScriptCommand) namespace() string {
+	return `command-` + c.Name
+}
+
+// Drops the loaded command scripts so they are recompiled the next time they are used.
+// The list of commands is kept.
+func ClearCommandVMs() {
+	scriptCommandsLock.Lock()
+	defer scriptCommandsLock.Unlock()
+
+	for _, cmd := range scriptCommands {
+		cmd.vmw = nil
+	}
+}
+
+// Loads any command scripts that are new or have changed since they were last loaded,
+// and forgets any whose files have been removed.
+// Safe to call often, since unchanged files are skipped.
+// Returns how many command scripts were loaded.
+func LoadCommandScripts() int {
+
+	loadedCt := 0
+	seenFiles := map[string]struct{}{}
+
+	filePaths, _ := filepath.Glob(filepath.Join(commandScriptsPath, `*.js`))
+	sort.Strings(filePaths)
+
+	for _, filePath := range filePaths {
+
+		// Script tests live alongside the scripts they test
+		if strings.HasSuffix(filePath, scriptTestSuffix) {
+			continue
+		}
+
+		info, err := os.Stat(filePath)
+		if err != nil {
+			continue
+		}
+
+		seenFiles[filePath] = struct{}{}
+
+		if existing := findCommandByFile(filePath); existing != nil && existing.modTime.Equal(info.ModTime()) {
+			continue
+		}
+
+		if modTime, ok := brokenCommandScripts[filePath]; ok && modTime.Equal(info.ModTime()) {
+			continue
+		}
+
+		cmd, err := loadCommandScript(filePath)
+		if err != nil {
+			brokenCommandScripts[filePath] = info.ModTime()
+			slog.Error("LoadCommandScripts()", "file", filePath, "error", err)
+			continue
+		}
+		cmd.modTime = info.ModTime()
+
+		if other, ok := scriptCommands[cmd.Name]; ok && other.file != filePath {
+			brokenCommandScripts[filePath] = info.ModTime()
+			slog.Error("LoadCommandScripts()", "file", filePath, "error", "command already defined", "command", cmd.Name, "definedIn", other.file)
+			continue
+		}
+
+		delete(brokenCommandScripts, filePath)
+
+		scriptCommandsLock.Lock()
+		removeCommandByFile(filePath)
+		scriptCommands[cmd.Name] = cmd
+		scriptCommandsLock.Unlock()
+		loadedCt++
+
+		slog.Info("LoadCommandScripts()", "command", cmd.Name, "file", filePath)
+	}
+
+	scriptCommandsLock.Lock()
+	for _, cmd := range scriptCommands {
+		if _, ok := seenFiles[cmd.file]; !ok {
+			slog.Info("LoadCommandScripts()", "removed", cmd.Name, "file", cmd.file)
+			removeCommandByFile(cmd.file)
+		}
+	}
+
+	rebuildCommandAliases()
+	scriptCommandsLock.Unlock()
+
+	return loadedCt
+}
+
+func findCommandByFile(filePath string) *ScriptCommand {
+	for _, cmd := range scriptCommands {
+		if cmd.file == filePath {
+			return cmd
+		}
+	}
+	return nil
+}
+
+func removeCommandByFile(filePath string) {
+	for name, cmd := range scriptCommands {
+		if cmd.file == filePath {
+			delete(scriptCommands, name)
+		}
+	}
+}
+
+func rebuildCommandAliases() {
+	clear(scriptCommandAliases)
+	for _, cmd := range scriptCommands {
+		for _, alias := range cmd.Aliases {
+			scriptCommandAliases[alias] = cmd.Name
+		}
+	}
+}
+
+// Finds a script command by name or alias
+func GetScriptCommand(name string) (ScriptCommand, bool) {
+
+	scriptCommandsLock.RLock()
+	defer scriptCommandsLock.RUnlock()
+
+	name = strings.ToLower(name)
+	if cmdName, ok := scriptCommandAliases[name]; ok {
+		name = cmdName
+	}
+
+	if cmd, ok := scriptCommands[name]; ok {
+		return *cmd, true
+	}
+	return ScriptCommand{}, false
+}
+
+// Returns every script command, sorted by name
+func GetScriptCommands() []ScriptCommand {
+
+	scriptCommandsLock.RLock()
+	defer scriptCommandsLock.RUnlock()
+
+	ret := make([]ScriptCommand, 0, len(scriptCommands))
+	for _, cmd := range scriptCommands {
+		ret = append(ret, *cmd)
+	}
+
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].Name < ret[j].Name
+	})
+
+	return ret
+}
+
+func setCommandVM(cmd *ScriptCommand, vmw *VMWrapper) {
+	scriptCommandsLock.Lock()
+	cmd.vmw = vmw
+	scriptCommandsLock.Unlock()
+}
+
+// Runs a script command for a user.
+// Permissions should already have been checked.
+// Returns false if the command doesn't exist, or the script returned false.
+func TryScriptCommand(name string, rest string, userId int) (handled bool, err error) {
+
+	sCmd, ok := GetScriptCommand(name)
+	if !ok {
+		return false, nil
+	}
+	cmd := scriptCommands[sCmd.Name]
+
+	user := users.GetByUserId(userId)
+	if user == nil {
+		return false, errors.New("user not found")
+	}
+
+	defer recoverScriptPanic(&err, cmd.namespace(), userId, 0, user.Character.RoomId, func() { setCommandVM(cmd, nil) })
+
+	pushScriptOwner(scriptOwner{})
+	defer popScriptOwner()
+
+	if cmd.vmw == nil {
+		reloaded, err := loadCommandScript(cmd.file)
+		if err != nil {
+			return false, err
+		}
+		setCommandVM(cmd, reloaded.vmw)
+	}
+	vmw := cmd.vmw
+
+	timestart := time.Now()
+	defer func() {
+		slog.Debug("TryScriptCommand()", "command", cmd.Name, "time", time.Since(timestart))
+	}()
+
+	onCommandFunc, ok := vmw.GetFunction(`onCommand`)
+	if !ok {
+		return false, fmt.Errorf("%s: onCommand() not found", cmd.file)
+	}
+
+	sUser := GetActor(userId, 0)
+	sRoom := GetRoom(user.Character.RoomId)
+
+	// Set forced ansi tag wrappers
+	userTextWrap.Set(`script-text`, ``, ``)
+	roomTextWrap.Set(`script-text`, ``, ``)
+
+	tmr := time.AfterFunc(scriptRoomTimeout, func() {
+		vmw.VM.Interrupt(errTimeout)
+	})
+	res, err := onCommandFunc(goja.Undefined(),
+		vmw.VM.ToValue(rest),
+		vmw.VM.ToValue(sUser),
+		vmw.VM.ToValue(sRoom),
+	)
+	vmw.VM.ClearInterrupt()
+	tmr.Stop()
+
+	userTextWrap.Reset()
+	roomTextWrap.Reset()
+
+	if err != nil {
+
+		// Wrap the error
+		finalErr := fmt.Errorf("%s onCommand(): %w", cmd.Name, err)
+
+		if _, ok := finalErr.(*goja.Exception); ok {
+			slog.Error("JSVM", "exception", finalErr)
+			return false, finalErr
+		} else if errors.Is(finalErr, errTimeout) {
+			slog.Error("JSVM", "interrupted", finalErr)
+			return false, finalErr
+		}
+
+		slog.Error("JSVM", "error", finalErr)
+		return false, finalErr
+	}
+
+	// Commands are handled unless they say otherwise
+	if boolVal, ok := res.Export().(bool); ok {
+		return boolVal, nil
+	}
+
+	return true, nil
+}
+
+// Compiles a command script and reads its "command" definition
+func loadCommandScript(filePath string) (*ScriptCommand, error) {
+
+	scriptBytes, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := &ScriptCommand{
+		Name: strings.ToLower(strings.TrimSuffix(filepath.Base(filePath), `.js`)),
+		file: filePath,
+	}
+
+	vm := goja.New()
+	setAllScriptingFunctions(vm)
+
+	prg, err := goja.Compile(filePath, string(scriptBytes), false)
+	if err != nil {
+		return nil, fmt.Errorf("Compile: %w", err)
+	}
+
+	//
+	// Run the program
+	//
+	tmr := time.AfterFunc(scriptLoadTimeout, func() {
+		vm.Interrupt(errTimeout)
+	})
+	_, err = vm.RunProgram(prg)
+	vm.ClearInterrupt()
+	tmr.Stop()
+
+	if err != nil {
+		return nil, fmt.Errorf("RunProgram: %w", err)
+	}
+
+	if _, ok := goja.AssertFunction(vm.Get(`onCommand`)); !ok {
+		return nil, errors.New("onCommand() not found")
+	}
+
+	// The definition is optional. Without one, the command is named after the file.
+	var def map[string]any
+	if defVal := vm.Get(`command`); defVal != nil {
+		def, _ = defVal.Export().(map[string]any)
+	}
+
+	if def != nil {
+
+		if name, ok := def[`name`].(string); ok && name != `` {
+			cmd.Name = strings.ToLower(name)
+		}
+
+		if aliases, ok := def[`aliases`].([]any); ok {
+			for _, alias := range aliases {
+				if aliasStr, ok := alias.(string); ok && aliasStr != `` {
+					cmd.Aliases = append(cmd.Aliases, strings.ToLower(aliasStr))
+				}
+			}
+		}
+
+		cmd.Help, _ = def[`help`].(string)
+		cmd.Category, _ = def[`category`].(string)
+		cmd.AdminOnly, _ = def[`adminOnly`].(bool)
+		cmd.AllowedWhenDowned, _ = def[`allowedWhenDowned`].(bool)
+	}
+
+	if strings.ContainsAny(cmd.Name, " \t") {
+		return nil, fmt.Errorf("invalid command name: %q", cmd.Name)
+	}
+
+	pushScriptNamespace(cmd.namespace())
+	defer popScriptNamespace()
+
+	//
+	// Run onLoad() function
+	//
+	if fn, ok := goja.AssertFunction(vm.Get(`onLoad`)); ok {
+
+		tmr = time.AfterFunc(scriptLoadTimeout, func() {
+			vm.Interrupt(errTimeout)
+		})
+		_, err = fn(goja.Undefined())
+		vm.ClearInterrupt()
+		tmr.Stop()
+
+		if err != nil {
+			return nil, fmt.Errorf("onLoad: %w", err)
+		}
+	}
+
+	cmd.vmw = newVMWrapper(vm, 0)
+	cmd.vmw.namespace = cmd.namespace()
+	setScriptFile(cmd.namespace(), filePath)
+
+	return cmd, nil
+}
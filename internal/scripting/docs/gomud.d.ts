@@ -0,0 +1,595 @@
+// Code generated by gendocs from internal/scripting. DO NOT EDIT.
+// Reference this file from a jsconfig.json to get autocompletion and type checking in scripts.
+
+declare function ActorNames(actorList: ActorObject[]): string;
+
+declare function ClearTimer(timerId: number): boolean;
+
+declare function ColorWrap(txt: string, ...colorClass: string[]): string;
+
+/** CreateItem creates a NEW instance of an item by id */
+declare function CreateItem(itemId: number): ItemObject | null;
+
+/**
+ * mapRoomId    - Room the map is centered on
+ * mapSize      - wide or normal
+ * mapHeight	- Height of the map
+ * mapWidth     - Width of the map
+ * mapName 		- The title of the map
+ * showSecrets  - Include secret exits/rooms?
+ * mapMarkers   - A list of strings representing custom map markers:
+ *
+ * 	[roomId],[symbol],[legend text]
+ * 	1,×,Here
+ */
+declare function GetMap(mapRoomId: number, mapSize: string, mapHeight: number, mapWidth: number, mapName: string, showSecrets: boolean, ...mapMarkers: string[]): string;
+
+/**
+ * Same as GetMap, but draws the level above or below mapRoomId
+ * mapLevel     - The level to draw, relative to mapRoomId. 1 is the level above, -1 below.
+ */
+declare function GetMapLevel(mapRoomId: number, mapSize: string, mapHeight: number, mapWidth: number, mapName: string, showSecrets: boolean, mapLevel: number, ...mapMarkers: string[]): string;
+
+declare function GetMob(mobInstanceId: number): ActorObject | null;
+
+declare function GetRoom(roomId: number): RoomObject | null;
+
+declare function GetUser(userId: number): ActorObject | null;
+
+declare function PersistTimer(timerId: number): boolean;
+
+declare function SendBroadcast(message: string): void;
+
+declare function SendRoomExitsMessage(roomId: number, message: string, isQuiet: boolean, ...excludeUserIds: number[]): void;
+
+declare function SendRoomMessage(roomId: number, message: string, ...excludeIds: number[]): void;
+
+declare function SendUserMessage(userId: number, message: string): void;
+
+declare function SetInterval(rounds: number, fnName: string, ...args: any[]): number;
+
+declare function SetTimeout(rounds: number, fnName: string, ...args: any[]): number;
+
+declare function UtilApplyColorPattern(input: string, patternName: string, ...wordsOnly: boolean[]): string;
+
+declare function UtilDiceRoll(diceQty: number, diceSides: number): number;
+
+declare function UtilFindMatchIn(search: string, items: string[]): Record<string, any>;
+
+declare function UtilGetConfig(): Config;
+
+declare function UtilGetMinutesToRounds(minutes: number): number;
+
+declare function UtilGetMinutesToTurns(minutes: number): number;
+
+declare function UtilGetRoundNumber(): number;
+
+declare function UtilGetSecondsToRounds(seconds: number): number;
+
+declare function UtilGetSecondsToTurns(seconds: number): number;
+
+declare function UtilGetTime(): GameDate;
+
+declare function UtilGetTimeString(): string;
+
+declare function UtilIsDay(): boolean;
+
+declare function UtilLocateUser(idOrName: any): number;
+
+declare function UtilSetTime(hour: number, minutes: number): void;
+
+declare function UtilSetTimeDay(): void;
+
+declare function UtilSetTimeNight(): void;
+
+declare function UtilStripPrepositions(input: string): string;
+
+declare const console: {
+    debug(msg: any): void;
+    error(msg: any): void;
+    info(msg: any): void;
+    log(msg: any): void;
+    warn(msg: any): void;
+};
+
+interface ActorObject {
+    AddGold(amt: number, ...bankAmt: number[]): void;
+    AddHealth(amt: number): number;
+    AddMana(amt: number): number;
+    CancelBuffWithFlag(buffFlag: string): boolean;
+    ChangeAlignment(alignmentChange: number): void;
+    CharmExpire(): void;
+    CharmRemove(): void;
+    CharmSet(userId: number, charmRounds: number, ...onRevertCommand: string[]): void;
+    Command(cmd: string, ...waitTurns: number[]): void;
+    GetAlignment(): number;
+    GetAlignmentName(): string;
+    GetBackpackItems(): ItemObject[];
+    GetChanceToTame(target: ActorObject): number;
+    GetCharacterName(wrapInTags: boolean): string;
+    GetCharmCount(): number;
+    GetCharmedUserId(): number;
+    GetHealth(): number;
+    GetHealthMax(): number;
+    GetHealthPct(): number;
+    GetLastInputRound(): number;
+    GetLevel(): number;
+    GetMana(): number;
+    GetManaMax(): number;
+    GetManaPct(): number;
+    GetMaxCharmCount(): number;
+    GetMiscCharacterData(key: string): any;
+    GetMiscCharacterDataKeys(...prefixMatches: string[]): string[];
+    GetMobKills(mobId: number): number;
+    GetPartyMembers(): ActorObject[];
+    GetPermData(key: string): any;
+    GetPet(): Pet | null;
+    GetRace(): string;
+    GetRaceKills(race: string): number;
+    GetRoomId(): number;
+    GetSize(): string;
+    GetSkillLevel(skillName: string): number;
+    GetStat(statName: string): number;
+    GetStatMod(statModName: string): number;
+    GetStatPoints(): number;
+    GetTameMastery(): Record<number, number>;
+    GetTempData(key: string): any;
+    GetTrainingPoints(): number;
+    GiveBuff(buffId: number): void;
+    GiveExtraLife(): void;
+    GiveItem(itm: ItemObject): void;
+    GiveQuest(questId: string): void;
+    GiveStatPoints(ct: number): void;
+    GiveTrainingPoints(ct: number): void;
+    GrantXP(xpAmt: number, reason: string): void;
+    HasBuff(buffId: number): boolean;
+    HasBuffFlag(buffFlag: string): boolean;
+    HasItemId(itemId: number, ...excludeWorn: boolean[]): boolean;
+    HasQuest(questId: string): boolean;
+    HasSpell(spellId: string): boolean;
+    InstanceId(): number;
+    IsAggro(actor: ActorObject): boolean;
+    /**
+     * Returns true if a mob is charmed by/friendly to a player.
+     * If userId is ommitted, it will return true if the mob is charmed by any player.
+     */
+    IsCharmed(...userId: number[]): boolean;
+    IsTameable(): boolean;
+    LearnSpell(spellId: string): boolean;
+    MobTypeId(): number;
+    MoveRoom(destRoomId: number, ...leaveCharmedMobs: boolean[]): void;
+    /** Remove a buff silently */
+    RemoveBuff(buffId: number): boolean;
+    SendText(msg: string): void;
+    SetAdjective(adj: string, addIt: boolean): void;
+    SetCharacterName(newName: string): void;
+    SetMiscCharacterData(key: string, value: any): void;
+    /** Players save to their character, mobs save to the spawn definition they came from. */
+    SetPermData(key: string, value: any): boolean;
+    SetTameMastery(mobId: number, newSkillLevel: number): void;
+    SetTempData(key: string, value: any): void;
+    ShorthandId(): string;
+    Sleep(seconds: number): void;
+    TakeItem(itm: ItemObject): void;
+    TrainSkill(skillName: string, skillLevel: number): boolean;
+    Uncurse(): ItemObject[];
+    UpdateItem(itm: ItemObject): void;
+    UserId(): number;
+}
+
+interface Config {
+    /** Cuurrent version of all datafiles */
+    Version: string;
+    MaxCPUCores: number;
+    FolderItemData: string;
+    FolderAttackMessageData: string;
+    FolderUserData: string;
+    FolderSpellData: string;
+    FolderTemplates: string;
+    FolderRecordings: string;
+    FolderCrashReports: string;
+    FolderZoneExports: string;
+    FileAnsiAliases: string;
+    FileColorPatterns: string;
+    FileKeywords: string;
+    FileSchedules: string;
+    FileScheduleState: string;
+    FileVehicles: string;
+    FileWorldScript: string;
+    FileAdminAudit: string;
+    AllowItemBuffRemoval: boolean;
+    CarefulSaveFiles: boolean;
+    AuctionsEnabled: boolean;
+    AuctionsAnonymous: boolean;
+    AuctionSeconds: number;
+    AuctionUpdateSeconds: number;
+    PVP: string;
+    PVPMinimumLevel: number;
+    XPScale: number;
+    TurnMs: number;
+    RoundSeconds: number;
+    RoundsPerAutoSave: number;
+    /** How many rounds are in a day */
+    RoundsPerDay: number;
+    /** How many hours of night */
+    NightHours: number;
+    MaxMobBoredom: number;
+    /** How long to spend the first time a script is loaded into memory */
+    ScriptLoadTimeoutMs: number;
+    /** How many milliseconds to allow a script to run before it is interrupted */
+    ScriptRoomTimeoutMs: number;
+    /** How much permanent data one script can save on a single item, character or spawn */
+    ScriptPermDataMaxBytes: number;
+    /** If above zero, scripts are interrupted if memory use grows by this much while they run */
+    ScriptMaxHeapGrowthMB: number;
+    /** If true, admins can run javascript inside live scripts with the eval and repl commands */
+    ScriptEvalEnabled: boolean;
+    /** How many rounds between checks for changed scripts. 0 disables it. */
+    ScriptWatchRounds: number;
+    /** Maximum number of telnet connections to accept */
+    MaxTelnetConnections: number;
+    /** One or more Ports used to accept telnet connections */
+    TelnetPort: string[];
+    /** Port used for admin connections, localhost only */
+    LocalPort: number;
+    /** Port used for web requests */
+    WebPort: number;
+    /** The next room id to use when creating a new room */
+    NextRoomId: number;
+    /** How often to spawn a loot goblin */
+    LootGoblinRoundCount: number;
+    /** How many items on the ground to attract the loot goblin */
+    LootGoblinMinimumItems: number;
+    /** How much gold on the ground to attract the loot goblin */
+    LootGoblinMinimumGold: number;
+    /** should the goblin include rooms that have been visited recently? */
+    LootGoblinIncludeRecentRooms: boolean;
+    /** How often to report the current round number. */
+    LogIntervalRoundCount: number;
+    /** List of locked config properties that cannot be changed without editing the file directly. */
+    Locked: string[];
+    /** Seed that may be used for generating content */
+    Seed: string;
+    /** Commands to run when a user logs in */
+    OnLoginCommands: string[];
+    /** Message of the day to display when a user logs in */
+    Motd: string;
+    /** List of names that are not allowed to be used */
+    BannedNames: string[];
+    /** How to format time when displaying real time */
+    TimeFormat: string;
+    /** How to format time when displaying real time (shortform) */
+    TimeFormatShort: string;
+    /** Chance a player will drop a given piece of equipment on death */
+    OnDeathEquipmentDropChance: number;
+    /** If true, players will always drop their backpack items on death */
+    OnDeathAlwaysDropBackpack: boolean;
+    /** Possible values are: none, level, 10%, 25%, 50%, 75%, 90%, 100% */
+    OnDeathXPPenalty: string;
+    EnterRoomMessageWrapper: string;
+    ExitRoomMessageWrapper: string;
+    /** How many seconds a player can go without a command in game before being kicked. */
+    MaxIdleSeconds: number;
+    /** Whether to kick admin/mods when idle too long. */
+    TimeoutMods: boolean;
+    /** Whether players are told when they are being snooped: none, anonymous, named */
+    SnoopNotice: string;
+    /** How many seconds a player will be a zombie allowing them to reconnect. */
+    ZombieSeconds: number;
+    /** How many rounds of uninterrupted meditation must be completed to log out. */
+    LogoutRounds: number;
+    /** Default starting room. */
+    StartRoom: number;
+    /** List of all rooms that can be used to begin the tutorial process */
+    TutorialStartRooms: string[];
+    /** Perma-death related configs */
+    PermaDeath: boolean;
+    /** Starting permadeath lives */
+    LivesStart: number;
+    /** Maximum permadeath lives */
+    LivesMax: number;
+    /** # lives gained on level up */
+    LivesOnLevelUp: number;
+    /** Price in gold to buy new lives */
+    PricePerLife: number;
+    /** Default time it takes to restock 1 quantity in shops */
+    ShopRestockRate: string;
+    /** Whether each weapon has consistent attack messages */
+    ConsistentAttackMessages: boolean;
+    /** How many characters beyond the default character can they create? */
+    MaxAltCharacters: number;
+    /** How long until a player is marked as afk? */
+    AfkSeconds: number;
+    /** Maximum size of leaderboard */
+    LeaderboardSize: number;
+    /** Size a session recording can reach before a new file is started */
+    RecordingMaxFileKB: number;
+    /** How many recording files to keep per user before the oldest are deleted */
+    RecordingMaxFiles: number;
+    SeedInt: number;
+    /** Last saved round count */
+    RoundCount: number;
+    /** Get all config data in a map with the field name as the key for easy iteration */
+    AllConfigData(...excludeStrings: string[]): Record<string, any>;
+    GetDeathXPPenalty(): any[];
+    GetOverrides(): Record<string, any>;
+    IsBannedName(name: string): any[];
+    MinutesToRounds(minutes: number): number;
+    MinutesToTurns(minutes: number): number;
+    RoundsToSeconds(rounds: number): number;
+    SecondsToRounds(seconds: number): number;
+    SecondsToTurns(seconds: number): number;
+    SetOverrides(overrides: Record<string, any>): void;
+    TurnsPerAutoSave(): number;
+    TurnsPerRound(): number;
+    TurnsPerSecond(): number;
+    /** Ensures certain ranges and defaults are observed */
+    Validate(): void;
+}
+
+interface Damage {
+    /** How many attacks this weapon gets (usually 1) */
+    Attacks: number;
+    /** 1d6, etc. */
+    DiceRoll: string;
+    /** If this damage is a crit, what buffs does it apply? */
+    CritBuffIds: number[];
+    /** how many dice to roll for this weapons damage */
+    DiceCount: number;
+    /** how many sides per dice roll */
+    SideCount: number;
+    /** flat damage bonus, so for example 1d6+1 */
+    BonusDamage: number;
+    FormatDiceRoll(): string;
+    InitDiceRoll(dRoll: string): void;
+    String(): string;
+}
+
+interface GameDate {
+    /** The round number this GameDate represents */
+    RoundNumber: number;
+    RoundsPerDay: number;
+    NightHoursPerDay: number;
+    Year: number;
+    Month: number;
+    Week: number;
+    Day: number;
+    Hour: number;
+    Hour24: number;
+    Minute: number;
+    MinuteFloat: number;
+    AmPm: string;
+    Night: boolean;
+    DayStart: number;
+    NightStart: number;
+    /** Returns the total number of days that have passed since day 1 of year 1 */
+    AbsoluteDay(): number;
+    Add(adjustHours: number, adjustDays: number, adjustYears: number): GameDate;
+    /**
+     * Example:
+     * gd := gametime.GetDate()
+     * nextPeriodRound := gd.AddPeriod(`10 days`)
+     * Accepts: x years, x months, x weeks, x days, x hours, x rounds
+     * If `IRL` or `real` are in the mix, such as `x irl days` or `x days irl`, then it will use real world time
+     */
+    AddPeriod(str: string): number;
+    ReCalculate(): void;
+    String(...symbolOnly: boolean[]): string;
+}
+
+/** Instance properties that may change */
+interface Item {
+    ItemId: number;
+    /** Does this item have a blob? Should be base64 encoded. */
+    Blob: string;
+    /** How many uses it has left */
+    Uses: number;
+    /** Last round this item was used */
+    LastUsedRound: number;
+    Spec: ItemSpec;
+    /** Is this item uncursed? */
+    Uncursed: boolean;
+    /** Is this item enchanted? */
+    Enchantments: number;
+    /** Decorative text for the name of the item (e.g. "exploding") */
+    Adjectives: string[];
+    /** userid of whoever stashed this item */
+    StashedBy: number;
+    /** Data saved by scripts. Keys are prefixed with the script namespace. */
+    PermData: Record<string, any>;
+    AddWornBuff(buffId: number): void;
+    AttrString(): string;
+    /**
+     * performs a break test and returns true if the item breaks
+     * Pass a uint8 to increase the chance of breaking.
+     */
+    BreakTest(...increaseChance: number[]): boolean;
+    DisplayName(): string;
+    /** enchantmentLevel is 0-100. If 0(zero) remove any enchantments. */
+    Enchant(damageBonus: number, defenseBonus: number, statBonus: Record<string, number>, cursed: boolean): void;
+    Equals(b: Item): boolean;
+    GetBlob(): string;
+    GetDamage(): Damage;
+    /** Returns a random number up to the total possible reduction for this item. */
+    GetDefense(): number;
+    /**
+     * Gets the specifics of the item damage
+     * Considers overrides
+     */
+    GetDiceRoll(): any[];
+    GetLongDescription(): string;
+    GetPermData(key: string): any;
+    GetPermDataKeys(): string[];
+    GetScript(): string;
+    GetSpec(): ItemSpec;
+    GetTempData(key: string): any;
+    HasAdjective(adj: string): boolean;
+    IsBetterThan(otherItm: Item): boolean;
+    IsCursed(): boolean;
+    IsDisabled(): boolean;
+    IsEnchanted(): boolean;
+    IsSpecial(): boolean;
+    IsValid(): boolean;
+    Name(): string;
+    NameComplex(): string;
+    NameMatch(input: string, allowContains: boolean): any[];
+    NameSimple(): string;
+    Redescribe(newDescription: string): void;
+    Rename(newName: string, ...displayNameOrStyle: string[]): void;
+    SetAdjective(adj: string, addToList: boolean): void;
+    SetBlob(blob: string): void;
+    SetPermData(key: string, value: any): void;
+    SetTempData(key: string, value: any): void;
+    ShorthandId(): string;
+    StatMod(...statName: string[]): number;
+    UnEnchant(): void;
+    Uncurse(): void;
+    UniqueId(): number;
+    Validate(): void;
+}
+
+interface ItemObject {
+    AddUsesLeft(amount: number): number;
+    GetLastUsedRound(): number;
+    GetPermData(key: string): any;
+    GetTempData(key: string): any;
+    GetUsesLeft(): number;
+    ItemId(): number;
+    MarkLastUsed(...clear: boolean[]): number;
+    Name(...simpleVersion: boolean[]): string;
+    NameComplex(): string;
+    NameSimple(): string;
+    Redescribe(newDescription: string): void;
+    Rename(newName: string, ...displayNameOrStyle: string[]): void;
+    SetPermData(key: string, value: any): boolean;
+    SetTempData(key: string, value: any): void;
+    SetUsesLeft(amount: number): number;
+    ShorthandId(): string;
+}
+
+/** The blueprint for an item */
+interface ItemSpec {
+    ItemId: number;
+    Value: number;
+    /** How many uses it starts with */
+    Uses: number;
+    /** What buffs it can apply (if used) */
+    BuffIds: number[];
+    /** BuffId's that are applied while worn, and expired when removed. */
+    WornBuffIds: number[];
+    /** % of damage it reduces when it blocks attacks */
+    DamageReduction: number;
+    /** How many extra rounds each combat requires */
+    WaitRounds: number;
+    /** How many hands it takes to wield */
+    Hands: number;
+    Name: string;
+    /** Name that is typically displayed to the user */
+    DisplayName: string;
+    /** A simpler name for the item, for example "Golden Battleaxe" should be "Battleaxe" or "Axe" for simple */
+    NameSimple: string;
+    Description: string;
+    /** Grants this quest if given/picked up */
+    QuestToken: string;
+    Type: string;
+    Subtype: string;
+    Damage: Damage;
+    Element: string;
+    /** What stats it modifies when equipped */
+    StatMods: Record<string, number>;
+    /** Chance in 100 that the item will break when used, or when the character is hit with it equipped, or if it is in the characters inventory during an explosion, etc. */
+    BreakChance: number;
+    /** Can't be removed once equipped */
+    Cursed: boolean;
+    /** Example: `778-north` - If it's a key, what lock does it open? roomid-exitname etc. */
+    KeyLockId: string;
+    /** If it's furniture, how many items it holds once placed in a house */
+    Capacity: number;
+    AutoCalculateValue(): void;
+    Filename(): string;
+    Filepath(): string;
+    GetScript(): string;
+    GetScriptPath(): string;
+    /** Presumably to ensure the datafile hasn't messed something up. */
+    Id(): number;
+    ItemFolder(...baseonly: boolean[]): string;
+    /** Presumably to ensure the datafile hasn't messed something up. */
+    Validate(): void;
+}
+
+interface Pet {
+    /** Name of the pet (player provided hopefully) */
+    Name: string;
+    /** Optional color pattern to apply */
+    NameStyle: string;
+    /** type of pet */
+    Type: string;
+    /** how much food the pet has */
+    Food: number;
+    /** When the pet was last fed */
+    LastMealRound: number;
+    /** When the pet was last fed */
+    Damage: Damage;
+    /** stat mods the pet provides */
+    StatMods: Record<string, number>;
+    /** Permabuffs this pet affords the player */
+    BuffIds: number[];
+    /** How many items this mob can carry */
+    Capacity: number;
+    /** Items held by this pet */
+    Items: Item[];
+    DisplayName(): string;
+    Exists(): boolean;
+    Filename(): string;
+    Filepath(): string;
+    FindItem(itemName: string): any[];
+    GetBuffs(): number[];
+    GetDiceRoll(): any[];
+    Id(): string;
+    RemoveItem(i: Item): boolean;
+    Save(): void;
+    StatMod(statName: string): number;
+    StoreItem(i: Item): boolean;
+    Validate(): void;
+}
+
+interface RoomObject {
+    AddMutator(mutName: string): void;
+    AddTemporaryExit(exitNameSimple: string, exitNameFancy: string, exitRoomId: number, expiresTimeString: string): boolean;
+    DestroyItem(itm: ItemObject): void;
+    GetContainers(): string[];
+    GetExits(): Record<string, any>[];
+    GetItems(): ItemObject[];
+    GetMobs(): number[];
+    GetPermData(key: string): any;
+    GetPlayers(): number[];
+    GetTempData(key: string): any;
+    GetWeather(): string;
+    HasMutator(mutName: string): boolean;
+    /**
+     * Returns a list of userIds found to have the questId
+     * if userIdParty is specified, will only check users in the party of the user.
+     */
+    HasQuest(questId: string, ...partyUserId: number[]): number[];
+    /**
+     * Returns a list of userIds found to NOT have the questId
+     * if userIdParty is specified, will only check users in the party of the user.
+     */
+    MissingQuest(questId: string, ...partyUserId: number[]): number[];
+    RemoveMutator(mutName: string): void;
+    RemoveTemporaryExit(exitNameSimple: string, exitNameFancy: string, exitRoomId: number): boolean;
+    RepeatSpawnItem(itemId: number, roundFrequency: number, ...containerName: string[]): boolean;
+    RoomId(): number;
+    SendText(msg: string, ...excludeIds: number[]): void;
+    SendTextToExits(msg: string, isQuiet: boolean, ...excludeUserIds: number[]): void;
+    /**
+     * Opens or closes the door on an exit, along with the door on the other side.
+     * Returns false if the exit has no door.
+     */
+    SetDoorOpen(exitName: string, openIt: boolean): boolean;
+    SetLocked(exitName: string, lockIt: boolean): void;
+    SetPermData(key: string, value: any): void;
+    SetTempData(key: string, value: any): void;
+    SpawnItem(itemId: number, inStash: boolean): void;
+    SpawnMob(mobId: number): ActorObject | null;
+}
@@ -0,0 +1,105 @@
+package scripting
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dop251/goja"
+)
+
+// Runs javascript typed by an admin inside the VM of a room script, with access to its globals.
+// Returns the result of the last statement.
+func EvalRoomScript(roomId int, code string) (result string, err error) {
+
+	defer recoverScriptPanic(&err, `eval`, 0, 0, roomId, func() { roomScriptPanicked(roomId) })
+
+	pushScriptOwner(scriptOwner{kind: ownerRoom, roomId: roomId})
+	defer popScriptOwner()
+
+	vmw, err := getRoomVM(roomId)
+	if err != nil {
+		if errors.Is(err, errNoScript) {
+			return ``, fmt.Errorf("room %d has no script", roomId)
+		}
+		return ``, err
+	}
+
+	return evalInVM(vmw, code)
+}
+
+// Runs javascript typed by an admin inside the VM of a mob script, with access to its globals.
+// Mobs of the same type and script tag share a VM.
+// Returns the result of the last statement.
+func EvalMobScript(mobInstanceId int, code string) (result string, err error) {
+
+	sMob := GetActor(0, mobInstanceId)
+	if sMob == nil {
+		return ``, errors.New("mob not found")
+	}
+
+	defer recoverScriptPanic(&err, `eval`, 0, mobInstanceId, sMob.GetRoomId(), func() { delete(mobVMCache, mobScriptId(sMob)) })
+
+	pushScriptOwner(scriptOwner{kind: ownerMob, mobInstanceId: mobInstanceId, roomId: sMob.GetRoomId()})
+	defer popScriptOwner()
+
+	vmw, err := getMobVM(sMob)
+	if err != nil {
+		if errors.Is(err, errNoScript) {
+			return ``, fmt.Errorf("%s has no script", sMob.GetCharacterName(false))
+		}
+		return ``, err
+	}
+
+	return evalInVM(vmw, code)
+}
+
+// Runs the code under the same timeout and memory limits as any other script call
+func evalInVM(vmw *VMWrapper, code string) (string, error) {
+
+	pushScriptNamespace(vmw.namespace)
+	defer popScriptNamespace()
+
+	userTextWrap.Set(`script-text`, ``, ``)
+	roomTextWrap.Set(`script-text`, ``, ``)
+	defer userTextWrap.Reset()
+	defer roomTextWrap.Reset()
+
+	stopHeapGuard := startHeapGuard(vmw.VM)
+	defer stopHeapGuard()
+
+	tmr := time.AfterFunc(scriptRoomTimeout, func() {
+		vmw.VM.Interrupt(errTimeout)
+	})
+	res, err := vmw.VM.RunString(code)
+	vmw.VM.ClearInterrupt()
+	tmr.Stop()
+
+	if err != nil {
+		if errors.Is(err, errTimeout) {
+			return ``, fmt.Errorf("interrupted after %s", scriptRoomTimeout)
+		}
+		return ``, err
+	}
+
+	return formatEvalResult(res), nil
+}
+
+// Plain javascript objects and arrays are shown as JSON. Everything else uses its string value.
+func formatEvalResult(val goja.Value) string {
+
+	if val == nil || goja.IsUndefined(val) {
+		return `undefined`
+	}
+
+	if obj, ok := val.(*goja.Object); ok {
+		switch obj.Export().(type) {
+		case map[string]any, []any:
+			if b, err := obj.MarshalJSON(); err == nil {
+				return string(b)
+			}
+		}
+	}
+
+	return val.String()
+}
@@ -0,0 +1,356 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/volte6/gomud/internal/scripting"
+)
+
+const (
+	modulePath = `github.com/volte6/gomud`
+)
+
+var (
+	// The names the docs have always used for the objects scripts receive
+	typeRenames = map[string]string{
+		`ScriptActor`: `ActorObject`,
+		`ScriptRoom`:  `RoomObject`,
+		`ScriptItem`:  `ItemObject`,
+	}
+
+	// Always documented, even if nothing happens to return them
+	rootTypes = []reflect.Type{
+		reflect.TypeOf(scripting.ScriptActor{}),
+		reflect.TypeOf(scripting.ScriptRoom{}),
+		reflect.TypeOf(scripting.ScriptItem{}),
+	}
+
+	errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+	// Words a Go parameter can be named that TypeScript won't accept
+	reservedWords = map[string]bool{
+		`class`: true, `delete`: true, `enum`: true, `export`: true, `extends`: true, `function`: true,
+		`in`: true, `instanceof`: true, `let`: true, `new`: true, `this`: true, `typeof`: true,
+		`void`: true, `with`: true, `yield`: true, `null`: true, `true`: true, `false`: true,
+	}
+)
+
+type apiParam struct {
+	Name     string
+	Type     string
+	Variadic bool
+}
+
+type apiFunc struct {
+	Name    string
+	Params  []apiParam
+	Returns string
+	Doc     string
+	File    string
+}
+
+type apiField struct {
+	Name string
+	Type string
+	Doc  string
+}
+
+// A struct type that scripts receive, such as ActorObject
+type apiType struct {
+	Name    string
+	Doc     string
+	File    string
+	Fields  []apiField
+	Methods []apiFunc
+}
+
+// A global object holding functions, such as console
+type apiObject struct {
+	Name  string
+	Funcs []apiFunc
+}
+
+type API struct {
+	Funcs   []apiFunc
+	Objects []apiObject
+	Types   []apiType
+}
+
+type builder struct {
+	rootDir string
+	src     map[string]sourceDocs // package path => docs
+	names   map[reflect.Type]string
+	queue   []reflect.Type
+}
+
+// Reflects over the scripting globals and the types they expose
+func buildAPI(srcDir string) (API, error) {
+
+	api := API{}
+
+	// Other packages are found relative to the scripting package, and only read for their comments
+	scriptingPkg := reflect.TypeOf(scripting.ScriptRoom{}).PkgPath()
+	depth := strings.Count(strings.TrimPrefix(scriptingPkg, modulePath), `/`)
+
+	b := &builder{
+		rootDir: filepath.Join(srcDir, strings.Repeat(`../`, depth)),
+		src:     map[string]sourceDocs{},
+		names:   map[reflect.Type]string{},
+	}
+
+	var err error
+	if b.src[scriptingPkg], err = parseSource(srcDir, linkPrefix(scriptingPkg)); err != nil {
+		return api, err
+	}
+
+	for _, t := range rootTypes {
+		b.tsType(t)
+	}
+
+	globals := scripting.APIGlobals()
+
+	globalNames := make([]string, 0, len(globals))
+	for name := range globals {
+		globalNames = append(globalNames, name)
+	}
+	sort.Strings(globalNames)
+
+	for _, name := range globalNames {
+
+		switch val := globals[name].(type) {
+
+		case map[string]any:
+
+			obj := apiObject{Name: name}
+
+			funcNames := make([]string, 0, len(val))
+			for funcName := range val {
+				funcNames = append(funcNames, funcName)
+			}
+			sort.Strings(funcNames)
+
+			for _, funcName := range funcNames {
+				rv := reflect.ValueOf(val[funcName])
+				if rv.Kind() == reflect.Func {
+					pkgPath, key := goFuncName(rv)
+					obj.Funcs = append(obj.Funcs, b.function(funcName, pkgPath, key, rv.Type(), false))
+				}
+			}
+
+			api.Objects = append(api.Objects, obj)
+
+		default:
+
+			rv := reflect.ValueOf(val)
+			if rv.Kind() == reflect.Func {
+				pkgPath, key := goFuncName(rv)
+				api.Funcs = append(api.Funcs, b.function(name, pkgPath, key, rv.Type(), false))
+			}
+		}
+	}
+
+	// Documenting a type can turn up more types
+	for len(b.queue) > 0 {
+		t := b.queue[0]
+		b.queue = b.queue[1:]
+		api.Types = append(api.Types, b.structType(t))
+	}
+
+	sort.Slice(api.Types, func(i, j int) bool {
+		return api.Types[i].Name < api.Types[j].Name
+	})
+
+	return api, nil
+}
+
+// Reads the comments of a package in this module, the first time they are needed
+func (b *builder) docs(pkgPath string) sourceDocs {
+
+	if docs, ok := b.src[pkgPath]; ok {
+		return docs
+	}
+
+	dir := filepath.Join(b.rootDir, filepath.FromSlash(strings.TrimPrefix(pkgPath, modulePath)))
+	docs, err := parseSource(dir, linkPrefix(pkgPath))
+	if err != nil {
+		docs = sourceDocs{}
+	}
+	b.src[pkgPath] = docs
+
+	return docs
+}
+
+func linkPrefix(pkgPath string) string {
+	return strings.TrimPrefix(pkgPath, modulePath) + `/`
+}
+
+func (b *builder) structType(t reflect.Type) apiType {
+
+	src := b.docs(t.PkgPath())
+	typeDoc := src.types[t.Name()]
+
+	at := apiType{
+		Name: b.names[t],
+		Doc:  typeDoc.Doc,
+		File: typeDoc.File,
+	}
+
+	for _, f := range reflect.VisibleFields(t) {
+		if !f.IsExported() || f.Anonymous {
+			continue
+		}
+		at.Fields = append(at.Fields, apiField{
+			Name: f.Name,
+			Type: b.tsType(f.Type),
+			Doc:  src.fields[t.Name()+`.`+f.Name],
+		})
+	}
+
+	// Pointer receivers too, since most of these are handed to scripts as pointers
+	pt := reflect.PointerTo(t)
+	for i := 0; i < pt.NumMethod(); i++ {
+		m := pt.Method(i)
+		at.Methods = append(at.Methods, b.function(m.Name, t.PkgPath(), t.Name()+`.`+m.Name, m.Type, true))
+	}
+
+	return at
+}
+
+// Describes a function. Method types include the receiver as the first argument.
+func (b *builder) function(name string, pkgPath string, sourceKey string, ft reflect.Type, isMethod bool) apiFunc {
+
+	doc := b.docs(pkgPath).funcs[sourceKey]
+
+	fn := apiFunc{
+		Name: name,
+		Doc:  doc.Doc,
+		File: doc.File,
+	}
+
+	firstArg := 0
+	if isMethod {
+		firstArg = 1
+	}
+
+	argCt := ft.NumIn() - firstArg
+	for i := 0; i < argCt; i++ {
+
+		argType := ft.In(i + firstArg)
+
+		p := apiParam{Name: fmt.Sprintf(`arg%d`, i)}
+		if len(doc.Params) == argCt && doc.Params[i] != `` && doc.Params[i] != `_` {
+			p.Name = doc.Params[i]
+		}
+		if reservedWords[p.Name] {
+			p.Name += `_`
+		}
+
+		if ft.IsVariadic() && i == argCt-1 {
+			p.Variadic = true
+			argType = argType.Elem()
+		}
+		p.Type = b.tsType(argType)
+
+		fn.Params = append(fn.Params, p)
+	}
+
+	// A trailing error becomes a thrown exception in the script
+	outs := []reflect.Type{}
+	for i := 0; i < ft.NumOut(); i++ {
+		outs = append(outs, ft.Out(i))
+	}
+	if len(outs) > 0 && outs[len(outs)-1] == errorType {
+		outs = outs[:len(outs)-1]
+	}
+
+	switch len(outs) {
+	case 0:
+		fn.Returns = `void`
+	case 1:
+		fn.Returns = b.tsType(outs[0])
+		if outs[0].Kind() == reflect.Pointer {
+			fn.Returns += ` | null`
+		}
+	default:
+		fn.Returns = `any[]`
+	}
+
+	return fn
+}
+
+// Converts a Go type to the TypeScript type a script sees.
+// Struct types from this module are queued to be documented.
+func (b *builder) tsType(t reflect.Type) string {
+
+	switch t.Kind() {
+
+	case reflect.Pointer:
+		return b.tsType(t.Elem())
+
+	case reflect.Bool:
+		return `boolean`
+
+	case reflect.String:
+		return `string`
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return `number`
+
+	case reflect.Slice, reflect.Array:
+		elem := b.tsType(t.Elem())
+		return arrayOf(elem)
+
+	case reflect.Map:
+		key := `string`
+		if b.tsType(t.Key()) == `number` {
+			key = `number`
+		}
+		return `Record<` + key + `, ` + b.tsType(t.Elem()) + `>`
+
+	case reflect.Func:
+		return `Function`
+
+	case reflect.Struct:
+
+		if t.Name() == `` || !strings.HasPrefix(t.PkgPath(), modulePath) {
+			return `any`
+		}
+
+		if name, ok := b.names[t]; ok {
+			return name
+		}
+
+		name := t.Name()
+		if rename, ok := typeRenames[name]; ok {
+			name = rename
+		}
+		b.names[t] = name
+		b.queue = append(b.queue, t)
+
+		return name
+	}
+
+	return `any`
+}
+
+// Finds the package and name of the Go function behind a global, such as "GetRoom" or "console.log"
+func goFuncName(rv reflect.Value) (pkgPath string, name string) {
+
+	// github.com/volte6/gomud/internal/scripting.(*console).log-fm
+	fullName := runtime.FuncForPC(rv.Pointer()).Name()
+
+	pkgEnd := strings.LastIndex(fullName, `/`) + 1
+	pkgEnd += strings.Index(fullName[pkgEnd:], `.`)
+
+	name = strings.TrimSuffix(fullName[pkgEnd+1:], `-fm`)
+	name = strings.NewReplacer(`(*`, ``, `(`, ``, `)`, ``).Replace(name)
+
+	return fullName[:pkgEnd], name
+}
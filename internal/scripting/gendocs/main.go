@@ -0,0 +1,35 @@
+// Generates gomud.d.ts and the scripting API reference from the scripting package,
+// so that the docs always match the functions scripts can actually call.
+//
+// Run it with: go generate ./internal/scripting
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+)
+
+func main() {
+
+	srcDir := flag.String("src", ".", "Folder containing the scripting package source")
+	tsPath := flag.String("ts", "docs/gomud.d.ts", "TypeScript declaration file to write")
+	mdPath := flag.String("md", "docs/API_REFERENCE.md", "Markdown reference file to write")
+	flag.Parse()
+
+	api, err := buildAPI(*srcDir)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "gendocs:", err)
+		os.Exit(1)
+	}
+
+	if err := os.WriteFile(*tsPath, []byte(api.TypeScript()), 0644); err != nil {
+		fmt.Fprintln(os.Stderr, "gendocs:", err)
+		os.Exit(1)
+	}
+
+	if err := os.WriteFile(*mdPath, []byte(api.Markdown()), 0644); err != nil {
+		fmt.Fprintln(os.Stderr, "gendocs:", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"os"
+	"testing"
+)
+
+// Fails when the scripting API has changed without running go generate
+func TestGeneratedDocsUpToDate(t *testing.T) {
+
+	api, err := buildAPI(`..`)
+	if err != nil {
+		t.Fatalf("buildAPI() error = %v", err)
+	}
+
+	generated := map[string]string{
+		`../docs/gomud.d.ts`:       api.TypeScript(),
+		`../docs/API_REFERENCE.md`: api.Markdown(),
+	}
+
+	for filePath, want := range generated {
+		got, err := os.ReadFile(filePath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != want {
+			t.Errorf("%s is out of date, run: go generate ./internal/scripting", filePath)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"strings"
+)
+
+// Formats the parameters of a function for TypeScript, such as "roomId: number, ...excludeIds: number[]"
+func (f apiFunc) params() string {
+
+	params := make([]string, 0, len(f.Params))
+	for _, p := range f.Params {
+		if p.Variadic {
+			params = append(params, `...`+p.Name+`: `+arrayOf(p.Type))
+		} else {
+			params = append(params, p.Name+`: `+p.Type)
+		}
+	}
+
+	return strings.Join(params, `, `)
+}
+
+func (f apiFunc) signature() string {
+	return f.Name + `(` + f.params() + `): ` + f.Returns
+}
+
+func arrayOf(tsType string) string {
+	if strings.Contains(tsType, ` | `) {
+		return `(` + tsType + `)[]`
+	}
+	return tsType + `[]`
+}
+
+func writeJSDoc(sb *strings.Builder, indent string, doc string) {
+
+	if doc == `` {
+		return
+	}
+
+	lines := strings.Split(doc, "\n")
+	if len(lines) == 1 {
+		sb.WriteString(indent + `/** ` + doc + " */\n")
+		return
+	}
+
+	sb.WriteString(indent + "/**\n")
+	for _, line := range lines {
+		sb.WriteString(strings.TrimRight(indent+` * `+line, ` `) + "\n")
+	}
+	sb.WriteString(indent + " */\n")
+}
+
+// Renders the declaration file scripts can reference for autocompletion
+func (api API) TypeScript() string {
+
+	sb := strings.Builder{}
+
+	sb.WriteString("// Code generated by gendocs from internal/scripting. DO NOT EDIT.\n")
+	sb.WriteString("// Reference this file from a jsconfig.json to get autocompletion and type checking in scripts.\n")
+
+	for _, fn := range api.Funcs {
+		sb.WriteString("\n")
+		writeJSDoc(&sb, ``, fn.Doc)
+		sb.WriteString(`declare function ` + fn.signature() + ";\n")
+	}
+
+	for _, obj := range api.Objects {
+		sb.WriteString("\n")
+		sb.WriteString(`declare const ` + obj.Name + ": {\n")
+		for _, fn := range obj.Funcs {
+			writeJSDoc(&sb, `    `, fn.Doc)
+			sb.WriteString(`    ` + fn.signature() + ";\n")
+		}
+		sb.WriteString("};\n")
+	}
+
+	for _, t := range api.Types {
+		sb.WriteString("\n")
+		writeJSDoc(&sb, ``, t.Doc)
+		sb.WriteString(`interface ` + t.Name + " {\n")
+		for _, f := range t.Fields {
+			writeJSDoc(&sb, `    `, f.Doc)
+			sb.WriteString(`    ` + f.Name + `: ` + f.Type + ";\n")
+		}
+		for _, fn := range t.Methods {
+			writeJSDoc(&sb, `    `, fn.Doc)
+			sb.WriteString(`    ` + fn.signature() + ";\n")
+		}
+		sb.WriteString("}\n")
+	}
+
+	return sb.String()
+}
+
+func markdownFuncHeader(sb *strings.Builder, prefix string, fn apiFunc) {
+	if fn.File == `` {
+		sb.WriteString("## `" + prefix + fn.signature() + "`\n")
+	} else {
+		sb.WriteString("## [`" + prefix + fn.signature() + "`](" + fn.File + ")\n")
+	}
+	if fn.Doc != `` {
+		sb.WriteString(fn.Doc + "\n")
+	}
+	sb.WriteString("\n")
+}
+
+func anchor(title string) string {
+	return strings.ToLower(strings.ReplaceAll(title, ` `, `-`))
+}
+
+// Renders the API reference
+func (api API) Markdown() string {
+
+	sb := strings.Builder{}
+
+	sb.WriteString("# Scripting API Reference\n\n")
+	sb.WriteString("_This file is generated by `go generate ./internal/scripting`. To change it, edit the doc comments in the Go source and regenerate._\n\n")
+	sb.WriteString("The same API is available as TypeScript declarations in [gomud.d.ts](gomud.d.ts).\n\n")
+
+	sb.WriteString("- [Global Functions](#global-functions)\n")
+	for _, obj := range api.Objects {
+		sb.WriteString("- [" + obj.Name + "](#" + anchor(obj.Name) + ")\n")
+	}
+	for _, t := range api.Types {
+		sb.WriteString("- [" + t.Name + "](#" + anchor(t.Name) + ")\n")
+	}
+
+	sb.WriteString("\n# Global Functions\n\n")
+	for _, fn := range api.Funcs {
+		markdownFuncHeader(&sb, ``, fn)
+	}
+
+	for _, obj := range api.Objects {
+		sb.WriteString("# " + obj.Name + "\n\n")
+		for _, fn := range obj.Funcs {
+			markdownFuncHeader(&sb, obj.Name+`.`, fn)
+		}
+	}
+
+	for _, t := range api.Types {
+
+		sb.WriteString("# " + t.Name + "\n\n")
+		if t.Doc != `` {
+			sb.WriteString(t.Doc + "\n\n")
+		}
+
+		if len(t.Fields) > 0 {
+			sb.WriteString("| Field | Type | Explanation |\n")
+			sb.WriteString("| --- | --- | --- |\n")
+			for _, f := range t.Fields {
+				sb.WriteString("| " + f.Name + " | `" + strings.ReplaceAll(f.Type, `|`, `\|`) + "` | " + strings.ReplaceAll(f.Doc, "\n", ` `) + " |\n")
+			}
+			sb.WriteString("\n")
+		}
+
+		for _, fn := range t.Methods {
+			markdownFuncHeader(&sb, t.Name+`.`, fn)
+		}
+	}
+
+	return sb.String()
+}
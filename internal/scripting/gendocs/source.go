@@ -0,0 +1,133 @@
+package main
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"strings"
+)
+
+// What reflection can't tell us about a function or type: parameter names and doc comments
+type sourceDoc struct {
+	Params []string
+	Doc    string
+	File   string
+}
+
+type sourceDocs struct {
+	funcs  map[string]sourceDoc // "GetRoom" or "ScriptRoom.SendText"
+	types  map[string]sourceDoc // "ScriptRoom"
+	fields map[string]string    // "TextWrapperStyle.Fg" => comment
+}
+
+// Reads the doc comments and parameter names out of the non-test Go files in a folder.
+// linkPrefix is prepended to file names, to link to them from the docs.
+func parseSource(dir string, linkPrefix string) (sourceDocs, error) {
+
+	docs := sourceDocs{
+		funcs:  map[string]sourceDoc{},
+		types:  map[string]sourceDoc{},
+		fields: map[string]string{},
+	}
+
+	filePaths, err := filepath.Glob(filepath.Join(dir, `*.go`))
+	if err != nil {
+		return docs, err
+	}
+
+	fset := token.NewFileSet()
+
+	for _, filePath := range filePaths {
+
+		if strings.HasSuffix(filePath, `_test.go`) {
+			continue
+		}
+
+		f, err := parser.ParseFile(fset, filePath, nil, parser.ParseComments)
+		if err != nil {
+			return docs, err
+		}
+
+		fileName := linkPrefix + filepath.Base(filePath)
+
+		for _, decl := range f.Decls {
+
+			switch d := decl.(type) {
+
+			case *ast.FuncDecl:
+
+				key := d.Name.Name
+				if d.Recv != nil && len(d.Recv.List) > 0 {
+					key = receiverName(d.Recv.List[0].Type) + `.` + key
+				}
+
+				params := []string{}
+				for _, field := range d.Type.Params.List {
+					if len(field.Names) == 0 {
+						params = append(params, ``)
+					}
+					for _, name := range field.Names {
+						params = append(params, name.Name)
+					}
+				}
+
+				docs.funcs[key] = sourceDoc{
+					Params: params,
+					Doc:    strings.TrimSpace(d.Doc.Text()),
+					File:   fileName,
+				}
+
+			case *ast.GenDecl:
+
+				for _, spec := range d.Specs {
+
+					tSpec, ok := spec.(*ast.TypeSpec)
+					if !ok {
+						continue
+					}
+
+					// A lone type declaration keeps its comment on the GenDecl
+					doc := tSpec.Doc.Text()
+					if doc == `` && len(d.Specs) == 1 {
+						doc = d.Doc.Text()
+					}
+
+					docs.types[tSpec.Name.Name] = sourceDoc{
+						Doc:  strings.TrimSpace(doc),
+						File: fileName,
+					}
+
+					sType, ok := tSpec.Type.(*ast.StructType)
+					if !ok {
+						continue
+					}
+
+					for _, field := range sType.Fields.List {
+
+						comment := field.Doc.Text()
+						if comment == `` {
+							comment = field.Comment.Text()
+						}
+
+						for _, name := range field.Names {
+							docs.fields[tSpec.Name.Name+`.`+name.Name] = strings.TrimSpace(comment)
+						}
+					}
+				}
+			}
+		}
+	}
+
+	return docs, nil
+}
+
+func receiverName(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.StarExpr:
+		return receiverName(t.X)
+	case *ast.Ident:
+		return t.Name
+	}
+	return ``
+}
@@ -0,0 +1,68 @@
+package scripting
+
+import (
+	"errors"
+	"log/slog"
+)
+
+// Details passed to a player hook.
+// Scripts can change some of the values to alter what happens next.
+type HookDetails map[string]any
+
+func (d HookDetails) Bool(key string) bool {
+	b, _ := d[key].(bool)
+	return b
+}
+
+// Numbers changed by a script come back as int64 or float64
+func (d HookDetails) Int(key string) int {
+	switch v := d[key].(type) {
+	case int:
+		return v
+	case int64:
+		return int(v)
+	case float64:
+		return int(v)
+	}
+	return 0
+}
+
+func (d HookDetails) Float(key string) float64 {
+	switch v := d[key].(type) {
+	case float64:
+		return v
+	case int:
+		return float64(v)
+	case int64:
+		return float64(v)
+	}
+	return 0
+}
+
+func (d HookDetails) String(key string) string {
+	s, _ := d[key].(string)
+	return s
+}
+
+// Calls a player hook such as onPlayerLogin or onPlayerDeath in the world script.
+// The hook receives (user, room, details).
+// Returns true if the hook returned true, which cancels whatever the hook allows to be cancelled.
+func TryPlayerHook(hookName string, userId int, details HookDetails) bool {
+
+	sUser := GetActor(userId, 0)
+	if sUser == nil {
+		return false
+	}
+
+	if details == nil {
+		details = HookDetails{}
+	}
+
+	cancel, err := TryWorldScriptEvent(hookName, sUser, GetRoom(sUser.GetRoomId()), map[string]any(details))
+	if err != nil && !errors.Is(err, errNoScript) {
+		slog.Error("TryPlayerHook()", "hook", hookName, "userId", userId, "error", err)
+		return false
+	}
+
+	return cancel
+}
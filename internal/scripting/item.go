@@ -24,7 +24,12 @@ func PruneItemVMs(instanceIds ...int) {
 
 }
 
-func TryItemScriptEvent(eventName string, item items.Item, userId int) (bool, error) {
+func TryItemScriptEvent(eventName string, item items.Item, userId int) (handled bool, err error) {
+
+	defer recoverScriptPanic(&err, eventName, userId, 0, 0, func() { delete(itemVMCache, strconv.Itoa(item.ItemId)) })
+
+	pushScriptOwner(scriptOwner{kind: ownerItem, userId: userId, item: item})
+	defer popScriptOwner()
 
 	sItem := GetItem(item)
 
@@ -85,7 +90,12 @@ func TryItemScriptEvent(eventName string, item items.Item, userId int) (bool, er
 	return false, nil
 }
 
-func TryItemCommand(cmd string, item items.Item, userId int) (bool, error) {
+func TryItemCommand(cmd string, item items.Item, userId int) (handled bool, err error) {
+
+	defer recoverScriptPanic(&err, `onCommand_`+cmd, userId, 0, 0, func() { delete(itemVMCache, strconv.Itoa(item.ItemId)) })
+
+	pushScriptOwner(scriptOwner{kind: ownerItem, userId: userId, item: item})
+	defer popScriptOwner()
 
 	sItem := GetItem(item)
 
@@ -202,10 +212,14 @@ func getItemVM(sItem *ScriptItem) (*VMWrapper, error) {
 		return nil, errNoScript
 	}
 
+	namespace := fmt.Sprintf(`item-%s`, scriptId)
+	pushScriptNamespace(namespace)
+	defer popScriptNamespace()
+
 	vm := goja.New()
 	setAllScriptingFunctions(vm)
 
-	prg, err := goja.Compile(fmt.Sprintf(`item-%s`, scriptId), script, false)
+	prg, err := goja.Compile(namespace, script, false)
 	if err != nil {
 		finalErr := fmt.Errorf("Compile: %w", err)
 		return nil, finalErr
@@ -237,6 +251,10 @@ func getItemVM(sItem *ScriptItem) (*VMWrapper, error) {
 	tmr.Stop()
 
 	vmw := newVMWrapper(vm, 0)
+	vmw.namespace = namespace
+
+	itemSpec := sItem.itemRecord.GetSpec()
+	setScriptFile(namespace, itemSpec.GetScriptPath())
 
 	itemVMCache[scriptId] = vmw
 
@@ -10,13 +10,23 @@ func setItemFunctions(vm *goja.Runtime) {
 	vm.Set(`CreateItem`, CreateItem)
 }
 
-func newScriptItem(i items.Item) ScriptItem {
-	return ScriptItem{i, &i}
+// Anything that holds items, so changes a script makes can be saved back to it
+type itemOwner interface {
+	UpdateItem(originalItm items.Item, replacement items.Item) bool
+}
+
+func newScriptItem(i items.Item, owner ...itemOwner) ScriptItem {
+	sItm := ScriptItem{originalItem: i, itemRecord: &i}
+	if len(owner) > 0 {
+		sItm.owner = owner[0]
+	}
+	return sItm
 }
 
 type ScriptItem struct {
 	originalItem items.Item
 	itemRecord   *items.Item
+	owner        itemOwner // nil if the item isn't held by anything
 }
 
 func (i ScriptItem) ItemId() int {
@@ -91,6 +101,21 @@ func (i ScriptItem) GetTempData(key string) any {
 	return i.itemRecord.GetTempData(key)
 }
 
+func (i ScriptItem) SetPermData(key string, value any) bool {
+	if !setPermData(i.itemRecord, key, value) {
+		return false
+	}
+	// The item is a copy, and may not have had any data to share with the original
+	if i.owner != nil {
+		i.owner.UpdateItem(i.originalItem, *i.itemRecord)
+	}
+	return true
+}
+
+func (i ScriptItem) GetPermData(key string) any {
+	return getPermData(i.itemRecord, key)
+}
+
 func (i ScriptItem) ShorthandId() string {
 	return i.itemRecord.ShorthandId()
 }
@@ -22,13 +22,18 @@ func PruneMobVMs(instanceIds ...int) {
 
 }
 
-func TryMobConverse(rest string, mobInstanceId int, sourceMobInstanceId int) (bool, error) {
+func TryMobConverse(rest string, mobInstanceId int, sourceMobInstanceId int) (handled bool, err error) {
 
 	sMob := GetActor(0, mobInstanceId)
 	if sMob == nil {
 		return false, errors.New("mob not found")
 	}
 
+	defer recoverScriptPanic(&err, `onConverse`, 0, mobInstanceId, sMob.GetRoomId(), func() { delete(mobVMCache, mobScriptId(sMob)) })
+
+	pushScriptOwner(scriptOwner{kind: ownerMob, mobInstanceId: mobInstanceId, roomId: sMob.GetRoomId()})
+	defer popScriptOwner()
+
 	vmw, err := getMobVM(sMob)
 	if err != nil {
 		return false, err
@@ -85,13 +90,18 @@ func TryMobConverse(rest string, mobInstanceId int, sourceMobInstanceId int) (bo
 	return false, nil
 }
 
-func TryMobScriptEvent(eventName string, mobInstanceId int, sourceId int, sourceType string, details map[string]any) (bool, error) {
+func TryMobScriptEvent(eventName string, mobInstanceId int, sourceId int, sourceType string, details map[string]any) (handled bool, err error) {
 
 	sMob := GetActor(0, mobInstanceId)
 	if sMob == nil {
 		return false, errors.New("mob not found")
 	}
 
+	defer recoverScriptPanic(&err, eventName, 0, mobInstanceId, sMob.GetRoomId(), func() { delete(mobVMCache, mobScriptId(sMob)) })
+
+	pushScriptOwner(scriptOwner{kind: ownerMob, mobInstanceId: mobInstanceId, roomId: sMob.GetRoomId()})
+	defer popScriptOwner()
+
 	vmw, err := getMobVM(sMob)
 	if err != nil {
 		return false, err
@@ -149,7 +159,7 @@ func TryMobScriptEvent(eventName string, mobInstanceId int, sourceId int, source
 	return false, nil
 }
 
-func TryMobCommand(cmd string, rest string, mobInstanceId int, sourceId int, sourceType string) (bool, error) {
+func TryMobCommand(cmd string, rest string, mobInstanceId int, sourceId int, sourceType string) (handled bool, err error) {
 
 	sMob := GetActor(0, mobInstanceId)
 	if sMob == nil {
@@ -157,6 +167,11 @@ func TryMobCommand(cmd string, rest string, mobInstanceId int, sourceId int, sou
 		return false, errors.New("mob not found")
 	}
 
+	defer recoverScriptPanic(&err, `onCommand_`+cmd, 0, mobInstanceId, sMob.GetRoomId(), func() { delete(mobVMCache, mobScriptId(sMob)) })
+
+	pushScriptOwner(scriptOwner{kind: ownerMob, mobInstanceId: mobInstanceId, roomId: sMob.GetRoomId()})
+	defer popScriptOwner()
+
 	vmw, err := getMobVM(sMob)
 	if err != nil {
 		return false, err
@@ -256,9 +271,14 @@ func TryMobCommand(cmd string, rest string, mobInstanceId int, sourceId int, sou
 	return false, nil
 }
 
+// Mobs of the same type and script tag share a VM
+func mobScriptId(mobActor *ScriptActor) string {
+	return fmt.Sprintf(`%d-%s`, mobActor.MobTypeId(), mobActor.getScriptTag())
+}
+
 func getMobVM(mobActor *ScriptActor) (*VMWrapper, error) {
 
-	scriptId := fmt.Sprintf(`%d-%s`, mobActor.MobTypeId(), mobActor.getScriptTag())
+	scriptId := mobScriptId(mobActor)
 
 	if vm, ok := mobVMCache[scriptId]; ok {
 		if vm == nil {
@@ -273,10 +293,16 @@ func getMobVM(mobActor *ScriptActor) (*VMWrapper, error) {
 		return nil, errNoScript
 	}
 
+	namespace := fmt.Sprintf(`mob-%s`, scriptId)
+	pushScriptNamespace(namespace)
+	defer popScriptNamespace()
+	pushScriptOwner(scriptOwner{kind: ownerMob, mobInstanceId: mobActor.InstanceId(), roomId: mobActor.GetRoomId()})
+	defer popScriptOwner()
+
 	vm := goja.New()
 	setAllScriptingFunctions(vm)
 
-	prg, err := goja.Compile(fmt.Sprintf(`mob-%s`, scriptId), script, false)
+	prg, err := goja.Compile(namespace, script, false)
 	if err != nil {
 		finalErr := fmt.Errorf("Compile: %w", err)
 		return nil, finalErr
@@ -335,6 +361,8 @@ func getMobVM(mobActor *ScriptActor) (*VMWrapper, error) {
 	tmr.Stop()
 
 	vmw := newVMWrapper(vm, 0)
+	vmw.namespace = namespace
+	setScriptFile(namespace, mobActor.mobRecord.GetScriptPath())
 
 	mobVMCache[scriptId] = vmw
 
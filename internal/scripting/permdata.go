@@ -0,0 +1,212 @@
+package scripting
+
+import (
+	"log/slog"
+	"sort"
+	"strings"
+
+	"github.com/volte6/gomud/internal/characters"
+	"github.com/volte6/gomud/internal/configs"
+	"github.com/volte6/gomud/internal/mobs"
+	"github.com/volte6/gomud/internal/rooms"
+	"github.com/volte6/gomud/internal/users"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// Keys are stored as "{namespace}:{key}"
+	permDataSeparator = `:`
+	// Used when no script is running, such as from the admin command
+	permDataGlobalNamespace = `global`
+	// Character MiscData is shared with the rest of the game, so script keys get an extra prefix
+	characterPermDataPrefix = `script` + permDataSeparator
+)
+
+var (
+	// The namespace of whichever script is currently running.
+	// Scripts can trigger other scripts (e.g. giving an item runs its onGive), so this is a stack.
+	namespaceStack = []string{}
+)
+
+// Anything scripts can permanently save data on
+type PermDataStore interface {
+	SetPermData(key string, value any)
+	GetPermData(key string) any
+	GetPermDataKeys() []string
+}
+
+func pushScriptNamespace(namespace string) {
+	namespaceStack = append(namespaceStack, namespace)
+}
+
+func popScriptNamespace() {
+	if len(namespaceStack) > 0 {
+		namespaceStack = namespaceStack[:len(namespaceStack)-1]
+	}
+}
+
+func currentScriptNamespace() string {
+	if len(namespaceStack) == 0 {
+		return permDataGlobalNamespace
+	}
+	return namespaceStack[len(namespaceStack)-1]
+}
+
+// Saves a value under the running script's namespace.
+// Returns false if the value can't be stored or the namespace would grow past the size limit.
+func setPermData(store PermDataStore, key string, value any) bool {
+
+	if store == nil {
+		return false
+	}
+
+	namespace := currentScriptNamespace()
+	fullKey := namespace + permDataSeparator + key
+
+	if value == nil {
+		store.SetPermData(fullKey, nil)
+		return true
+	}
+
+	switch value.(type) {
+	case ScriptActor, ScriptItem, *ScriptItem, ScriptRoom, *ScriptRoom: // Only plain data can be saved
+		return false
+	}
+
+	nsData := GetPermDataNamespace(store, namespace)
+	nsData[key] = value
+
+	maxBytes := int(configs.GetConfig().ScriptPermDataMaxBytes)
+	if size := PermDataSize(nsData); size > maxBytes {
+		slog.Warn("SetPermData()", "error", "size limit reached", "namespace", namespace, "key", key, "size", size, "max", maxBytes)
+		return false
+	}
+
+	store.SetPermData(fullKey, value)
+	return true
+}
+
+func getPermData(store PermDataStore, key string) any {
+
+	if store == nil {
+		return nil
+	}
+
+	return store.GetPermData(currentScriptNamespace() + permDataSeparator + key)
+}
+
+// Returns all data saved on a store, grouped by namespace
+func ListPermData(store PermDataStore) map[string]map[string]any {
+
+	ret := map[string]map[string]any{}
+
+	if store == nil {
+		return ret
+	}
+
+	for _, fullKey := range store.GetPermDataKeys() {
+		namespace, key, _ := strings.Cut(fullKey, permDataSeparator)
+		if _, ok := ret[namespace]; !ok {
+			ret[namespace] = map[string]any{}
+		}
+		ret[namespace][key] = store.GetPermData(fullKey)
+	}
+
+	return ret
+}
+
+// Returns a copy of the data saved on a store by one namespace
+func GetPermDataNamespace(store PermDataStore, namespace string) map[string]any {
+
+	if nsData, ok := ListPermData(store)[namespace]; ok {
+		return nsData
+	}
+	return map[string]any{}
+}
+
+// Returns a sorted list of namespaces that have data saved on a store
+func GetPermDataNamespaces(store PermDataStore) []string {
+
+	namespaces := []string{}
+	for namespace := range ListPermData(store) {
+		namespaces = append(namespaces, namespace)
+	}
+	sort.Strings(namespaces)
+
+	return namespaces
+}
+
+// Deletes data saved on a store.
+// If namespace is empty, all namespaces are cleared.
+// Returns how many keys were deleted.
+func ClearPermData(store PermDataStore, namespace string) int {
+
+	if store == nil {
+		return 0
+	}
+
+	deleted := 0
+	for _, fullKey := range store.GetPermDataKeys() {
+		if namespace != `` && !strings.HasPrefix(fullKey, namespace+permDataSeparator) {
+			continue
+		}
+		store.SetPermData(fullKey, nil)
+		deleted++
+	}
+
+	return deleted
+}
+
+// How many bytes the data takes up when saved
+func PermDataSize(data map[string]any) int {
+	b, err := yaml.Marshal(data)
+	if err != nil {
+		return 0
+	}
+	return len(b)
+}
+
+// Returns the store for a users character
+func UserPermData(user *users.UserRecord) PermDataStore {
+	if user == nil || user.Character == nil {
+		return nil
+	}
+	return characterPermData{user.Character}
+}
+
+// Returns the store for the spawn definition a mob came from.
+// Returns nil if the mob wasn't spawned by a room (e.g. summoned or created by an admin).
+func MobPermData(mob *mobs.Mob) PermDataStore {
+
+	if mob == nil {
+		return nil
+	}
+
+	room := rooms.LoadRoom(mob.HomeRoomId)
+	if room == nil {
+		return nil
+	}
+
+	if spawnInfo := room.GetSpawnInfo(mob.InstanceId); spawnInfo != nil {
+		return spawnInfo
+	}
+
+	return nil
+}
+
+// Stores script data in a characters MiscData
+type characterPermData struct {
+	characterRecord *characters.Character
+}
+
+func (c characterPermData) SetPermData(key string, value any) {
+	c.characterRecord.SetMiscData(characterPermDataPrefix+key, value)
+}
+
+func (c characterPermData) GetPermData(key string) any {
+	return c.characterRecord.GetMiscData(characterPermDataPrefix + key)
+}
+
+func (c characterPermData) GetPermDataKeys() []string {
+	return c.characterRecord.GetMiscDataKeys(characterPermDataPrefix)
+}
@@ -0,0 +1,243 @@
+package scripting
+
+import (
+	"errors"
+	"runtime/metrics"
+	"strings"
+	"time"
+
+	"github.com/dop251/goja"
+)
+
+const (
+	// How many recent script errors to remember
+	maxRecentScriptErrors = 25
+	// How often the heap guard checks memory while a script is running
+	heapGuardInterval = time.Millisecond
+	heapMetricName    = `/memory/classes/heap/objects:bytes`
+)
+
+var (
+	errHeapLimit = errors.New("script heap limit exceeded")
+
+	scriptStats        = map[string]*ScriptStats{} // namespace => stats
+	recentScriptErrors = []ScriptError{}
+	disabledScripts    = map[string]struct{}{} // lowercase namespaces disabled by an admin
+
+	// If above zero, scripts are interrupted when the heap grows by more than this while they run
+	scriptMaxHeapGrowth uint64 = 0
+)
+
+// Running totals for a single script
+type ScriptStats struct {
+	Namespace  string // e.g. room-1, mob-12-guard, item-10001, zone-frostfang
+	File       string
+	Calls      uint64
+	TotalTime  time.Duration
+	MaxTime    time.Duration
+	MaxFunc    string // Function that took MaxTime
+	Timeouts   uint64
+	Exceptions uint64
+	HeapLimits uint64
+	Panics     uint64
+}
+
+func (s ScriptStats) AvgTime() time.Duration {
+	if s.Calls == 0 {
+		return 0
+	}
+	return s.TotalTime / time.Duration(s.Calls)
+}
+
+func (s ScriptStats) Errors() uint64 {
+	return s.Timeouts + s.Exceptions + s.HeapLimits + s.Panics
+}
+
+type ScriptError struct {
+	Time      time.Time
+	Namespace string
+	Function  string
+	Error     string // goja errors include the script name, line and column
+}
+
+func getScriptStats(namespace string) *ScriptStats {
+	stats, ok := scriptStats[namespace]
+	if !ok {
+		stats = &ScriptStats{Namespace: namespace}
+		scriptStats[namespace] = stats
+	}
+	return stats
+}
+
+// Remembers which file a script was loaded from
+func setScriptFile(namespace string, file string) {
+	getScriptStats(namespace).File = file
+}
+
+func recordScriptCall(namespace string, fnName string, duration time.Duration, err error) {
+
+	stats := getScriptStats(namespace)
+	stats.Calls++
+	stats.TotalTime += duration
+	if duration > stats.MaxTime {
+		stats.MaxTime = duration
+		stats.MaxFunc = fnName
+	}
+
+	if err == nil {
+		return
+	}
+
+	var exception *goja.Exception
+	if errors.Is(err, errTimeout) {
+		stats.Timeouts++
+	} else if errors.Is(err, errHeapLimit) {
+		stats.HeapLimits++
+	} else if errors.As(err, &exception) {
+		stats.Exceptions++
+	}
+
+	recordScriptError(namespace, fnName, err.Error())
+
+	roomIds := []int{}
+	if roomId := currentScriptRoomId(); roomId > 0 {
+		roomIds = append(roomIds, roomId)
+	}
+	reportScriptError(scriptSource(namespace), roomIds, fnName+`(): `+err.Error())
+}
+
+func recordScriptPanic(namespace string, fnName string) {
+	getScriptStats(namespace).Panics++
+	recordScriptError(namespace, fnName, errPanic.Error())
+}
+
+func recordScriptError(namespace string, fnName string, errMsg string) {
+	recentScriptErrors = append(recentScriptErrors, ScriptError{
+		Time:      time.Now(),
+		Namespace: namespace,
+		Function:  fnName,
+		Error:     errMsg,
+	})
+	if len(recentScriptErrors) > maxRecentScriptErrors {
+		recentScriptErrors = recentScriptErrors[len(recentScriptErrors)-maxRecentScriptErrors:]
+	}
+}
+
+// Returns a copy of the stats for every script that has been called
+func GetScriptStats() []ScriptStats {
+	ret := make([]ScriptStats, 0, len(scriptStats))
+	for _, stats := range scriptStats {
+		ret = append(ret, *stats)
+	}
+	return ret
+}
+
+// Returns recent script errors, oldest first
+func GetRecentScriptErrors() []ScriptError {
+	return append([]ScriptError{}, recentScriptErrors...)
+}
+
+// Clears all script stats and recent errors
+func ResetScriptStats() {
+	for namespace, stats := range scriptStats {
+		scriptStats[namespace] = &ScriptStats{Namespace: namespace, File: stats.File}
+	}
+	recentScriptErrors = recentScriptErrors[:0]
+}
+
+// Stops a script from being run until it is enabled again. Namespaces are not case sensitive.
+// Returns false if it was already disabled.
+func DisableScript(namespace string) bool {
+	namespace = strings.ToLower(namespace)
+	if _, ok := disabledScripts[namespace]; ok {
+		return false
+	}
+	disabledScripts[namespace] = struct{}{}
+	return true
+}
+
+// Allows a disabled script to run again.
+// Room scripts disabled for panicking too often are also re-enabled.
+// Returns false if the script was not disabled.
+func EnableScript(namespace string) bool {
+
+	namespace = strings.ToLower(namespace)
+
+	_, ok := disabledScripts[namespace]
+	delete(disabledScripts, namespace)
+
+	for roomId := range disabledRoomScripts {
+		if strings.ToLower(roomScriptNamespace(roomId)) == namespace {
+			delete(disabledRoomScripts, roomId)
+			ok = true
+		}
+	}
+
+	return ok
+}
+
+func IsScriptDisabled(namespace string) bool {
+	_, ok := disabledScripts[strings.ToLower(namespace)]
+	return ok
+}
+
+// Returns every disabled script, including room scripts disabled for panicking
+func GetDisabledScripts() []string {
+	ret := []string{}
+	for namespace := range disabledScripts {
+		ret = append(ret, namespace)
+	}
+	for roomId := range disabledRoomScripts {
+		ret = append(ret, roomScriptNamespace(roomId))
+	}
+	return ret
+}
+
+// Watches the heap while a script runs, and interrupts the script if it grows too much.
+// Returns a function that stops watching.
+// The heap is measured for the whole process, not the script's VM, so anything else allocating
+// at the same time (connections, the web server) counts against the script and can interrupt it.
+// Set ScriptMaxHeapGrowthMB well above what the server normally allocates in a script's timeout.
+func startHeapGuard(vm *goja.Runtime) func() {
+
+	if scriptMaxHeapGrowth == 0 {
+		return func() {}
+	}
+
+	sample := []metrics.Sample{{Name: heapMetricName}}
+	metrics.Read(sample)
+	if sample[0].Value.Kind() != metrics.KindUint64 {
+		return func() {}
+	}
+	startBytes := sample[0].Value.Uint64()
+
+	done := make(chan struct{})
+	exited := make(chan struct{})
+
+	go func() {
+		defer close(exited)
+
+		ticker := time.NewTicker(heapGuardInterval)
+		defer ticker.Stop()
+
+		s := []metrics.Sample{{Name: heapMetricName}}
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				metrics.Read(s)
+				if nowBytes := s[0].Value.Uint64(); nowBytes > startBytes && nowBytes-startBytes > scriptMaxHeapGrowth {
+					vm.Interrupt(errHeapLimit)
+					return
+				}
+			}
+		}
+	}()
+
+	// Wait for the check to exit, so it can't interrupt whatever runs next
+	return func() {
+		close(done)
+		<-exited
+	}
+}
@@ -7,18 +7,65 @@ import (
 	"time"
 
 	"github.com/dop251/goja"
+	"github.com/volte6/gomud/internal/crashreports"
 	"github.com/volte6/gomud/internal/rooms"
 	"github.com/volte6/gomud/internal/users"
 )
 
+const (
+	// How many panics within the window before a room script is disabled
+	roomScriptMaxPanics   = 3
+	roomScriptPanicWindow = 10 * time.Minute
+)
+
 var (
 	roomVMCache       = make(map[int]*VMWrapper)
 	scriptLoadTimeout = 1000 * time.Millisecond
 	scriptRoomTimeout = 10 * time.Millisecond
+
+	roomScriptPanics    = map[int][]time.Time{} // roomId => when recent panics happened
+	disabledRoomScripts = map[int]struct{}{}
 )
 
 func ClearRoomVMs() {
 	clear(roomVMCache)
+	clear(roomScriptPanics)
+	clear(disabledRoomScripts)
+}
+
+// Discards a room VM after a panic, and disables the script entirely if it keeps panicking.
+// Disabled scripts stay disabled until the room VM's are cleared (such as with a reload).
+func roomScriptPanicked(roomId int) {
+
+	delete(roomVMCache, roomId)
+
+	now := time.Now()
+
+	recentPanics := []time.Time{}
+	for _, t := range roomScriptPanics[roomId] {
+		if now.Sub(t) < roomScriptPanicWindow {
+			recentPanics = append(recentPanics, t)
+		}
+	}
+	recentPanics = append(recentPanics, now)
+
+	if len(recentPanics) < roomScriptMaxPanics {
+		roomScriptPanics[roomId] = recentPanics
+		return
+	}
+
+	delete(roomScriptPanics, roomId)
+	disabledRoomScripts[roomId] = struct{}{}
+
+	slog.Error("Room script disabled", "roomId", roomId, "panics", len(recentPanics), "window", roomScriptPanicWindow)
+
+	crashreports.NotifyAdmins(fmt.Sprintf(`The script for room #<ansi fg="yellow-bold">%d</ansi> panicked %d times and has been <ansi fg="red">disabled</ansi>. Reload to re-enable it.`, roomId, len(recentPanics)))
+}
+
+// Returns true if a room script was disabled for panicking too often
+func IsRoomScriptDisabled(roomId int) bool {
+	_, ok := disabledRoomScripts[roomId]
+	return ok
 }
 
 func PruneRoomVMs(roomIds ...int) {
@@ -35,7 +82,12 @@ func PruneRoomVMs(roomIds ...int) {
 	}
 }
 
-func TryRoomScriptEvent(eventName string, userId int, roomId int) (bool, error) {
+func TryRoomScriptEvent(eventName string, userId int, roomId int) (handled bool, err error) {
+
+	defer recoverScriptPanic(&err, eventName, userId, 0, roomId, func() { roomScriptPanicked(roomId) })
+
+	pushScriptOwner(scriptOwner{kind: ownerRoom, roomId: roomId})
+	defer popScriptOwner()
 
 	vmw, err := getRoomVM(roomId)
 	if err != nil {
@@ -96,7 +148,12 @@ func TryRoomScriptEvent(eventName string, userId int, roomId int) (bool, error)
 	return false, nil
 }
 
-func TryRoomIdleEvent(roomId int) (bool, error) {
+func TryRoomIdleEvent(roomId int) (handled bool, err error) {
+
+	defer recoverScriptPanic(&err, `onIdle`, 0, 0, roomId, func() { roomScriptPanicked(roomId) })
+
+	pushScriptOwner(scriptOwner{kind: ownerRoom, roomId: roomId})
+	defer popScriptOwner()
 
 	vmw, err := getRoomVM(roomId)
 	if err != nil {
@@ -155,13 +212,85 @@ func TryRoomIdleEvent(roomId int) (bool, error) {
 	return false, nil
 }
 
-func TryRoomCommand(cmd string, rest string, userId int) (bool, error) {
+func TryRoomWeatherEvent(roomId int, fromWeather string, toWeather string) (handled bool, err error) {
+
+	defer recoverScriptPanic(&err, `onWeatherChange`, 0, 0, roomId, func() { roomScriptPanicked(roomId) })
+
+	pushScriptOwner(scriptOwner{kind: ownerRoom, roomId: roomId})
+	defer popScriptOwner()
+
+	vmw, err := getRoomVM(roomId)
+	if err != nil {
+		return false, err
+	}
+
+	timestart := time.Now()
+	defer func() {
+		slog.Debug("TryRoomWeatherEvent()", "roomId", roomId, "time", time.Since(timestart))
+	}()
+
+	if onCommandFunc, ok := vmw.GetFunction(`onWeatherChange`); ok {
+
+		// Set forced ansi tag wrappers
+		userTextWrap.Set(`script-text`, ``, ``)
+		roomTextWrap.Set(`script-text`, ``, ``)
+
+		sRoom := GetRoom(roomId)
+
+		tmr := time.AfterFunc(scriptRoomTimeout, func() {
+			vmw.VM.Interrupt(errTimeout)
+		})
+
+		res, err := onCommandFunc(goja.Undefined(),
+			vmw.VM.ToValue(sRoom),
+			vmw.VM.ToValue(fromWeather),
+			vmw.VM.ToValue(toWeather),
+		)
+
+		vmw.VM.ClearInterrupt()
+		tmr.Stop()
+
+		userTextWrap.Reset()
+		roomTextWrap.Reset()
+
+		if err != nil {
+
+			// Wrap the error
+			finalErr := fmt.Errorf("TryRoomWeatherEvent(): %w", err)
+
+			if _, ok := finalErr.(*goja.Exception); ok {
+				slog.Error("JSVM", "exception", finalErr)
+				return false, finalErr
+			} else if errors.Is(finalErr, errTimeout) {
+				slog.Error("JSVM", "interrupted", finalErr)
+				return false, finalErr
+			}
+
+			slog.Error("JSVM", "error", finalErr)
+			return false, finalErr
+		}
+
+		if boolVal, ok := res.Export().(bool); ok {
+			return boolVal, nil
+		}
+	}
+
+	return false, nil
+}
+
+func TryRoomCommand(cmd string, rest string, userId int) (handled bool, err error) {
 
 	user := users.GetByUserId(userId)
 	if user == nil {
 		return false, errors.New("user not found")
 	}
 
+	roomId := user.Character.RoomId
+	defer recoverScriptPanic(&err, `onCommand_`+cmd, userId, 0, roomId, func() { roomScriptPanicked(roomId) })
+
+	pushScriptOwner(scriptOwner{kind: ownerRoom, roomId: roomId})
+	defer popScriptOwner()
+
 	room := rooms.LoadRoom(user.Character.RoomId)
 
 	altCmd, _ := room.FindExitByName(cmd)
@@ -286,8 +415,16 @@ func TryRoomCommand(cmd string, rest string, userId int) (bool, error) {
 	return false, nil
 }
 
+func roomScriptNamespace(roomId int) string {
+	return fmt.Sprintf(`room-%d`, roomId)
+}
+
 func getRoomVM(roomId int) (*VMWrapper, error) {
 
+	if IsRoomScriptDisabled(roomId) {
+		return nil, errNoScript
+	}
+
 	if vm, ok := roomVMCache[roomId]; ok {
 		if vm == nil {
 			return nil, errNoScript
@@ -306,10 +443,16 @@ func getRoomVM(roomId int) (*VMWrapper, error) {
 		return nil, errNoScript
 	}
 
+	namespace := roomScriptNamespace(roomId)
+	pushScriptNamespace(namespace)
+	defer popScriptNamespace()
+	pushScriptOwner(scriptOwner{kind: ownerRoom, roomId: roomId})
+	defer popScriptOwner()
+
 	vm := goja.New()
 	setAllScriptingFunctions(vm)
 
-	prg, err := goja.Compile(fmt.Sprintf(`room-%d`, roomId), script, false)
+	prg, err := goja.Compile(namespace, script, false)
 	if err != nil {
 		finalErr := fmt.Errorf("Compile: %w", err)
 		return nil, finalErr
@@ -370,8 +513,12 @@ func getRoomVM(roomId int) (*VMWrapper, error) {
 	tmr.Stop()
 
 	vmw := newVMWrapper(vm, 0)
+	vmw.namespace = namespace
+	setScriptFile(namespace, room.GetScriptPath())
 
 	roomVMCache[roomId] = vmw
 
+	restoreRoomTimers(room)
+
 	return vmw, nil
 }
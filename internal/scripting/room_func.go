@@ -1,8 +1,6 @@
 package scripting
 
 import (
-	"fmt"
-
 	"github.com/dop251/goja"
 	"github.com/volte6/gomud/internal/colorpatterns"
 	"github.com/volte6/gomud/internal/configs"
@@ -18,6 +16,7 @@ import (
 func setRoomFunctions(vm *goja.Runtime) {
 	vm.Set(`GetRoom`, GetRoom)
 	vm.Set(`GetMap`, GetMap)
+	vm.Set(`GetMapLevel`, GetMapLevel)
 }
 
 type ScriptRoom struct {
@@ -48,7 +47,7 @@ func (r ScriptRoom) GetPermData(key string) any {
 func (r ScriptRoom) GetItems() []ScriptItem {
 	itms := make([]ScriptItem, 0, 5)
 	for _, item := range r.roomRecord.GetAllFloorItems(false) {
-		itms = append(itms, newScriptItem(item))
+		itms = append(itms, newScriptItem(item, r.roomRecord))
 	}
 	return itms
 }
@@ -96,7 +95,7 @@ func (r ScriptRoom) GetExits() []map[string]any {
 		}
 
 		if exitInfo.HasLock() {
-			lockId := fmt.Sprintf(`%d-%s`, r.roomId, exitName)
+			lockId := exitInfo.LockId(r.roomId, exitName)
 			exitMap["Lock"] = map[string]any{
 				"LockId":     lockId,
 				"Difficulty": exitInfo.Lock.Difficulty,
@@ -106,6 +105,15 @@ func (r ScriptRoom) GetExits() []map[string]any {
 			exitMap["Lock"] = nil
 		}
 
+		if exitInfo.HasDoor() {
+			exitMap["Door"] = map[string]any{
+				"Name": exitInfo.Door.Name,
+				"Open": exitInfo.Door.Open,
+			}
+		} else {
+			exitMap["Door"] = nil
+		}
+
 		exits = append(exits, exitMap)
 	}
 
@@ -134,6 +142,12 @@ func (r ScriptRoom) SetLocked(exitName string, lockIt bool) {
 	}
 }
 
+// Opens or closes the door on an exit, along with the door on the other side.
+// Returns false if the exit has no door.
+func (r ScriptRoom) SetDoorOpen(exitName string, openIt bool) bool {
+	return r.roomRecord.SetDoorOpen(exitName, openIt)
+}
+
 // Returns a list of userIds found to have the questId
 // if userIdParty is specified, will only check users in the party of the user.
 func (r ScriptRoom) HasQuest(questId string, partyUserId ...int) []int {
@@ -295,11 +309,16 @@ func (r ScriptRoom) RemoveMutator(mutName string) {
 	}
 }
 
+func (r ScriptRoom) GetWeather() string {
+	return string(r.roomRecord.GetWeather())
+}
+
 // ////////////////////////////////////////////////////////
 //
 // # These functions get exported to the scripting engine
 //
 // ////////////////////////////////////////////////////////
+
 func GetRoom(roomId int) *ScriptRoom {
 	if room := rooms.LoadRoom(roomId); room != nil {
 		return &ScriptRoom{roomId, room}
@@ -327,5 +346,11 @@ func GetMap(mapRoomId int, mapSize string, mapHeight int, mapWidth int, mapName
 	// mapMarkers   - A list of strings representing custom map markers:
 	//                [roomId],[symbol],[legend text]
 	//                1,×,Here
-	return rooms.GetSpecificMap(mapRoomId, mapSize, mapHeight, mapWidth, mapName, showSecrets, mapMarkers)
+	return rooms.GetSpecificMap(mapRoomId, mapSize, mapHeight, mapWidth, mapName, showSecrets, 0, mapMarkers)
+}
+
+// Same as GetMap, but draws the level above or below mapRoomId
+// mapLevel     - The level to draw, relative to mapRoomId. 1 is the level above, -1 below.
+func GetMapLevel(mapRoomId int, mapSize string, mapHeight int, mapWidth int, mapName string, showSecrets bool, mapLevel int, mapMarkers ...string) string {
+	return rooms.GetSpecificMap(mapRoomId, mapSize, mapHeight, mapWidth, mapName, showSecrets, mapLevel, mapMarkers)
 }
@@ -2,10 +2,12 @@ package scripting
 
 import (
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/dop251/goja"
 	"github.com/volte6/gomud/internal/colorpatterns"
+	"github.com/volte6/gomud/internal/crashreports"
 )
 
 type TextWrapperStyle struct {
@@ -71,15 +73,21 @@ func (t *TextWrapperStyle) Wrap(str string) string {
 var (
 	errNoScript = errors.New("no script")
 	errTimeout  = errors.New("script timeout")
+	errPanic    = errors.New("script panic")
 
 	// If non empty, will wrap output to users or rooms in this style
 	userTextWrap = TextWrapperStyle{}
 	roomTextWrap = TextWrapperStyle{}
 )
 
-func Setup(scriptLoadTimeoutMs int, scriptRoomTimeoutMs int) {
+func Setup(scriptLoadTimeoutMs int, scriptRoomTimeoutMs int, scriptMaxHeapGrowthMB int) {
 	scriptLoadTimeout = time.Duration(scriptLoadTimeoutMs) * time.Millisecond
 	scriptRoomTimeout = time.Duration(scriptRoomTimeoutMs) * time.Millisecond
+	if scriptMaxHeapGrowthMB > 0 {
+		scriptMaxHeapGrowth = uint64(scriptMaxHeapGrowthMB) << 20
+	} else {
+		scriptMaxHeapGrowth = 0
+	}
 }
 
 func setAllScriptingFunctions(vm *goja.Runtime) {
@@ -91,6 +99,32 @@ func setAllScriptingFunctions(vm *goja.Runtime) {
 	setUtilFunctions(vm)
 }
 
+// Recovers from a panic in a script (or in a Go function the script called) so that
+// only the script call fails. Must be deferred directly by the Try*() function.
+// discardVM should remove the VM from its cache, since it may be left in a bad state.
+func recoverScriptPanic(err *error, where string, userId int, mobInstanceId int, roomId int, discardVM func()) {
+
+	r := recover()
+	if r == nil {
+		return
+	}
+
+	userTextWrap.Reset()
+	roomTextWrap.Reset()
+
+	report := crashreports.New(r, `script:`+where)
+	report.UserId = userId
+	report.MobInstanceId = mobInstanceId
+	report.RoomId = roomId
+	report.Save()
+
+	if discardVM != nil {
+		discardVM()
+	}
+
+	*err = fmt.Errorf("%s(): %w: %v", where, errPanic, r)
+}
+
 func PruneVMs(forceClear ...bool) {
 
 	if len(forceClear) > 0 && forceClear[0] {
@@ -99,6 +133,8 @@ func PruneVMs(forceClear ...bool) {
 		ClearBuffVMs()
 		ClearItemVMs()
 		ClearSpellVMs()
+		ClearZoneVMs()
+		ClearCommandVMs()
 	} else {
 		PruneRoomVMs()
 		PruneMobVMs()
@@ -1,9 +1,18 @@
 package scripting
 
 import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/dop251/goja"
+	"github.com/volte6/gomud/internal/characters"
+	"github.com/volte6/gomud/internal/items"
+	"github.com/volte6/gomud/internal/rooms"
+	"github.com/volte6/gomud/internal/util"
 )
 
 const (
@@ -73,3 +82,387 @@ func Benchmark_VMW_Get_Missing_NotCached(b *testing.B) {
 		vmw.GetFunction(`TestMissing`)
 	}
 }
+
+func TestRoomScriptPanicked_CircuitBreaker(t *testing.T) {
+
+	ClearRoomVMs()
+	defer ClearRoomVMs()
+
+	roomId := 12345
+
+	for i := 1; i < roomScriptMaxPanics; i++ {
+		roomScriptPanicked(roomId)
+		if IsRoomScriptDisabled(roomId) {
+			t.Fatalf("room script disabled after %d panic(s), expected %d", i, roomScriptMaxPanics)
+		}
+	}
+
+	roomScriptPanicked(roomId)
+	if !IsRoomScriptDisabled(roomId) {
+		t.Fatalf("room script not disabled after %d panics", roomScriptMaxPanics)
+	}
+
+	if _, err := getRoomVM(roomId); err != errNoScript {
+		t.Errorf("getRoomVM() on a disabled script = %v, expected errNoScript", err)
+	}
+
+	ClearRoomVMs()
+	if IsRoomScriptDisabled(roomId) {
+		t.Errorf("room script still disabled after ClearRoomVMs()")
+	}
+}
+
+func TestPermData_Namespaces(t *testing.T) {
+
+	itm := &items.Item{}
+
+	pushScriptNamespace(`room-1`)
+	if !setPermData(itm, `owner`, `alice`) {
+		t.Fatalf("setPermData() failed in room-1")
+	}
+	popScriptNamespace()
+
+	pushScriptNamespace(`item-2`)
+	if !setPermData(itm, `owner`, `bob`) {
+		t.Fatalf("setPermData() failed in item-2")
+	}
+	if got := getPermData(itm, `owner`); got != `bob` {
+		t.Errorf("getPermData() in item-2 = %v, expected bob", got)
+	}
+	popScriptNamespace()
+
+	pushScriptNamespace(`room-1`)
+	if got := getPermData(itm, `owner`); got != `alice` {
+		t.Errorf("getPermData() in room-1 = %v, expected alice", got)
+	}
+	popScriptNamespace()
+
+	if deleted := ClearPermData(itm, `item-2`); deleted != 1 {
+		t.Errorf("ClearPermData() deleted %d keys, expected 1", deleted)
+	}
+	if namespaces := GetPermDataNamespaces(itm); len(namespaces) != 1 || namespaces[0] != `room-1` {
+		t.Errorf("GetPermDataNamespaces() = %v, expected [room-1]", namespaces)
+	}
+}
+
+func TestScriptItem_SetPermData_SavesToOwner(t *testing.T) {
+
+	pushScriptNamespace(`item-1`)
+	defer popScriptNamespace()
+
+	// Items start without a PermData map, so the copy a script gets doesn't share one
+	char := &characters.Character{Items: []items.Item{{ItemId: 1}}}
+	sActor := ScriptActor{characterRecord: char}
+
+	if !sActor.GetBackpackItems()[0].SetPermData(`owner`, `alice`) {
+		t.Fatalf("SetPermData() failed on a backpack item")
+	}
+	if got := getPermData(&char.Items[0], `owner`); got != `alice` {
+		t.Errorf("backpack item PermData = %v, expected alice", got)
+	}
+
+	room := &rooms.Room{Items: []items.Item{{ItemId: 1}}}
+	sRoom := ScriptRoom{roomRecord: room}
+
+	if !sRoom.GetItems()[0].SetPermData(`owner`, `bob`) {
+		t.Fatalf("SetPermData() failed on a floor item")
+	}
+	if got := getPermData(&room.Items[0], `owner`); got != `bob` {
+		t.Errorf("floor item PermData = %v, expected bob", got)
+	}
+}
+
+func TestScriptTimers(t *testing.T) {
+
+	clearScriptTimers()
+	defer clearScriptTimers()
+	defer ClearZoneVMs()
+
+	if timerId := SetTimeout(1, `onTimer`); timerId != 0 {
+		t.Errorf("SetTimeout() with no running script = %d, expected 0", timerId)
+	}
+
+	pushScriptNamespace(`zone-timertest`)
+	defer popScriptNamespace()
+
+	pushScriptOwner(scriptOwner{kind: ownerZone, zone: `timertest`})
+	timeoutId := SetTimeout(2, `onTimer`)
+	intervalId := SetInterval(1, `onInterval`)
+	popScriptOwner()
+
+	pushScriptOwner(scriptOwner{kind: ownerMob, mobInstanceId: 999999})
+	mobTimerId := SetTimeout(1, `onTimer`)
+	popScriptOwner()
+
+	if timeoutId == 0 || intervalId == 0 || mobTimerId == 0 {
+		t.Fatalf("SetTimeout()/SetInterval() failed: %d, %d, %d", timeoutId, intervalId, mobTimerId)
+	}
+
+	roundNow := util.GetRoundCount()
+
+	ProcessTimers(roundNow + 1)
+
+	if _, ok := scriptTimers[mobTimerId]; ok {
+		t.Errorf("timer for a missing mob was not removed")
+	}
+	if _, ok := scriptTimers[timeoutId]; !ok {
+		t.Errorf("timeout removed before it was due")
+	}
+	if tmr, ok := scriptTimers[intervalId]; !ok || tmr.dueRound != roundNow+2 {
+		t.Errorf("interval was not rescheduled")
+	}
+
+	ProcessTimers(roundNow + 2)
+
+	if _, ok := scriptTimers[timeoutId]; ok {
+		t.Errorf("timeout still exists after it was called")
+	}
+
+	pushScriptNamespace(`room-1`)
+	if ClearTimer(intervalId) {
+		t.Errorf("ClearTimer() cleared another script's timer")
+	}
+	popScriptNamespace()
+
+	if !ClearTimer(intervalId) {
+		t.Errorf("ClearTimer() failed")
+	}
+}
+
+func TestScriptStats_RecordAndDisable(t *testing.T) {
+
+	namespace := `test-stats`
+	defer delete(scriptStats, namespace)
+
+	vm := goja.New()
+	vm.RunString(`function ok() { return true; } function bad() { throw new Error("oops"); }`)
+	vmw := newVMWrapper(vm, 0)
+	vmw.namespace = namespace
+
+	okFn, found := vmw.GetFunction(`ok`)
+	if !found {
+		t.Fatalf("GetFunction(ok) not found")
+	}
+	okFn(goja.Undefined())
+
+	badFn, _ := vmw.GetFunction(`bad`)
+	if _, err := badFn(goja.Undefined()); err == nil {
+		t.Fatalf("bad() did not return an error")
+	}
+
+	stats := getScriptStats(namespace)
+	if stats.Calls != 2 || stats.Exceptions != 1 {
+		t.Errorf("stats = %d calls, %d exceptions, expected 2 calls, 1 exception", stats.Calls, stats.Exceptions)
+	}
+
+	DisableScript(strings.ToUpper(namespace))
+	if _, found := vmw.GetFunction(`ok`); found {
+		t.Errorf("GetFunction() found a function in a disabled script")
+	}
+
+	if !EnableScript(namespace) {
+		t.Errorf("EnableScript() = false, expected true")
+	}
+	if _, found := vmw.GetFunction(`ok`); !found {
+		t.Errorf("GetFunction() did not find a function after the script was enabled")
+	}
+}
+
+func TestRunScriptTests_Results(t *testing.T) {
+
+	testFile := filepath.Join(t.TempDir(), `example.test.js`)
+	testScript := "test(`passes`, function() {\n    AssertEqual(1+1, 2);\n});\n" +
+		"test(`fails`, function() {\n    AssertEqual(1+1, 3, `math`);\n});\n"
+
+	if err := os.WriteFile(testFile, []byte(testScript), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	out := &strings.Builder{}
+	passed, failed := RunScriptTests(filepath.Dir(testFile), out)
+
+	if passed != 1 || failed != 1 {
+		t.Fatalf("RunScriptTests() = %d passed, %d failed, expected 1 and 1\n%s", passed, failed, out)
+	}
+
+	if expected := testFile + `:5:`; !strings.Contains(out.String(), expected) {
+		t.Errorf("RunScriptTests() output does not contain the failing line %q\n%s", expected, out)
+	}
+}
+
+func TestLoadCommandScript_Definition(t *testing.T) {
+
+	scriptFile := filepath.Join(t.TempDir(), `wave.js`)
+	script := "var command = { name: `Greet`, aliases: [`hi`, `hello`], help: `Says hi.`, adminOnly: true };\n" +
+		"function onCommand(rest, user, room) { return true; }\n"
+
+	if err := os.WriteFile(scriptFile, []byte(script), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd, err := loadCommandScript(scriptFile)
+	if err != nil {
+		t.Fatalf("loadCommandScript() error = %v", err)
+	}
+
+	if cmd.Name != `greet` || len(cmd.Aliases) != 2 || cmd.Help != `Says hi.` || !cmd.AdminOnly || cmd.AllowedWhenDowned {
+		t.Errorf("loadCommandScript() = %+v, definition not read correctly", *cmd)
+	}
+
+	if cmd.vmw == nil || cmd.vmw.namespace != `command-greet` {
+		t.Errorf("loadCommandScript() did not set up the VM for the command-greet namespace")
+	}
+
+	// Without a definition, the command is named after the file
+	if err := os.WriteFile(scriptFile, []byte("function onCommand(rest, user, room) {}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if cmd, err = loadCommandScript(scriptFile); err != nil || cmd.Name != `wave` {
+		t.Errorf("loadCommandScript() without a definition = %v, %v, expected the name wave", cmd, err)
+	}
+
+	// onCommand() is required
+	if err := os.WriteFile(scriptFile, []byte("var command = {};\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = loadCommandScript(scriptFile); err == nil {
+		t.Errorf("loadCommandScript() without onCommand() should fail")
+	}
+}
+
+func TestLoadCommandScripts_ConcurrentReads(t *testing.T) {
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, commandScriptsPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		os.Chdir(wd)
+		LoadCommandScripts()
+	})
+
+	scriptFile := filepath.Join(commandScriptsPath, `wave.js`)
+	script := "var command = { aliases: [`wv`] };\n" +
+		"function onCommand(rest, user, room) { return true; }\n"
+	if err := os.WriteFile(scriptFile, []byte(script), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Autocomplete and help read the commands from connection goroutines
+	done := make(chan struct{})
+	readerDone := make(chan struct{})
+	go func() {
+		defer close(readerDone)
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			for _, cmd := range GetScriptCommands() {
+				GetScriptCommand(cmd.Name)
+			}
+			GetScriptCommand(`wv`)
+		}
+	}()
+
+	modTime := time.Now()
+	for i := 0; i < 50; i++ {
+		// A new modified time makes the script reload
+		modTime = modTime.Add(time.Second)
+		if err := os.Chtimes(scriptFile, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+		LoadCommandScripts()
+		ClearCommandVMs()
+		runtime.Gosched()
+	}
+
+	close(done)
+	<-readerDone
+
+	if cmd, ok := GetScriptCommand(`wv`); !ok || cmd.Name != `wave` {
+		t.Errorf("GetScriptCommand(wv) = %+v, %v, expected the wave command", cmd, ok)
+	}
+}
+
+func TestHookDetails_ScriptChanges(t *testing.T) {
+
+	vm := goja.New()
+	vm.RunString("function onPlayerDeath(details) { details.dropGold = false; details.equipmentDropChance = 0.5; details.xpPenalty = `10%`; details.gold = 25; }")
+
+	details := HookDetails{
+		`dropGold`:            true,
+		`equipmentDropChance`: 0.0,
+		`xpPenalty`:           `none`,
+		`gold`:                10,
+	}
+
+	fn, _ := goja.AssertFunction(vm.Get(`onPlayerDeath`))
+	if _, err := fn(goja.Undefined(), vm.ToValue(map[string]any(details))); err != nil {
+		t.Fatal(err)
+	}
+
+	if details.Bool(`dropGold`) || details.Float(`equipmentDropChance`) != 0.5 || details.String(`xpPenalty`) != `10%` || details.Int(`gold`) != 25 {
+		t.Errorf("HookDetails changes made by the script were lost: %v", details)
+	}
+}
+
+func TestEvalInVM(t *testing.T) {
+
+	vm := goja.New()
+	vm.RunString(`var counter = 5;`)
+	vmw := newVMWrapper(vm, 0)
+
+	tests := []struct {
+		code    string
+		want    string
+		wantErr bool
+	}{
+		{`counter + 1`, `6`, false},
+		{`counter = 10; counter`, `10`, false},
+		{`({a: counter, b: [1, 2]})`, `{"a":10,"b":[1,2]}`, false},
+		{`var x;`, `undefined`, false},
+		{`throw new Error("oops")`, ``, true},
+		{`while (true) {}`, ``, true},
+	}
+
+	for _, tt := range tests {
+		got, err := evalInVM(vmw, tt.code)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("evalInVM(%q) = %q, %v, want %q (error: %v)", tt.code, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestReloadScriptFile_KeepsVMOnCompileError(t *testing.T) {
+
+	roomId := 999999
+	scriptPath := filepath.Join(t.TempDir(), `999999.js`)
+	if err := os.WriteFile(scriptPath, []byte(`function onEnter( {`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	oldVM := newVMWrapper(goja.New(), 0)
+	roomVMCache[roomId] = oldVM
+	setScriptFile(`room-999999`, scriptPath)
+	defer func() {
+		delete(roomVMCache, roomId)
+		delete(scriptStats, `room-999999`)
+	}()
+
+	if err := reloadScriptFile(scriptPath); err == nil {
+		t.Fatal("reloadScriptFile() expected a compile error")
+	}
+
+	if roomVMCache[roomId] != oldVM {
+		t.Error("reloadScriptFile() replaced the VM of a script that doesn't compile")
+	}
+}
@@ -0,0 +1,606 @@
+package scripting
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Volte6/ansitags"
+	"github.com/dop251/goja"
+	"github.com/volte6/gomud/internal/events"
+	"github.com/volte6/gomud/internal/items"
+	"github.com/volte6/gomud/internal/mobs"
+	"github.com/volte6/gomud/internal/rooms"
+	"github.com/volte6/gomud/internal/users"
+	"github.com/volte6/gomud/internal/util"
+)
+
+const (
+	// Test files are named after the script they test, e.g. 1.js is tested by 1.test.js
+	scriptTestSuffix = `.test.js`
+	// How long a single test can run for
+	scriptTestTimeout = 5 * time.Second
+	// Fixture rooms, users and connections are numbered from here, so they never clash with real ones
+	scriptTestIdStart = 1000000
+	// Zone that fixture rooms are created in
+	scriptTestZone = `Script Tests`
+)
+
+var (
+	errAssertFailed = errors.New("assertion failed")
+
+	scriptTestIdCounter = scriptTestIdStart
+)
+
+type scriptTestCase struct {
+	name string
+	fn   goja.Callable
+}
+
+// Everything needed to run the tests in a single test file
+type scriptTestRun struct {
+	vm         *goja.Runtime
+	testFile   string
+	scriptFile string // The script under test
+	tests      []scriptTestCase
+	failure    string // Set by a failed assertion
+
+	// Everything scripts have sent since the last ClearMessages()
+	messages []events.Message
+	inputs   []events.Input
+}
+
+// Runs every *.test.js file found in path (a file or folder), writing the results to out.
+// The data files must already be loaded, since fixtures are built from real mob and item specs.
+func RunScriptTests(path string, out io.Writer) (passed int, failed int) {
+
+	testFiles := []string{}
+
+	if info, err := os.Stat(path); err != nil {
+		fmt.Fprintf(out, "%s: %v\n", path, err)
+		return 0, 1
+	} else if !info.IsDir() {
+		testFiles = append(testFiles, path)
+	} else {
+		filepath.Walk(path, func(filePath string, info os.FileInfo, err error) error {
+			if err == nil && !info.IsDir() && strings.HasSuffix(filePath, scriptTestSuffix) {
+				testFiles = append(testFiles, filePath)
+			}
+			return nil
+		})
+		sort.Strings(testFiles)
+	}
+
+	if len(testFiles) == 0 {
+		fmt.Fprintf(out, "No %s files found in %s\n", scriptTestSuffix, path)
+		return 0, 0
+	}
+
+	for _, testFile := range testFiles {
+		p, f := runScriptTestFile(testFile, out)
+		passed += p
+		failed += f
+	}
+
+	fmt.Fprintf(out, "\n%d passed, %d failed\n", passed, failed)
+
+	return passed, failed
+}
+
+func runScriptTestFile(testFile string, out io.Writer) (passed int, failed int) {
+
+	fmt.Fprintf(out, "%s\n", testFile)
+
+	t := &scriptTestRun{
+		vm:         goja.New(),
+		testFile:   testFile,
+		scriptFile: strings.TrimSuffix(testFile, scriptTestSuffix) + `.js`,
+	}
+
+	setAllScriptingFunctions(t.vm)
+	t.setTestFunctions()
+
+	source, err := os.ReadFile(testFile)
+	if err != nil {
+		fmt.Fprintf(out, "  FAIL %v\n", err)
+		return 0, 1
+	}
+
+	if _, err := t.vm.RunScript(testFile, string(source)); err != nil {
+		fmt.Fprintf(out, "  FAIL %v\n", err)
+		return 0, 1
+	}
+
+	for _, tc := range t.tests {
+		if errMsg := t.runTest(tc); errMsg != `` {
+			fmt.Fprintf(out, "  FAIL %s\n       %s\n", tc.name, errMsg)
+			failed++
+			continue
+		}
+		fmt.Fprintf(out, "  ok   %s\n", tc.name)
+		passed++
+	}
+
+	return passed, failed
+}
+
+// Runs a single test, returning an error message if it failed
+func (t *scriptTestRun) runTest(tc scriptTestCase) (errMsg string) {
+
+	t.failure = ``
+	t.ClearMessages()
+
+	defer func() {
+		if r := recover(); r != nil {
+			errMsg = fmt.Sprintf("panic: %v", r)
+		}
+		userTextWrap.Reset()
+		roomTextWrap.Reset()
+	}()
+
+	tmr := time.AfterFunc(scriptTestTimeout, func() {
+		t.vm.Interrupt(errTimeout)
+	})
+	_, err := tc.fn(goja.Undefined())
+	t.vm.ClearInterrupt()
+	tmr.Stop()
+
+	if t.failure != `` {
+		return t.failure
+	}
+	if err != nil {
+		return err.Error()
+	}
+	return ``
+}
+
+func (t *scriptTestRun) setTestFunctions() {
+
+	t.vm.Set(`test`, t.Test)
+
+	t.vm.Set(`Assert`, t.Assert)
+	t.vm.Set(`AssertEqual`, t.AssertEqual)
+	t.vm.Set(`AssertContains`, t.AssertContains)
+
+	t.vm.Set(`NewFixtureRoom`, t.NewFixtureRoom)
+	t.vm.Set(`NewFixtureUser`, t.NewFixtureUser)
+	t.vm.Set(`NewFixtureMob`, t.NewFixtureMob)
+	t.vm.Set(`NewFixtureItem`, t.NewFixtureItem)
+	t.vm.Set(`AttachScript`, t.AttachScript)
+
+	t.vm.Set(`FireRoomEvent`, t.FireRoomEvent)
+	t.vm.Set(`FireRoomCommand`, t.FireRoomCommand)
+	t.vm.Set(`FireMobEvent`, t.FireMobEvent)
+	t.vm.Set(`FireMobCommand`, t.FireMobCommand)
+	t.vm.Set(`FireItemEvent`, t.FireItemEvent)
+	t.vm.Set(`FireItemCommand`, t.FireItemCommand)
+	t.vm.Set(`AdvanceRounds`, t.AdvanceRounds)
+
+	t.vm.Set(`GetMessages`, t.GetMessages)
+	t.vm.Set(`GetRoomMessages`, t.GetRoomMessages)
+	t.vm.Set(`GetCommands`, t.GetCommands)
+	t.vm.Set(`ClearMessages`, t.ClearMessages)
+}
+
+// Returns "file:line:column" for the line of the test file that is currently running
+func (t *scriptTestRun) testPosition() string {
+	for _, frame := range t.vm.CaptureCallStack(0, nil) {
+		if frame.SrcName() == t.testFile {
+			pos := frame.Position()
+			return fmt.Sprintf(`%s:%d:%d`, t.testFile, pos.Line, pos.Column)
+		}
+	}
+	return t.testFile
+}
+
+// Records a failure and stops the current test
+func (t *scriptTestRun) fail(msg string) {
+	t.failure = fmt.Sprintf(`%s: %s`, t.testPosition(), msg)
+	panic(t.vm.NewGoError(errAssertFailed))
+}
+
+// Copies anything scripts have sent out of the event queues
+func (t *scriptTestRun) captureEvents() {
+
+	msgQueue := events.GetQueue(events.Message{})
+	for msgQueue.Len() > 0 {
+		if msg, ok := msgQueue.Poll().(events.Message); ok {
+			t.messages = append(t.messages, msg)
+		}
+	}
+
+	inputQueue := events.GetQueue(events.Input{})
+	for inputQueue.Len() > 0 {
+		if input, ok := inputQueue.Poll().(events.Input); ok {
+			t.inputs = append(t.inputs, input)
+		}
+	}
+}
+
+// Loads the script under test for a fixture and runs its onLoad()
+func (t *scriptTestRun) loadScript(namespace string, owner scriptOwner, onLoadArg any) (*VMWrapper, error) {
+
+	script, err := os.ReadFile(t.scriptFile)
+	if err != nil {
+		return nil, err
+	}
+
+	pushScriptNamespace(namespace)
+	defer popScriptNamespace()
+	pushScriptOwner(owner)
+	defer popScriptOwner()
+
+	vm := goja.New()
+	setAllScriptingFunctions(vm)
+
+	// Named after the file so that errors show where in the script they happened
+	prg, err := goja.Compile(t.scriptFile, string(script), false)
+	if err != nil {
+		return nil, fmt.Errorf("Compile: %w", err)
+	}
+
+	tmr := time.AfterFunc(scriptLoadTimeout, func() {
+		vm.Interrupt(errTimeout)
+	})
+	defer tmr.Stop()
+	defer vm.ClearInterrupt()
+
+	if _, err = vm.RunProgram(prg); err != nil {
+		return nil, fmt.Errorf("RunProgram: %w", err)
+	}
+
+	if fn, ok := goja.AssertFunction(vm.Get(`onLoad`)); ok {
+		if _, err := fn(goja.Undefined(), vm.ToValue(onLoadArg)); err != nil {
+			return nil, fmt.Errorf("onLoad: %w", err)
+		}
+	}
+
+	vmw := newVMWrapper(vm, 0)
+	vmw.namespace = namespace
+	setScriptFile(namespace, t.scriptFile)
+
+	return vmw, nil
+}
+
+// ////////////////////////////////////////////////////////
+//
+// # These functions get exported to the test files
+//
+// ////////////////////////////////////////////////////////
+
+// Registers a test to run once the test file has loaded
+func (t *scriptTestRun) Test(name string, fn goja.Callable) {
+	t.tests = append(t.tests, scriptTestCase{name: name, fn: fn})
+}
+
+func (t *scriptTestRun) Assert(condition bool, message ...string) {
+	if !condition {
+		msg := `expected true`
+		if len(message) > 0 {
+			msg = message[0]
+		}
+		t.fail(msg)
+	}
+}
+
+func (t *scriptTestRun) AssertEqual(actual goja.Value, expected goja.Value, message ...string) {
+	if actual.StrictEquals(expected) {
+		return
+	}
+	msg := fmt.Sprintf(`got %v, expected %v`, actual, expected)
+	if len(message) > 0 {
+		msg = message[0] + `: ` + msg
+	}
+	t.fail(msg)
+}
+
+// Checks whether a string, or any string in a list, contains the text
+func (t *scriptTestRun) AssertContains(haystack any, needle string, message ...string) {
+
+	found := false
+	switch v := haystack.(type) {
+	case string:
+		found = strings.Contains(v, needle)
+	case []any:
+		for _, s := range v {
+			if str, ok := s.(string); ok && strings.Contains(str, needle) {
+				found = true
+				break
+			}
+		}
+	case []string:
+		for _, str := range v {
+			if strings.Contains(str, needle) {
+				found = true
+				break
+			}
+		}
+	}
+
+	if found {
+		return
+	}
+	msg := fmt.Sprintf(`%q not found in %v`, needle, haystack)
+	if len(message) > 0 {
+		msg = message[0] + `: ` + msg
+	}
+	t.fail(msg)
+}
+
+// Creates an empty room that only exists in memory
+func (t *scriptTestRun) NewFixtureRoom(title ...string) *ScriptRoom {
+
+	scriptTestIdCounter++
+
+	room := rooms.NewTemporaryRoom(scriptTestIdCounter, scriptTestZone)
+	if len(title) > 0 {
+		room.Title = title[0]
+	}
+
+	return GetRoom(room.RoomId)
+}
+
+// Creates a logged in user standing in a room.
+// Fixture users are never saved.
+func (t *scriptTestRun) NewFixtureUser(name string, room *ScriptRoom) *ScriptActor {
+
+	if room == nil {
+		t.fail(`NewFixtureUser() needs a room`)
+	}
+
+	scriptTestIdCounter++
+
+	user := users.NewUserRecord(scriptTestIdCounter, uint64(scriptTestIdCounter))
+	user.Username = fmt.Sprintf(`%s%d`, name, scriptTestIdCounter)
+	user.Character.Name = name
+	user.Character.RoomId = room.roomId
+
+	if _, _, err := users.LoginUser(user, uint64(scriptTestIdCounter)); err != nil {
+		t.fail(err.Error())
+	}
+
+	// Low level users are normally given a guide mob when they move, which would get in the way of tests
+	user.SetTempData(`lastGuideRound`, util.GetRoundCount())
+
+	if err := rooms.MoveToRoom(user.UserId, room.roomId); err != nil {
+		t.fail(err.Error())
+	}
+
+	t.captureEvents()
+
+	return GetActor(user.UserId, 0)
+}
+
+// Creates a mob from a real mob spec, standing in a room
+func (t *scriptTestRun) NewFixtureMob(mobId int, room *ScriptRoom) *ScriptActor {
+
+	if room == nil {
+		t.fail(`NewFixtureMob() needs a room`)
+	}
+
+	mob := mobs.NewMobById(mobs.MobId(mobId), room.roomId)
+	if mob == nil {
+		t.fail(fmt.Sprintf(`mob %d not found`, mobId))
+	}
+	room.roomRecord.AddMob(mob.InstanceId)
+
+	return GetActor(0, mob.InstanceId)
+}
+
+// Creates an item from a real item spec and puts it in a users backpack
+func (t *scriptTestRun) NewFixtureItem(itemId int, user *ScriptActor) *ScriptItem {
+
+	itm := items.New(itemId)
+	if itm.ItemId == 0 {
+		t.fail(fmt.Sprintf(`item %d not found`, itemId))
+	}
+
+	if user != nil && !user.characterRecord.StoreItem(itm) {
+		t.fail(`could not give the item to ` + user.characterRecord.Name)
+	}
+
+	return GetItem(itm)
+}
+
+// Makes a fixture room, mob or item use the script under test.
+// For mobs and items, every fixture of the same type shares the script.
+func (t *scriptTestRun) AttachScript(target goja.Value) {
+
+	var err error
+	var vmw *VMWrapper
+
+	switch v := target.Export().(type) {
+	case *ScriptRoom:
+		namespace := roomScriptNamespace(v.roomId)
+		if vmw, err = t.loadScript(namespace, scriptOwner{kind: ownerRoom, roomId: v.roomId}, v); err == nil {
+			delete(disabledRoomScripts, v.roomId)
+			roomVMCache[v.roomId] = vmw
+		}
+	case *ScriptActor:
+		if v.mobRecord == nil {
+			t.fail(`AttachScript() only works on mobs, not users`)
+		}
+		scriptId := mobScriptId(v)
+		owner := scriptOwner{kind: ownerMob, mobInstanceId: v.mobInstanceId, roomId: v.GetRoomId()}
+		if vmw, err = t.loadScript(`mob-`+scriptId, owner, v); err == nil {
+			mobVMCache[scriptId] = vmw
+		}
+	case *ScriptItem:
+		scriptId := strconv.Itoa(v.ItemId())
+		if vmw, err = t.loadScript(`item-`+scriptId, scriptOwner{}, v); err == nil {
+			itemVMCache[scriptId] = vmw
+		}
+	default:
+		t.fail(`AttachScript() needs a room, mob or item`)
+	}
+
+	t.captureEvents()
+
+	if err != nil {
+		t.fail(fmt.Sprintf(`%s: %v`, t.scriptFile, err))
+	}
+}
+
+// Calls a room event such as onEnter or onExit
+func (t *scriptTestRun) FireRoomEvent(eventName string, user *ScriptActor, room *ScriptRoom) bool {
+	userId := 0
+	if user != nil {
+		userId = user.userId
+	}
+	handled, err := TryRoomScriptEvent(eventName, userId, room.roomId)
+	return t.handleFired(handled, err)
+}
+
+// Types a command as the user, in whichever room they are in
+func (t *scriptTestRun) FireRoomCommand(cmd string, rest string, user *ScriptActor) bool {
+	handled, err := TryRoomCommand(cmd, rest, user.userId)
+	return t.handleFired(handled, err)
+}
+
+// Calls a mob event such as onGive or onDie.
+// The source is the user or mob that caused the event.
+func (t *scriptTestRun) FireMobEvent(eventName string, mob *ScriptActor, source *ScriptActor, details map[string]any) bool {
+
+	sourceId, sourceType := 0, ``
+	if source != nil {
+		if source.userId > 0 {
+			sourceId, sourceType = source.userId, `user`
+		} else {
+			sourceId, sourceType = source.mobInstanceId, `mob`
+		}
+	}
+
+	// Scripts receive the item itself, the same as they would in game
+	for key, value := range details {
+		if sItem, ok := value.(*ScriptItem); ok {
+			details[key] = *sItem.itemRecord
+		}
+	}
+
+	handled, err := TryMobScriptEvent(eventName, mob.mobInstanceId, sourceId, sourceType, details)
+	return t.handleFired(handled, err)
+}
+
+// Types a command at the mob, as the source user or mob
+func (t *scriptTestRun) FireMobCommand(cmd string, rest string, mob *ScriptActor, source *ScriptActor) bool {
+
+	sourceId, sourceType := source.userId, `user`
+	if sourceId == 0 {
+		sourceId, sourceType = source.mobInstanceId, `mob`
+	}
+
+	handled, err := TryMobCommand(cmd, rest, mob.mobInstanceId, sourceId, sourceType)
+	return t.handleFired(handled, err)
+}
+
+// Calls an item event such as onFound or onLost
+func (t *scriptTestRun) FireItemEvent(eventName string, item *ScriptItem, user *ScriptActor) bool {
+	handled, err := TryItemScriptEvent(eventName, *item.itemRecord, user.userId)
+	t.refreshItem(item, user)
+	return t.handleFired(handled, err)
+}
+
+// Types a command while carrying the item
+func (t *scriptTestRun) FireItemCommand(cmd string, item *ScriptItem, user *ScriptActor) bool {
+	handled, err := TryItemCommand(cmd, *item.itemRecord, user.userId)
+	t.refreshItem(item, user)
+	return t.handleFired(handled, err)
+}
+
+// Moves the round counter forward, running any script timers that come due
+func (t *scriptTestRun) AdvanceRounds(rounds int) {
+	for i := 0; i < rounds; i++ {
+		ProcessTimers(util.IncrementRoundCount())
+	}
+	t.captureEvents()
+}
+
+// Returns the text of every message the user could have seen, with color tags removed
+func (t *scriptTestRun) GetMessages(user *ScriptActor) []string {
+
+	t.captureEvents()
+
+	ret := []string{}
+	for _, msg := range t.messages {
+		if msg.UserId == user.userId {
+			ret = append(ret, scriptTestText(msg.Text))
+			continue
+		}
+		if msg.UserId == 0 && msg.RoomId == user.GetRoomId() {
+			excluded := false
+			for _, excludeId := range msg.ExcludeUserIds {
+				if excludeId == user.userId {
+					excluded = true
+					break
+				}
+			}
+			if !excluded {
+				ret = append(ret, scriptTestText(msg.Text))
+			}
+		}
+	}
+	return ret
+}
+
+// Returns the text of every message sent to a room, with color tags removed
+func (t *scriptTestRun) GetRoomMessages(room *ScriptRoom) []string {
+
+	t.captureEvents()
+
+	ret := []string{}
+	for _, msg := range t.messages {
+		if msg.UserId == 0 && msg.RoomId == room.roomId {
+			ret = append(ret, scriptTestText(msg.Text))
+		}
+	}
+	return ret
+}
+
+// Returns every command the user or mob was made to type
+func (t *scriptTestRun) GetCommands(actor *ScriptActor) []string {
+
+	t.captureEvents()
+
+	ret := []string{}
+	for _, input := range t.inputs {
+		if (actor.userId > 0 && input.UserId == actor.userId) || (actor.mobInstanceId > 0 && input.MobInstanceId == actor.mobInstanceId) {
+			ret = append(ret, input.InputText)
+		}
+	}
+	return ret
+}
+
+func (t *scriptTestRun) ClearMessages() {
+	t.captureEvents()
+	t.messages = t.messages[:0]
+	t.inputs = t.inputs[:0]
+}
+
+// Script errors fail the test, so that the line number in the script is reported
+func (t *scriptTestRun) handleFired(handled bool, err error) bool {
+	t.captureEvents()
+	if err != nil && !errors.Is(err, errNoScript) {
+		t.fail(err.Error())
+	}
+	return handled
+}
+
+// Points a fixture item at the copy the script may have changed
+func (t *scriptTestRun) refreshItem(item *ScriptItem, user *ScriptActor) {
+	for _, itm := range user.characterRecord.Items {
+		if itm.Equals(*item.itemRecord) {
+			*item.itemRecord = itm
+			return
+		}
+	}
+}
+
+func scriptTestText(txt string) string {
+	return strings.TrimSpace(ansitags.Parse(txt, ansitags.StripTags))
+}
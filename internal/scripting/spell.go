@@ -25,7 +25,13 @@ func PruneSpellVMs(instanceIds ...int) {
 
 }
 
-func TrySpellScriptEvent(eventName string, sourceUserId int, sourceMobInstanceId int, spellAggro characters.SpellAggroInfo) (bool, error) {
+func TrySpellScriptEvent(eventName string, sourceUserId int, sourceMobInstanceId int, spellAggro characters.SpellAggroInfo) (handled bool, err error) {
+
+	// getSpellVM() caches spell VM's alongside the item VM's
+	defer recoverScriptPanic(&err, eventName, sourceUserId, sourceMobInstanceId, 0, func() { delete(itemVMCache, spellAggro.SpellId) })
+
+	pushScriptOwner(scriptOwner{})
+	defer popScriptOwner()
 
 	spellInfo := spells.GetSpell(spellAggro.SpellId)
 	if spellInfo == nil {
@@ -175,10 +181,14 @@ func getSpellVM(scriptId string) (*VMWrapper, error) {
 		return nil, errNoScript
 	}
 
+	namespace := fmt.Sprintf(`spell-%s`, scriptId)
+	pushScriptNamespace(namespace)
+	defer popScriptNamespace()
+
 	vm := goja.New()
 	setAllScriptingFunctions(vm)
 
-	prg, err := goja.Compile(fmt.Sprintf(`spell-%s`, scriptId), script, false)
+	prg, err := goja.Compile(namespace, script, false)
 	if err != nil {
 		finalErr := fmt.Errorf("Compile: %w", err)
 		return nil, finalErr
@@ -210,6 +220,8 @@ func getSpellVM(scriptId string) (*VMWrapper, error) {
 	tmr.Stop()
 
 	vmw := newVMWrapper(vm, 0)
+	vmw.namespace = namespace
+	setScriptFile(namespace, spellData.GetScriptPath())
 
 	itemVMCache[scriptId] = vmw
 
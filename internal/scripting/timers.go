@@ -0,0 +1,421 @@
+package scripting
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/dop251/goja"
+	"github.com/volte6/gomud/internal/items"
+	"github.com/volte6/gomud/internal/mobs"
+	"github.com/volte6/gomud/internal/rooms"
+	"github.com/volte6/gomud/internal/users"
+	"github.com/volte6/gomud/internal/util"
+)
+
+const (
+	// Stops a runaway script from creating endless timers
+	scriptMaxTimersPerOwner = 50
+
+	ownerRoom = `room`
+	ownerMob  = `mob`
+	ownerItem = `item`
+	ownerZone = `zone`
+)
+
+var (
+	timerIdCounter = 0
+	scriptTimers   = map[int]*scriptTimer{}
+
+	// Whatever the running script belongs to. Timers created by the script will call back into it.
+	ownerStack = []scriptOwner{}
+
+	// Rooms whose saved timers have already been loaded since the server started
+	restoredRoomTimers = map[int]struct{}{}
+)
+
+// Who a timer belongs to
+type scriptOwner struct {
+	kind          string
+	roomId        int
+	mobInstanceId int
+	userId        int        // Who is carrying the item
+	item          items.Item // Only used for items
+	zone          string     // Only used for zones. Empty is the world script.
+}
+
+type scriptTimer struct {
+	id             int
+	namespace      string
+	owner          scriptOwner
+	function       string
+	args           []any
+	dueRound       uint64
+	intervalRounds int
+	persist        bool
+}
+
+func pushScriptOwner(owner scriptOwner) {
+	ownerStack = append(ownerStack, owner)
+}
+
+func popScriptOwner() {
+	if len(ownerStack) > 0 {
+		ownerStack = ownerStack[:len(ownerStack)-1]
+	}
+}
+
+func currentScriptOwner() (scriptOwner, bool) {
+	if len(ownerStack) == 0 {
+		return scriptOwner{}, false
+	}
+	return ownerStack[len(ownerStack)-1], true
+}
+
+// Creates a timer for whichever script is currently running.
+// Returns the new timer id, or 0 if no timer could be created.
+func addScriptTimer(rounds int, intervalRounds int, fnName string, args []any) int {
+
+	owner, ok := currentScriptOwner()
+	if !ok || owner.kind == `` {
+		slog.Error("SetTimeout()", "error", "timers can only be created by room, mob, item and zone scripts", "function", fnName)
+		return 0
+	}
+
+	if rounds < 1 {
+		rounds = 1
+	}
+
+	ownerCt := 0
+	for _, t := range scriptTimers {
+		if t.owner.kind == owner.kind && t.owner.roomId == owner.roomId && t.owner.mobInstanceId == owner.mobInstanceId && t.owner.zone == owner.zone {
+			ownerCt++
+		}
+	}
+	if ownerCt >= scriptMaxTimersPerOwner {
+		slog.Error("SetTimeout()", "error", "too many timers", "namespace", currentScriptNamespace(), "function", fnName, "max", scriptMaxTimersPerOwner)
+		return 0
+	}
+
+	timerIdCounter++
+
+	scriptTimers[timerIdCounter] = &scriptTimer{
+		id:             timerIdCounter,
+		namespace:      currentScriptNamespace(),
+		owner:          owner,
+		function:       fnName,
+		args:           args,
+		dueRound:       util.GetRoundCount() + uint64(rounds),
+		intervalRounds: intervalRounds,
+	}
+
+	return timerIdCounter
+}
+
+// Marks a room timer to be saved with the room, so that it continues after a restart.
+// Only plain data (strings, numbers, lists etc.) can be passed to a persistent timer.
+func persistScriptTimer(timerId int) bool {
+
+	t, ok := scriptTimers[timerId]
+	if !ok || t.namespace != currentScriptNamespace() || t.owner.kind != ownerRoom {
+		return false
+	}
+
+	for _, arg := range t.args {
+		switch arg.(type) {
+		case ScriptActor, *ScriptActor, ScriptItem, *ScriptItem, ScriptRoom, *ScriptRoom:
+			return false
+		}
+	}
+
+	t.persist = true
+	saveRoomTimers(t.owner.roomId)
+
+	return true
+}
+
+// Cancels a timer. Scripts can only cancel their own timers.
+func clearScriptTimer(timerId int) bool {
+
+	t, ok := scriptTimers[timerId]
+	if !ok || t.namespace != currentScriptNamespace() {
+		return false
+	}
+
+	removeScriptTimer(t)
+
+	return true
+}
+
+func removeScriptTimer(t *scriptTimer) {
+	delete(scriptTimers, t.id)
+	if t.persist {
+		saveRoomTimers(t.owner.roomId)
+	}
+}
+
+// Copies any persistent timers for a room into the room data, so they are saved with it.
+func saveRoomTimers(roomId int) {
+
+	room := rooms.LoadRoom(roomId)
+	if room == nil {
+		return
+	}
+
+	room.ScriptTimers = []rooms.ScriptTimer{}
+	for _, t := range sortedScriptTimers() {
+		if !t.persist || t.owner.kind != ownerRoom || t.owner.roomId != roomId {
+			continue
+		}
+		room.ScriptTimers = append(room.ScriptTimers, rooms.ScriptTimer{
+			Function:       t.function,
+			Args:           t.args,
+			DueRound:       t.dueRound,
+			IntervalRounds: t.intervalRounds,
+		})
+	}
+}
+
+// Recreates the timers saved with every room, so they run even if the room's script is never loaded.
+// Should be called once rooms are loaded.
+func RestoreRoomTimers() {
+	for _, roomId := range rooms.GetRoomsWithScriptTimers() {
+		if room := rooms.LoadRoom(roomId); room != nil {
+			restoreRoomTimers(room)
+		}
+	}
+}
+
+// Recreates timers that were saved with a room.
+// Only happens once per room, since the timers stay in memory even if the room or its script are unloaded.
+func restoreRoomTimers(room *rooms.Room) {
+
+	if _, ok := restoredRoomTimers[room.RoomId]; ok {
+		return
+	}
+	restoredRoomTimers[room.RoomId] = struct{}{}
+
+	for _, saved := range room.ScriptTimers {
+		timerIdCounter++
+		scriptTimers[timerIdCounter] = &scriptTimer{
+			id:             timerIdCounter,
+			namespace:      roomScriptNamespace(room.RoomId),
+			owner:          scriptOwner{kind: ownerRoom, roomId: room.RoomId},
+			function:       saved.Function,
+			args:           saved.Args,
+			dueRound:       saved.DueRound,
+			intervalRounds: saved.IntervalRounds,
+			persist:        true,
+		}
+	}
+}
+
+func sortedScriptTimers() []*scriptTimer {
+	timers := make([]*scriptTimer, 0, len(scriptTimers))
+	for _, t := range scriptTimers {
+		timers = append(timers, t)
+	}
+	sort.Slice(timers, func(i, j int) bool {
+		return timers[i].id < timers[j].id
+	})
+	return timers
+}
+
+// Runs any timers that are due, and drops timers whose owner no longer exists.
+// Should be called once per round.
+func ProcessTimers(roundNumber uint64) {
+
+	for _, t := range sortedScriptTimers() {
+
+		// May have been cleared by another timer this round
+		if _, ok := scriptTimers[t.id]; !ok {
+			continue
+		}
+
+		if !t.owner.exists() {
+			removeScriptTimer(t)
+			continue
+		}
+
+		if t.dueRound > roundNumber {
+			continue
+		}
+
+		if t.intervalRounds > 0 {
+			t.dueRound = roundNumber + uint64(t.intervalRounds)
+			if t.persist {
+				saveRoomTimers(t.owner.roomId)
+			}
+		} else {
+			removeScriptTimer(t)
+		}
+
+		if _, err := tryTimerFunction(t); err != nil && !errors.Is(err, errNoScript) {
+			slog.Error("ProcessTimers()", "timerId", t.id, "namespace", t.namespace, "function", t.function, "error", err)
+		}
+	}
+}
+
+// Removes all timers. Used for testing.
+func clearScriptTimers() {
+	clear(scriptTimers)
+	clear(restoredRoomTimers)
+}
+
+func (o scriptOwner) exists() bool {
+	switch o.kind {
+	case ownerRoom: // Rooms never despawn, and checking would load unloaded rooms back into memory
+		return true
+	case ownerMob:
+		return mobs.GetInstance(o.mobInstanceId) != nil
+	case ownerItem:
+		user := users.GetByUserId(o.userId)
+		if user == nil {
+			return false
+		}
+		for _, itm := range append(user.Character.GetAllBackpackItems(), user.Character.GetAllWornItems()...) {
+			if itm.Equals(o.item) {
+				return true
+			}
+		}
+		return false
+	case ownerZone:
+		return true
+	}
+	return false
+}
+
+// Calls the timer function on the owner's script.
+// The owner is passed after the timer arguments:
+// rooms get (room), mobs get (mob, room), items get (user, item, room), zones get (zoneRoom)
+func tryTimerFunction(t *scriptTimer) (handled bool, err error) {
+
+	var vmw *VMWrapper
+	var discardVM func()
+	ownerArgs := []any{}
+
+	defer recoverScriptPanic(&err, t.function, t.owner.userId, t.owner.mobInstanceId, t.owner.roomId, func() {
+		if discardVM != nil {
+			discardVM()
+		}
+	})
+
+	pushScriptOwner(t.owner)
+	defer popScriptOwner()
+
+	// Disabled scripts keep their timers, but they don't do anything
+	if IsScriptDisabled(t.namespace) {
+		return false, errNoScript
+	}
+
+	switch t.owner.kind {
+	case ownerRoom:
+
+		discardVM = func() { roomScriptPanicked(t.owner.roomId) }
+		if vmw, err = getRoomVM(t.owner.roomId); err != nil {
+			return false, err
+		}
+		ownerArgs = append(ownerArgs, GetRoom(t.owner.roomId))
+
+	case ownerMob:
+
+		sMob := GetActor(0, t.owner.mobInstanceId)
+		if sMob == nil {
+			return false, errors.New("mob not found")
+		}
+		discardVM = func() { delete(mobVMCache, mobScriptId(sMob)) }
+		if vmw, err = getMobVM(sMob); err != nil {
+			return false, err
+		}
+		ownerArgs = append(ownerArgs, sMob, GetRoom(sMob.GetRoomId()))
+
+	case ownerItem:
+
+		sUser := GetActor(t.owner.userId, 0)
+		if sUser == nil {
+			return false, errors.New("user not found")
+		}
+		sItem := GetItem(t.owner.item)
+		discardVM = func() { delete(itemVMCache, fmt.Sprintf(`%d`, t.owner.item.ItemId)) }
+		if vmw, err = getItemVM(sItem); err != nil {
+			return false, err
+		}
+		ownerArgs = append(ownerArgs, sUser, sItem, GetRoom(sUser.GetRoomId()))
+
+		// Save any changes made to the item
+		defer func() {
+			if sUser.characterRecord.UpdateItem(t.owner.item, *sItem.itemRecord) {
+				t.owner.item = *sItem.itemRecord
+			}
+		}()
+
+	case ownerZone:
+
+		discardVM = func() { delete(zoneVMCache, t.owner.zone) }
+		if vmw, err = getZoneVM(t.owner.zone); err != nil {
+			return false, err
+		}
+		if t.owner.zone != `` {
+			if rootRoomId, err := rooms.GetZoneRoot(t.owner.zone); err == nil {
+				ownerArgs = append(ownerArgs, GetRoom(rootRoomId))
+			}
+		}
+
+	default:
+		return false, errNoScript
+	}
+
+	timerFunc, ok := vmw.GetFunction(t.function)
+	if !ok {
+		return false, fmt.Errorf("%s(): function not found", t.function)
+	}
+
+	// Set forced ansi tag wrappers
+	userTextWrap.Set(`script-text`, ``, ``)
+	roomTextWrap.Set(`script-text`, ``, ``)
+
+	jsArgs := make([]goja.Value, 0, len(t.args)+len(ownerArgs))
+	for _, arg := range t.args {
+		jsArgs = append(jsArgs, vmw.VM.ToValue(arg))
+	}
+	for _, arg := range ownerArgs {
+		jsArgs = append(jsArgs, vmw.VM.ToValue(arg))
+	}
+
+	tmr := time.AfterFunc(scriptRoomTimeout, func() {
+		vmw.VM.Interrupt(errTimeout)
+	})
+
+	res, err := timerFunc(goja.Undefined(), jsArgs...)
+
+	vmw.VM.ClearInterrupt()
+	tmr.Stop()
+
+	userTextWrap.Reset()
+	roomTextWrap.Reset()
+
+	if err != nil {
+
+		// Wrap the error
+		finalErr := fmt.Errorf("%s(): %w", t.function, err)
+
+		if _, ok := finalErr.(*goja.Exception); ok {
+			slog.Error("JSVM", "exception", finalErr)
+			return false, finalErr
+		} else if errors.Is(finalErr, errTimeout) {
+			slog.Error("JSVM", "interrupted", finalErr)
+			return false, finalErr
+		}
+
+		slog.Error("JSVM", "error", finalErr)
+		return false, finalErr
+	}
+
+	if boolVal, ok := res.Export().(bool); ok {
+		return boolVal, nil
+	}
+
+	return false, nil
+}
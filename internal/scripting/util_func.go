@@ -30,6 +30,10 @@ func setUtilFunctions(vm *goja.Runtime) {
 	vm.Set(`UtilApplyColorPattern`, UtilApplyColorPattern)
 	vm.Set(`UtilGetConfig`, UtilGetConfig)
 	vm.Set(`ColorWrap`, ColorWrap)
+	vm.Set(`SetTimeout`, SetTimeout)
+	vm.Set(`SetInterval`, SetInterval)
+	vm.Set(`ClearTimer`, ClearTimer)
+	vm.Set(`PersistTimer`, PersistTimer)
 }
 
 // ////////////////////////////////////////////////////////
@@ -37,6 +41,7 @@ func setUtilFunctions(vm *goja.Runtime) {
 // # These functions get exported to the scripting engine
 //
 // ////////////////////////////////////////////////////////
+
 func UtilGetRoundNumber() uint64 {
 	return util.GetRoundCount()
 }
@@ -161,3 +166,22 @@ func ColorWrap(txt string, colorClass ...string) string {
 
 	return txt
 }
+
+func SetTimeout(rounds int, fnName string, args ...any) int {
+	return addScriptTimer(rounds, 0, fnName, args)
+}
+
+func SetInterval(rounds int, fnName string, args ...any) int {
+	if rounds < 1 {
+		rounds = 1
+	}
+	return addScriptTimer(rounds, rounds, fnName, args)
+}
+
+func ClearTimer(timerId int) bool {
+	return clearScriptTimer(timerId)
+}
+
+func PersistTimer(timerId int) bool {
+	return persistScriptTimer(timerId)
+}
@@ -1,6 +1,8 @@
 package scripting
 
 import (
+	"time"
+
 	"github.com/dop251/goja"
 )
 
@@ -9,6 +11,7 @@ type VMWrapper struct {
 	callableCache map[string]goja.Callable
 	cacheSize     int
 	maxCacheSize  int
+	namespace     string // Used to keep data saved by this script separate from other scripts
 }
 
 func newVMWrapper(vm *goja.Runtime, cacheSize int) *VMWrapper {
@@ -17,6 +20,48 @@ func newVMWrapper(vm *goja.Runtime, cacheSize int) *VMWrapper {
 
 func (vmw *VMWrapper) GetFunction(name string) (goja.Callable, bool) {
 
+	if vmw.namespace == `` {
+		return vmw.getFunction(name)
+	}
+
+	// Disabled scripts act as if they have no functions
+	if IsScriptDisabled(vmw.namespace) {
+		return nil, false
+	}
+
+	fn, ok := vmw.getFunction(name)
+	if !ok {
+		return fn, ok
+	}
+
+	// Track which script is running while the function is called, and how it went
+	return func(this goja.Value, args ...goja.Value) (goja.Value, error) {
+		pushScriptNamespace(vmw.namespace)
+		defer popScriptNamespace()
+
+		finished := false
+		defer func() {
+			if !finished { // Panicking
+				recordScriptPanic(vmw.namespace, name)
+			}
+		}()
+
+		stopHeapGuard := startHeapGuard(vmw.VM)
+		defer stopHeapGuard()
+
+		start := time.Now()
+		res, err := fn(this, args...)
+
+		finished = true
+
+		recordScriptCall(vmw.namespace, name, time.Since(start), err)
+
+		return res, err
+	}, true
+}
+
+func (vmw *VMWrapper) getFunction(name string) (goja.Callable, bool) {
+
 	fn, ok := vmw.callableCache[name]
 
 	if ok {
@@ -0,0 +1,371 @@
+package scripting
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dop251/goja"
+	"github.com/volte6/gomud/internal/mobs"
+	"github.com/volte6/gomud/internal/users"
+)
+
+const (
+	// Every script under this folder is watched for changes
+	scriptWatchPath = `_datafiles`
+	// The same error from the same script is only reported to builders once in this window
+	scriptErrorReportWindow = time.Minute
+)
+
+var (
+	// Not watched: command scripts reload themselves, and web javascript isn't run by the server
+	scriptWatchSkipFolders = []string{`commands`, `html`}
+
+	scriptFileTimes   = map[string]time.Time{} // script file => modified time when last checked
+	scriptWatchSeeded = false
+
+	lastReportedScriptErrors = map[string]time.Time{} // file or namespace + error => when it was reported
+)
+
+// Checks every script under _datafiles for changes since the last check.
+// A changed script is compiled first, and only replaces the running script if it compiles and loads.
+// Errors are reported to admins and builders in the affected rooms.
+// The first call only remembers the scripts that exist.
+// Returns how many changed scripts were reloaded, and how many failed.
+func ReloadChangedScripts() (reloadedCt int, failedCt int) {
+
+	changedFiles := findChangedScripts()
+
+	if !scriptWatchSeeded {
+		scriptWatchSeeded = true
+		return 0, 0
+	}
+
+	for _, filePath := range changedFiles {
+		if err := reloadScriptFile(filePath); err != nil {
+			failedCt++
+		} else {
+			reloadedCt++
+		}
+	}
+
+	return reloadedCt, failedCt
+}
+
+// Returns scripts that are new, changed or removed since the last call
+func findChangedScripts() []string {
+
+	changedFiles := []string{}
+	seenFiles := map[string]struct{}{}
+
+	skipFolders := map[string]struct{}{}
+	for _, folder := range scriptWatchSkipFolders {
+		skipFolders[filepath.Join(scriptWatchPath, folder)] = struct{}{}
+	}
+
+	filepath.WalkDir(scriptWatchPath, func(filePath string, d fs.DirEntry, err error) error {
+
+		if err != nil {
+			return nil
+		}
+
+		if d.IsDir() {
+			if _, ok := skipFolders[filePath]; ok {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if !strings.HasSuffix(filePath, `.js`) || strings.HasSuffix(filePath, scriptTestSuffix) {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+
+		seenFiles[filePath] = struct{}{}
+
+		if lastTime, ok := scriptFileTimes[filePath]; !ok || !lastTime.Equal(info.ModTime()) {
+			scriptFileTimes[filePath] = info.ModTime()
+			changedFiles = append(changedFiles, filePath)
+		}
+
+		return nil
+	})
+
+	for filePath := range scriptFileTimes {
+		if _, ok := seenFiles[filePath]; !ok {
+			delete(scriptFileTimes, filePath)
+			changedFiles = append(changedFiles, filePath)
+		}
+	}
+
+	sort.Strings(changedFiles)
+
+	return changedFiles
+}
+
+// Validates a changed script and swaps it in for any running copies
+func reloadScriptFile(filePath string) error {
+
+	roomIds := scriptFileRoomIds(filePath)
+
+	// A removed script only needs its VM's forgotten
+	if scriptBytes, err := os.ReadFile(filePath); err == nil {
+		if _, err := goja.Compile(filePath, string(scriptBytes), false); err != nil {
+			slog.Error("ReloadChangedScripts()", "file", filePath, "error", err)
+			reportScriptError(filePath, roomIds, err.Error())
+			return err
+		}
+	}
+
+	// Anything remembered as having no script may have one now
+	forgetMissingScriptVMs()
+
+	for _, namespace := range scriptFileNamespaces(filePath) {
+
+		restore := forgetScriptVM(namespace)
+
+		if err := loadScriptVM(namespace); err != nil && !errors.Is(err, errNoScript) {
+			restore()
+			slog.Error("ReloadChangedScripts()", "file", filePath, "namespace", namespace, "error", err)
+			reportScriptError(filePath, roomIds, err.Error())
+			return err
+		}
+	}
+
+	slog.Info("ReloadChangedScripts()", "file", filePath)
+	notifyScriptBuilders(roomIds, false, fmt.Sprintf(`Script reloaded: <ansi fg="yellow">%s</ansi>`, filePath))
+
+	return nil
+}
+
+// Finds the scripts that were loaded from a file
+func scriptFileNamespaces(filePath string) []string {
+
+	filePath = filepath.Clean(filePath)
+
+	namespaces := []string{}
+	for namespace, stats := range scriptStats {
+		if stats.File != `` && filepath.Clean(stats.File) == filePath {
+			namespaces = append(namespaces, namespace)
+		}
+	}
+	sort.Strings(namespaces)
+
+	return namespaces
+}
+
+// Removes the cached VM of a script so that it is loaded again the next time it is used.
+// Returns a function that puts the old VM back.
+func forgetScriptVM(namespace string) (restore func()) {
+
+	prefix, key, _ := strings.Cut(namespace, `-`)
+
+	switch prefix {
+	case `room`:
+		roomId, _ := strconv.Atoi(key)
+		// A fixed script gets another chance
+		delete(disabledRoomScripts, roomId)
+		delete(roomScriptPanics, roomId)
+		return forgetVM(roomVMCache, roomId)
+	case `world`:
+		return forgetVM(zoneVMCache, ``)
+	case `zone`:
+		return forgetVM(zoneVMCache, key)
+	case `mob`:
+		return forgetVM(mobVMCache, key)
+	case `item`, `spell`: // Spells share the item cache
+		return forgetVM(itemVMCache, key)
+	case `buff`:
+		buffId, _ := strconv.Atoi(key)
+		return forgetVM(buffVMCache, buffId)
+	}
+
+	return func() {}
+}
+
+func forgetVM[K comparable](cache map[K]*VMWrapper, key K) (restore func()) {
+
+	oldVM, ok := cache[key]
+	delete(cache, key)
+
+	return func() {
+		if ok {
+			cache[key] = oldVM
+		}
+	}
+}
+
+// Drops the cache entries that remember a script didn't exist
+func forgetMissingScriptVMs() {
+	for _, cache := range []map[string]*VMWrapper{mobVMCache, itemVMCache, zoneVMCache} {
+		for key, vmw := range cache {
+			if vmw == nil {
+				delete(cache, key)
+			}
+		}
+	}
+	for roomId, vmw := range roomVMCache {
+		if vmw == nil {
+			delete(roomVMCache, roomId)
+		}
+	}
+	for buffId, vmw := range buffVMCache {
+		if vmw == nil {
+			delete(buffVMCache, buffId)
+		}
+	}
+}
+
+// Loads room and zone scripts straight away, so a script that fails to load can be put back.
+// Other scripts need an item, mob or buff to load, and are loaded the next time they are used.
+func loadScriptVM(namespace string) error {
+
+	prefix, key, _ := strings.Cut(namespace, `-`)
+
+	switch prefix {
+	case `room`:
+		roomId, _ := strconv.Atoi(key)
+		_, err := getRoomVM(roomId)
+		return err
+	case `world`:
+		_, err := getZoneVM(``)
+		return err
+	case `zone`:
+		_, err := getZoneVM(key)
+		return err
+	}
+
+	return nil
+}
+
+// Works out which rooms a script file affects from where it lives:
+// _datafiles/rooms/[zone]/[roomId].js or _datafiles/mobs/[zone]/scripts/[mobId]-[name].js
+func scriptFileRoomIds(filePath string) []int {
+
+	relPath, err := filepath.Rel(scriptWatchPath, filePath)
+	if err != nil {
+		return nil
+	}
+	parts := strings.Split(filepath.ToSlash(relPath), `/`)
+	fileName := strings.TrimSuffix(parts[len(parts)-1], `.js`)
+
+	if len(parts) == 3 && parts[0] == `rooms` {
+		if roomId, err := strconv.Atoi(fileName); err == nil {
+			return []int{roomId}
+		}
+	}
+
+	if len(parts) == 4 && parts[0] == `mobs` && parts[2] == `scripts` {
+
+		mobIdStr, _, _ := strings.Cut(fileName, `-`)
+		mobId, err := strconv.Atoi(mobIdStr)
+		if err != nil {
+			return nil
+		}
+
+		roomIds := []int{}
+		for _, mobInstanceId := range mobs.GetAllMobInstanceIds() {
+			if mob := mobs.GetInstance(mobInstanceId); mob != nil && int(mob.MobId) == mobId {
+				roomIds = append(roomIds, mob.Character.RoomId)
+			}
+		}
+		return roomIds
+	}
+
+	return nil
+}
+
+// Works out which room a running script affects
+func currentScriptRoomId() int {
+
+	owner, ok := currentScriptOwner()
+	if !ok {
+		return 0
+	}
+
+	if owner.roomId > 0 {
+		return owner.roomId
+	}
+
+	if owner.userId > 0 {
+		if user := users.GetByUserId(owner.userId); user != nil {
+			return user.Character.RoomId
+		}
+	}
+
+	if owner.mobInstanceId > 0 {
+		if mob := mobs.GetInstance(owner.mobInstanceId); mob != nil {
+			return mob.Character.RoomId
+		}
+	}
+
+	return 0
+}
+
+// Tells admins and builders about a broken script, unless they were just told the same thing.
+// If no rooms are affected, every admin and builder online is told.
+func reportScriptError(source string, roomIds []int, errMsg string) {
+
+	key := source + `|` + errMsg
+	now := time.Now()
+
+	if lastTime, ok := lastReportedScriptErrors[key]; ok && now.Sub(lastTime) < scriptErrorReportWindow {
+		return
+	}
+	lastReportedScriptErrors[key] = now
+
+	for k, t := range lastReportedScriptErrors {
+		if now.Sub(t) >= scriptErrorReportWindow {
+			delete(lastReportedScriptErrors, k)
+		}
+	}
+
+	notifyScriptBuilders(roomIds, true, fmt.Sprintf(`<ansi fg="red-bold">SCRIPT ERROR</ansi> in <ansi fg="yellow">%s</ansi>: <ansi fg="red">%s</ansi>`, source, errMsg))
+}
+
+// Sends a message to admins and builders standing in any of the rooms.
+func notifyScriptBuilders(roomIds []int, everyoneIfNoRooms bool, msg string) {
+
+	if len(roomIds) == 0 && !everyoneIfNoRooms {
+		return
+	}
+
+	inRoom := map[int]struct{}{}
+	for _, roomId := range roomIds {
+		inRoom[roomId] = struct{}{}
+	}
+
+	for _, u := range users.GetAllActiveUsers() {
+
+		if u.Permission != users.PermissionAdmin && u.Permission != users.PermissionMod {
+			continue
+		}
+
+		if len(inRoom) > 0 {
+			if _, ok := inRoom[u.Character.RoomId]; !ok {
+				continue
+			}
+		}
+
+		u.SendText(msg)
+	}
+}
+
+// Names a running script by its file when known, otherwise by its namespace
+func scriptSource(namespace string) string {
+	if stats, ok := scriptStats[namespace]; ok && stats.File != `` {
+		return stats.File
+	}
+	return namespace
+}
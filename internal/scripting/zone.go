@@ -0,0 +1,222 @@
+package scripting
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/dop251/goja"
+	"github.com/volte6/gomud/internal/configs"
+	"github.com/volte6/gomud/internal/rooms"
+)
+
+var (
+	// zone name => VM. The world script is stored under an empty zone name.
+	zoneVMCache = make(map[string]*VMWrapper)
+)
+
+func ClearZoneVMs() {
+	clear(zoneVMCache)
+}
+
+// Returns true if a zone has a working script
+func HasZoneScript(zone string) bool {
+	_, err := getZoneVM(zone)
+	return err == nil
+}
+
+// Calls a function in the global world script.
+// Returns true if the function returned true.
+func TryWorldScriptEvent(eventName string, args ...any) (handled bool, err error) {
+	return TryZoneScriptEvent(eventName, ``, args...)
+}
+
+// Calls a function in a zone script.
+// An empty zone name calls the function in the global world script instead.
+// Returns true if the function returned true.
+func TryZoneScriptEvent(eventName string, zone string, args ...any) (handled bool, err error) {
+
+	defer recoverScriptPanic(&err, eventName, 0, 0, 0, func() { delete(zoneVMCache, zone) })
+
+	pushScriptOwner(scriptOwner{kind: ownerZone, zone: zone})
+	defer popScriptOwner()
+
+	vmw, err := getZoneVM(zone)
+	if err != nil {
+		return false, err
+	}
+
+	timestart := time.Now()
+	defer func() {
+		slog.Debug("TryZoneScriptEvent()", "eventName", eventName, "zone", zone, "time", time.Since(timestart))
+	}()
+
+	if onEventFunc, ok := vmw.GetFunction(eventName); ok {
+
+		// Set forced ansi tag wrappers
+		userTextWrap.Set(`script-text`, ``, ``)
+		roomTextWrap.Set(`script-text`, ``, ``)
+
+		jsArgs := make([]goja.Value, len(args))
+		for i, arg := range args {
+			jsArgs[i] = vmw.VM.ToValue(arg)
+		}
+
+		tmr := time.AfterFunc(scriptRoomTimeout, func() {
+			vmw.VM.Interrupt(errTimeout)
+		})
+
+		res, err := onEventFunc(goja.Undefined(), jsArgs...)
+
+		vmw.VM.ClearInterrupt()
+		tmr.Stop()
+
+		userTextWrap.Reset()
+		roomTextWrap.Reset()
+
+		if err != nil {
+
+			// Wrap the error
+			finalErr := fmt.Errorf("%s(): %w", eventName, err)
+
+			if _, ok := finalErr.(*goja.Exception); ok {
+				slog.Error("JSVM", "exception", finalErr)
+				return false, finalErr
+			} else if errors.Is(finalErr, errTimeout) {
+				slog.Error("JSVM", "interrupted", finalErr)
+				return false, finalErr
+			}
+
+			slog.Error("JSVM", "error", finalErr)
+			return false, finalErr
+		}
+
+		if boolVal, ok := res.Export().(bool); ok {
+			return boolVal, nil
+		}
+	}
+
+	return false, nil
+}
+
+// Returns the path to a zone script, or the world script if zone is empty
+func getZoneScriptPath(zone string) string {
+	if zone == `` {
+		return string(configs.GetConfig().FileWorldScript)
+	}
+	return rooms.GetZoneScriptPath(zone)
+}
+
+func getZoneVM(zone string) (*VMWrapper, error) {
+
+	if vm, ok := zoneVMCache[zone]; ok {
+		if vm == nil {
+			return nil, errNoScript
+		}
+		return vm, nil
+	}
+
+	scriptPath := getZoneScriptPath(zone)
+
+	scriptBytes, err := os.ReadFile(scriptPath)
+	if err != nil || len(scriptBytes) == 0 {
+		zoneVMCache[zone] = nil
+		return nil, errNoScript
+	}
+
+	namespace := `world`
+	if zone != `` {
+		namespace = `zone-` + zone
+	}
+	pushScriptNamespace(namespace)
+	defer popScriptNamespace()
+	pushScriptOwner(scriptOwner{kind: ownerZone, zone: zone})
+	defer popScriptOwner()
+
+	vm := goja.New()
+	setAllScriptingFunctions(vm)
+
+	prg, err := goja.Compile(scriptPath, string(scriptBytes), false)
+	if err != nil {
+		// Don't keep trying to compile a broken script
+		zoneVMCache[zone] = nil
+		finalErr := fmt.Errorf("Compile: %w", err)
+		slog.Error("JSVM", "error", finalErr)
+		return nil, finalErr
+	}
+
+	//
+	// Run the program
+	//
+	tmr := time.AfterFunc(scriptLoadTimeout, func() {
+		vm.Interrupt(errTimeout)
+	})
+	if _, err = vm.RunProgram(prg); err != nil {
+
+		zoneVMCache[zone] = nil
+
+		// Wrap the error
+		finalErr := fmt.Errorf("RunProgram: %w", err)
+
+		if _, ok := finalErr.(*goja.Exception); ok {
+			slog.Error("JSVM", "exception", finalErr)
+			return nil, finalErr
+		} else if errors.Is(finalErr, errTimeout) {
+			slog.Error("JSVM", "interrupted", finalErr)
+			return nil, finalErr
+		}
+
+		slog.Error("JSVM", "error", finalErr)
+		return nil, finalErr
+	}
+	vm.ClearInterrupt()
+	tmr.Stop()
+
+	//
+	// Run onLoad() function
+	// Zone scripts receive the root room of the zone
+	//
+	tmr = time.AfterFunc(scriptLoadTimeout, func() {
+		vm.Interrupt(errTimeout)
+	})
+	if fn, ok := goja.AssertFunction(vm.Get(`onLoad`)); ok {
+
+		loadArgs := []goja.Value{}
+		if zone != `` {
+			if rootRoomId, err := rooms.GetZoneRoot(zone); err == nil {
+				loadArgs = append(loadArgs, vm.ToValue(GetRoom(rootRoomId)))
+			}
+		}
+
+		if _, err := fn(goja.Undefined(), loadArgs...); err != nil {
+
+			zoneVMCache[zone] = nil
+
+			// Wrap the error
+			finalErr := fmt.Errorf("onLoad: %w", err)
+
+			if _, ok := finalErr.(*goja.Exception); ok {
+				slog.Error("JSVM", "exception", finalErr)
+				return nil, finalErr
+			} else if errors.Is(finalErr, errTimeout) {
+				slog.Error("JSVM", "interrupted", finalErr)
+				return nil, finalErr
+			}
+
+			slog.Error("JSVM", "error", finalErr)
+			return nil, finalErr
+		}
+	}
+	vm.ClearInterrupt()
+	tmr.Stop()
+
+	vmw := newVMWrapper(vm, 0)
+	vmw.namespace = namespace
+	setScriptFile(namespace, scriptPath)
+
+	zoneVMCache[zone] = vmw
+
+	return vmw, nil
+}
@@ -174,6 +174,9 @@ func Build(rest string, user *users.UserRecord, room *rooms.Room) (bool, error)
 					return true, err
 				}
 
+				// Up and down look for a room on the level above or below
+				rGraph.SetLevel(deltaD.Dz)
+
 				map2D, cX, cY := rGraph.Generate2DMap(11, 11, user.Character.RoomId)
 
 				if len(map2D) < 1 {
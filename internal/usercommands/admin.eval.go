@@ -0,0 +1,152 @@
+package usercommands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/volte6/gomud/internal/audit"
+	"github.com/volte6/gomud/internal/configs"
+	"github.com/volte6/gomud/internal/rooms"
+	"github.com/volte6/gomud/internal/scripting"
+	"github.com/volte6/gomud/internal/templates"
+	"github.com/volte6/gomud/internal/users"
+)
+
+const (
+	// Prefix that targets a mob script instead of the room script, such as "mob:guard"
+	evalMobPrefix = `mob:`
+)
+
+func Eval(rest string, user *users.UserRecord, room *rooms.Room) (bool, error) {
+
+	if !configs.GetConfig().ScriptEvalEnabled {
+		user.SendText(`eval is disabled. It can be enabled with ScriptEvalEnabled in the config file.`)
+		return true, nil
+	}
+
+	if rest == `` {
+		infoOutput, _ := templates.Process("admincommands/help/command.eval", nil)
+		user.SendText(infoOutput)
+		return true, nil
+	}
+
+	mobInstanceId, code, err := parseEvalTarget(rest, room)
+	if err != nil {
+		user.SendText(err.Error())
+		return true, nil
+	}
+
+	runEval(user, room, mobInstanceId, code)
+
+	return true, nil
+}
+
+func Repl(rest string, user *users.UserRecord, room *rooms.Room) (bool, error) {
+
+	if !configs.GetConfig().ScriptEvalEnabled {
+		user.ClearPrompt()
+		user.SendText(`repl is disabled. It can be enabled with ScriptEvalEnabled in the config file.`)
+		return true, nil
+	}
+
+	if rest != `` && !strings.HasPrefix(rest, evalMobPrefix) {
+		infoOutput, _ := templates.Process("admincommands/help/command.repl", nil)
+		user.SendText(infoOutput)
+		return true, nil
+	}
+
+	// Look the mob up again for every line, in case it has died or wandered off
+	mobInstanceId, _, err := parseEvalTarget(rest, room)
+	if err != nil {
+		user.ClearPrompt()
+		user.SendText(err.Error())
+		return true, nil
+	}
+
+	cmdPrompt, isNew := user.StartPrompt(`repl`, rest)
+	if isNew {
+		user.SetTempData(`replBuffer`, nil)
+		user.SendText(`Starting a javascript session. Type <ansi fg="command">.exit</ansi> to leave. End a line with <ansi fg="command">\</ansi> to continue it on the next line.`)
+	}
+
+	question := cmdPrompt.Ask(`js>`, []string{})
+	if !question.Done {
+		return true, nil
+	}
+
+	line := question.Response
+	question.RejectResponse() // Keep asking until they leave
+
+	if line == `.exit` {
+		user.ClearPrompt()
+		user.SetTempData(`replBuffer`, nil)
+		user.SendText(`Javascript session ended.`)
+		return true, nil
+	}
+
+	buffer, _ := user.GetTempData(`replBuffer`).(string)
+
+	if strings.HasSuffix(line, `\`) {
+		user.SetTempData(`replBuffer`, buffer+strings.TrimSuffix(line, `\`)+"\n")
+		return true, nil
+	}
+	user.SetTempData(`replBuffer`, nil)
+
+	runEval(user, room, mobInstanceId, buffer+line)
+
+	return true, nil
+}
+
+// Splits an optional "mob:name" target off the front of the code.
+// Returns a mobInstanceId of zero when the room script is the target.
+func parseEvalTarget(rest string, room *rooms.Room) (mobInstanceId int, code string, err error) {
+
+	if !strings.HasPrefix(rest, evalMobPrefix) {
+		return 0, rest, nil
+	}
+
+	mobName, code, _ := strings.Cut(rest[len(evalMobPrefix):], ` `)
+
+	_, mobInstanceId = room.FindByName(mobName, rooms.FindAll)
+	if mobInstanceId < 1 {
+		return 0, ``, fmt.Errorf(`No mob named "%s" found.`, mobName)
+	}
+
+	return mobInstanceId, strings.TrimSpace(code), nil
+}
+
+// Runs the code, shows the result and records it in the audit trail
+func runEval(user *users.UserRecord, room *rooms.Room, mobInstanceId int, code string) {
+
+	if code == `` {
+		return
+	}
+
+	entry := audit.Entry{
+		UserId:   user.UserId,
+		Username: user.Username,
+		Action:   `eval`,
+		Details:  code,
+	}
+
+	var result string
+	var err error
+
+	if mobInstanceId > 0 {
+		entry.Target = fmt.Sprintf(`mob-%d`, mobInstanceId)
+		result, err = scripting.EvalMobScript(mobInstanceId, code)
+	} else {
+		entry.Target = fmt.Sprintf(`room-%d`, room.RoomId)
+		result, err = scripting.EvalRoomScript(room.RoomId, code)
+	}
+
+	if err != nil {
+		entry.Result = `error: ` + err.Error()
+		user.SendText(`<ansi fg="red">` + err.Error() + `</ansi>`)
+	} else {
+		entry.Result = result
+		user.SendText(`<ansi fg="black-bold">=</ansi> ` + result)
+	}
+
+	audit.Record(entry)
+}
@@ -0,0 +1,126 @@
+package usercommands
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/volte6/gomud/internal/mobs"
+	"github.com/volte6/gomud/internal/rooms"
+	"github.com/volte6/gomud/internal/scripting"
+	"github.com/volte6/gomud/internal/templates"
+	"github.com/volte6/gomud/internal/users"
+	"github.com/volte6/gomud/internal/util"
+)
+
+func PermData(rest string, user *users.UserRecord, room *rooms.Room) (bool, error) {
+
+	// args should look like one of the following:
+	// user <name> [clear [namespace]]
+	// mob <name> [clear [namespace]]
+	// item <name> [clear [namespace]]
+	args := util.SplitButRespectQuotes(rest)
+
+	if len(args) < 2 {
+		infoOutput, _ := templates.Process("admincommands/help/command.permdata", nil)
+		user.SendText(infoOutput)
+		return true, nil
+	}
+
+	targetType := strings.ToLower(args[0])
+	targetName := args[1]
+	args = args[2:]
+
+	var store scripting.PermDataStore
+	displayName := ``
+
+	switch targetType {
+	case `user`:
+
+		targetUser := users.GetByCharacterName(targetName)
+		if targetUser == nil {
+			user.SendText(fmt.Sprintf(`<ansi fg="username">%s</ansi> is not online.`, targetName))
+			return true, nil
+		}
+		store = scripting.UserPermData(targetUser)
+		displayName = fmt.Sprintf(`<ansi fg="username">%s</ansi>`, targetUser.Character.Name)
+
+	case `mob`:
+
+		_, mobInstanceId := room.FindByName(targetName, rooms.FindAll)
+		mob := mobs.GetInstance(mobInstanceId)
+		if mob == nil {
+			user.SendText(fmt.Sprintf(`No mob named "%s" found.`, targetName))
+			return true, nil
+		}
+		displayName = fmt.Sprintf(`<ansi fg="mobname">%s</ansi>`, mob.Character.Name)
+
+		if store = scripting.MobPermData(mob); store == nil {
+			user.SendText(fmt.Sprintf(`%s was not spawned by a room, so has nowhere to keep script data.`, displayName))
+			return true, nil
+		}
+
+	case `item`:
+
+		itm, found := user.Character.FindInBackpack(targetName)
+		if !found {
+			itm, found = room.FindOnFloor(targetName, false)
+		}
+		if !found {
+			user.SendText(fmt.Sprintf(`No item named "%s" found in your backpack or on the floor.`, targetName))
+			return true, nil
+		}
+		// Item copies share the same data map, so changes are seen by the original
+		store = &itm
+		displayName = itm.DisplayName()
+
+	default:
+		user.SendText(fmt.Sprintf(`Unknown target type "%s". Use <ansi fg="command">user</ansi>, <ansi fg="command">mob</ansi> or <ansi fg="command">item</ansi>.`, targetType))
+		return true, nil
+	}
+
+	if len(args) > 0 && strings.ToLower(args[0]) == `clear` {
+
+		namespace := ``
+		if len(args) > 1 {
+			namespace = args[1]
+		}
+
+		deleted := scripting.ClearPermData(store, namespace)
+
+		if namespace == `` {
+			user.SendText(fmt.Sprintf(`Cleared %d key(s) of script data from %s.`, deleted, displayName))
+		} else {
+			user.SendText(fmt.Sprintf(`Cleared %d key(s) of <ansi fg="yellow">%s</ansi> script data from %s.`, deleted, namespace, displayName))
+		}
+
+		return true, nil
+	}
+
+	headers := []string{"Namespace", "Key", "Value"}
+	rows := [][]string{}
+	formatting := []string{`<ansi fg="yellow">%s</ansi>`, `<ansi fg="white">%s</ansi>`, `<ansi fg="black-bold">%s</ansi>`}
+
+	allData := scripting.ListPermData(store)
+	for _, namespace := range scripting.GetPermDataNamespaces(store) {
+
+		nsData := allData[namespace]
+
+		keys := []string{}
+		for key := range nsData {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		nsLabel := fmt.Sprintf(`%s (%d bytes)`, namespace, scripting.PermDataSize(nsData))
+		for _, key := range keys {
+			rows = append(rows, []string{nsLabel, key, fmt.Sprintf(`%v`, nsData[key])})
+		}
+	}
+
+	tblData := templates.GetTable(`Script Data: `+displayName, headers, rows, formatting)
+	tplTxt, _ := templates.Process("tables/generic", tblData)
+	user.SendText(tplTxt)
+
+	return true, nil
+}
@@ -0,0 +1,89 @@
+package usercommands
+
+import (
+	"fmt"
+
+	"github.com/volte6/gomud/internal/mobs"
+	"github.com/volte6/gomud/internal/rooms"
+	"github.com/volte6/gomud/internal/templates"
+	"github.com/volte6/gomud/internal/users"
+)
+
+func Possess(rest string, user *users.UserRecord, room *rooms.Room) (bool, error) {
+
+	if rest == `` {
+		infoOutput, _ := templates.Process("admincommands/help/command.possess", nil)
+		user.SendText(infoOutput)
+		return true, nil
+	}
+
+	_, mobInstanceId := room.FindByName(rest, rooms.FindAll)
+	if mobInstanceId < 1 {
+		user.SendText(fmt.Sprintf(`No mob named "%s" found.`, rest))
+		return true, nil
+	}
+
+	mob := mobs.GetInstance(mobInstanceId)
+	if mob == nil {
+		user.SendText(fmt.Sprintf(`No mob named "%s" found.`, rest))
+		return true, nil
+	}
+
+	if mob.PossessedBy > 0 && mob.PossessedBy != user.UserId {
+		if otherUser := users.GetByUserId(mob.PossessedBy); otherUser != nil {
+			user.SendText(fmt.Sprintf(`<ansi fg="mobname">%s</ansi> is already possessed by <ansi fg="username">%s</ansi>.`, mob.Character.Name, otherUser.Character.Name))
+			return true, nil
+		}
+	}
+
+	// Only one mob at a time
+	ReleasePossessed(user)
+
+	mob.PossessedBy = user.UserId
+	user.SetTempData(`possessing`, mobInstanceId)
+
+	user.SendText(fmt.Sprintf(`You possess <ansi fg="mobname">%s</ansi>. Everything you type will be performed by them. Type <ansi fg="command">return</ansi> to return to your body.`, mob.Character.Name))
+
+	LookRoomSecretly(user, mob.Character.RoomId)
+
+	return true, nil
+}
+
+// Returns the mob instance an admin is possessing, if any
+func GetPossessed(user *users.UserRecord) *mobs.Mob {
+
+	mobInstanceId, ok := user.GetTempData(`possessing`).(int)
+	if !ok || mobInstanceId < 1 {
+		return nil
+	}
+
+	mob := mobs.GetInstance(mobInstanceId)
+	if mob == nil || mob.PossessedBy != user.UserId {
+		user.SetTempData(`possessing`, nil)
+		return nil
+	}
+
+	return mob
+}
+
+// Returns control of a possessed mob to the game.
+// Returns false if nothing was possessed.
+func ReleasePossessed(user *users.UserRecord) bool {
+
+	mob := GetPossessed(user)
+
+	user.SetTempData(`possessing`, nil)
+
+	if mob == nil {
+		return false
+	}
+
+	mob.PossessedBy = 0
+
+	return true
+}
+
+// Shows a room to a user without anyone else being told they are looking
+func LookRoomSecretly(user *users.UserRecord, roomId int) {
+	lookRoom(user, roomId, true)
+}
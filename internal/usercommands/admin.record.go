@@ -0,0 +1,234 @@
+package usercommands
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/volte6/gomud/internal/configs"
+	"github.com/volte6/gomud/internal/connections"
+	"github.com/volte6/gomud/internal/recordings"
+	"github.com/volte6/gomud/internal/rooms"
+	"github.com/volte6/gomud/internal/templates"
+	"github.com/volte6/gomud/internal/users"
+	"github.com/volte6/gomud/internal/util"
+)
+
+const (
+	// Idle time in a recording is never replayed for longer than this
+	recordingMaxPlaybackDelay = 2 * time.Second
+)
+
+var (
+	// userId => channel to close to stop playback
+	recordingPlayback     = map[int]chan struct{}{}
+	recordingPlaybackLock = sync.Mutex{}
+)
+
+func Record(rest string, user *users.UserRecord, room *rooms.Room) (bool, error) {
+
+	// args should look like one of the following:
+	// list [name]
+	// start <name>
+	// stop <name>
+	// play <name> [#|filename] [speed]
+	// play stop
+	args := util.SplitButRespectQuotes(strings.ToLower(rest))
+
+	if len(args) == 0 {
+		infoOutput, _ := templates.Process("admincommands/help/command.record", nil)
+		user.SendText(infoOutput)
+		return true, nil
+	}
+
+	recordCmd := args[0]
+	args = args[1:]
+
+	if recordCmd == `list` && len(args) == 0 {
+
+		headers := []string{"Name", "Username", "File", "Started By"}
+		rows := [][]string{}
+		formatting := []string{`<ansi fg="username">%s</ansi>`, `<ansi fg="yellow">%s</ansi>`, `<ansi fg="white">%s</ansi>`, `<ansi fg="black-bold">%s</ansi>`}
+
+		for _, u := range users.GetAllActiveUsers() {
+			if rec := connections.GetRecorder(u.ConnectionId()); rec != nil {
+				rows = append(rows, []string{u.Character.Name, u.Username, rec.FileName(), rec.StartedBy})
+			}
+		}
+
+		tblData := templates.GetTable(`Active Recordings`, headers, rows, formatting)
+		tplTxt, _ := templates.Process("tables/generic", tblData)
+		user.SendText(tplTxt)
+
+		return true, nil
+	}
+
+	if recordCmd == `play` && len(args) > 0 && args[0] == `stop` {
+
+		if stopRecordingPlayback(user.UserId) {
+			user.SendText(`Playback stopped.`)
+		} else {
+			user.SendText(`Nothing is playing.`)
+		}
+
+		return true, nil
+	}
+
+	if len(args) < 1 {
+		user.SendText(`Not enough arguments provided.`)
+		return true, nil
+	}
+
+	targetUser := users.GetByCharacterName(args[0])
+
+	if recordCmd == `start` || recordCmd == `stop` {
+
+		if targetUser == nil {
+			user.SendText(fmt.Sprintf(`<ansi fg="username">%s</ansi> is not online.`, args[0]))
+			return true, nil
+		}
+
+		if recordCmd == `stop` {
+			if !connections.StopRecording(targetUser.ConnectionId()) {
+				user.SendText(fmt.Sprintf(`<ansi fg="username">%s</ansi> is not being recorded.`, targetUser.Character.Name))
+				return true, nil
+			}
+
+			// Admins stopping a recording overrides the users own preference
+			targetUser.SetConfigOption(`recording`, false)
+
+			user.SendText(fmt.Sprintf(`Stopped recording <ansi fg="username">%s</ansi>.`, targetUser.Character.Name))
+			return true, nil
+		}
+
+		fileName, err := connections.StartRecording(targetUser.ConnectionId(), targetUser.Username, user.Username)
+		if err != nil {
+			user.SendText(fmt.Sprintf(`Could not start recording: %s`, err))
+			return true, nil
+		}
+
+		user.SendText(fmt.Sprintf(`Recording <ansi fg="username">%s</ansi> to <ansi fg="yellow">%s</ansi>.`, targetUser.Character.Name, fileName))
+		return true, nil
+	}
+
+	// Offline users can still have their recordings listed or played
+	username := args[0]
+	if targetUser != nil {
+		username = targetUser.Username
+	} else if _, foundUsername := users.CharacterNameSearch(args[0]); foundUsername != `` {
+		username = foundUsername
+	}
+
+	allRecordings := recordings.List(username)
+
+	if recordCmd == `list` {
+
+		headers := []string{"#", "File", "Size", "Last Modified"}
+		rows := [][]string{}
+		formatting := []string{`<ansi fg="yellow-bold">%s</ansi>`, `<ansi fg="white">%s</ansi>`, `<ansi fg="cyan">%s</ansi>`, `<ansi fg="black-bold">%s</ansi>`}
+
+		timeFormat := string(configs.GetConfig().TimeFormatShort)
+
+		for i, info := range allRecordings {
+			rows = append(rows, []string{strconv.Itoa(i + 1), info.Name, fmt.Sprintf(`%dkb`, info.Size/1024), info.Modified.Format(timeFormat)})
+		}
+
+		tblData := templates.GetTable(fmt.Sprintf(`Recordings for %s`, username), headers, rows, formatting)
+		tplTxt, _ := templates.Process("tables/generic", tblData)
+		user.SendText(tplTxt)
+
+		return true, nil
+	}
+
+	if recordCmd == `play` {
+
+		if len(allRecordings) == 0 {
+			user.SendText(fmt.Sprintf(`No recordings found for <ansi fg="username">%s</ansi>.`, username))
+			return true, nil
+		}
+
+		// Default to the most recent recording at normal speed
+		playPath := allRecordings[len(allRecordings)-1].Path
+		speed := 1.0
+
+		if len(args) > 1 {
+			if num, err := strconv.Atoi(args[1]); err == nil {
+				if num < 1 || num > len(allRecordings) {
+					user.SendText(fmt.Sprintf(`Recording #%d not found.`, num))
+					return true, nil
+				}
+				playPath = allRecordings[num-1].Path
+			} else {
+				var err error
+				if playPath, err = recordings.GetPath(username, args[1]); err != nil {
+					user.SendText(err.Error())
+					return true, nil
+				}
+			}
+		}
+
+		if len(args) > 2 {
+			if s, err := strconv.ParseFloat(strings.TrimSuffix(args[2], `x`), 64); err == nil && s > 0 {
+				speed = s
+			}
+		}
+
+		stopChan := make(chan struct{})
+
+		stopRecordingPlayback(user.UserId)
+		recordingPlaybackLock.Lock()
+		recordingPlayback[user.UserId] = stopChan
+		recordingPlaybackLock.Unlock()
+
+		user.SendText(fmt.Sprintf(`Playing <ansi fg="yellow">%s</ansi> at %.1fx speed. Type <ansi fg="command">record play stop</ansi> to stop.`, playPath, speed))
+
+		connId := user.ConnectionId()
+		userId := user.UserId
+
+		go func() {
+
+			err := recordings.Play(playPath, speed, recordingMaxPlaybackDelay, func(b []byte) {
+				// Frames were recorded after newline conversion, so undo it to avoid doubling up
+				connections.SendTo([]byte(strings.ReplaceAll(string(b), "\r\n", "\n")), connId)
+			}, stopChan)
+
+			recordingPlaybackLock.Lock()
+			if recordingPlayback[userId] == stopChan {
+				delete(recordingPlayback, userId)
+			}
+			recordingPlaybackLock.Unlock()
+
+			if u := users.GetByUserId(userId); u != nil {
+				if err != nil {
+					u.SendText(fmt.Sprintf("\n"+`Playback error: %s`, err))
+				} else {
+					u.SendText("\n" + `Playback finished.`)
+				}
+			}
+		}()
+
+		return true, nil
+	}
+
+	user.SendText(fmt.Sprintf(`Unknown record command: <ansi fg="red">%s</ansi>`, recordCmd))
+
+	return true, nil
+}
+
+// Stops any playback an admin is watching. Returns false if nothing was playing.
+func stopRecordingPlayback(userId int) bool {
+	recordingPlaybackLock.Lock()
+	defer recordingPlaybackLock.Unlock()
+
+	stopChan, ok := recordingPlayback[userId]
+	if !ok {
+		return false
+	}
+
+	close(stopChan)
+	delete(recordingPlayback, userId)
+
+	return true
+}
@@ -1,10 +1,12 @@
 package usercommands
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/volte6/gomud/internal/items"
 	"github.com/volte6/gomud/internal/rooms"
+	"github.com/volte6/gomud/internal/scripting"
 	"github.com/volte6/gomud/internal/templates"
 	"github.com/volte6/gomud/internal/users"
 )
@@ -21,6 +23,12 @@ func Reload(rest string, user *users.UserRecord, room *rooms.Room) (bool, error)
 	case `items`:
 		items.LoadDataFiles()
 		user.SendText(`Items reloaded.`)
+	case `commands`:
+		loadedCt := scripting.LoadCommandScripts()
+		user.SendText(fmt.Sprintf(`%d command script(s) loaded or changed. %d script command(s) available.`, loadedCt, len(scripting.GetScriptCommands())))
+	case `scripts`:
+		reloadedCt, failedCt := scripting.ReloadChangedScripts()
+		user.SendText(fmt.Sprintf(`%d changed script(s) reloaded. %d script(s) failed and were left unchanged.`, reloadedCt, failedCt))
 	default:
 		user.SendText(`Unknown reload command.`)
 	}
@@ -5,6 +5,8 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/volte6/gomud/internal/exit"
+	"github.com/volte6/gomud/internal/gametime"
 	"github.com/volte6/gomud/internal/mobs"
 	"github.com/volte6/gomud/internal/mutators"
 	"github.com/volte6/gomud/internal/parties"
@@ -160,6 +162,7 @@ func Room(rest string, user *users.UserRecord, room *rooms.Room) (bool, error) {
 				return handled, nil
 			}
 			delete(room.Exits, direction)
+			rooms.ClearPathCache(room.Zone)
 			return handled, nil
 		}
 
@@ -169,6 +172,7 @@ func Room(rest string, user *users.UserRecord, room *rooms.Room) (bool, error) {
 			if exitRename != `` {
 				delete(room.Exits, direction)
 				room.Exits[exitRename] = currentExit
+				rooms.ClearPathCache(room.Zone)
 
 				user.SendText(fmt.Sprintf("Exit %s renamed to %s.", direction, exitRename))
 				return true, nil
@@ -204,6 +208,74 @@ func Room(rest string, user *users.UserRecord, room *rooms.Room) (bool, error) {
 			user.SendText(fmt.Sprintf("Exit %s not found.", direction))
 		}
 
+	} else if len(args) >= 2 && roomCmd == "instancedexit" {
+
+		direction := args[1]
+		if exit, ok := room.Exits[direction]; ok {
+			if exit.Instanced {
+				exit.Instanced = false
+				room.Exits[direction] = exit
+				rooms.SaveRoom(*room)
+				user.SendText(fmt.Sprintf("Exit %s instancing REMOVED.", direction))
+			} else {
+				exit.Instanced = true
+				room.Exits[direction] = exit
+				rooms.SaveRoom(*room)
+				user.SendText(fmt.Sprintf("Exit %s instancing ADDED.", direction))
+			}
+		} else {
+			user.SendText(fmt.Sprintf("Exit %s not found.", direction))
+		}
+
+	} else if len(args) >= 2 && roomCmd == "door" {
+
+		// room door north "iron gate"
+		// room door north key vault-1
+		// room door north off
+		direction := args[1]
+		exitInfo, ok := room.Exits[direction]
+		if !ok {
+			user.SendText(fmt.Sprintf("Exit %s not found.", direction))
+			return true, nil
+		}
+
+		if len(args) == 2 {
+			if !exitInfo.HasDoor() {
+				user.SendText(fmt.Sprintf("Exit %s has no door.", direction))
+			} else {
+				user.SendText(fmt.Sprintf("Exit %s door: %s (open: %t, key: %s)", direction, exitInfo.Door.Name, exitInfo.Door.Open, exitInfo.LockId(room.RoomId, direction)))
+			}
+			return true, nil
+		}
+
+		if strings.ToLower(args[2]) == `off` {
+			exitInfo.Door = exit.Door{}
+			room.Exits[direction] = exitInfo
+			rooms.SaveRoom(*room)
+			user.SendText(fmt.Sprintf("Exit %s door REMOVED.", direction))
+			return true, nil
+		}
+
+		if strings.ToLower(args[2]) == `key` {
+			if !exitInfo.HasDoor() {
+				user.SendText(fmt.Sprintf("Exit %s has no door. Name the door first.", direction))
+				return true, nil
+			}
+			exitInfo.Door.Key = ``
+			if len(args) > 3 {
+				exitInfo.Door.Key = args[3]
+			}
+			room.Exits[direction] = exitInfo
+			rooms.SaveRoom(*room)
+			user.SendText(fmt.Sprintf("Exit %s door key set to: %s", direction, exitInfo.LockId(room.RoomId, direction)))
+			return true, nil
+		}
+
+		exitInfo.Door.Name = strings.Join(args[2:], ` `)
+		room.Exits[direction] = exitInfo
+		rooms.SaveRoom(*room)
+		user.SendText(fmt.Sprintf("Exit %s door set to: %s", direction, exitInfo.Door.Name))
+
 	} else if len(args) >= 2 && roomCmd == "set" {
 
 		propertyName := args[1]
@@ -291,8 +363,56 @@ func Room(rest string, user *users.UserRecord, room *rooms.Room) (bool, error) {
 				return handled, nil
 			}
 
+		} else if propertyName == "housing" {
+			// room set housing 5000 100 1 week
+			// room set housing off
+			housingArgs := strings.Fields(propertyValue)
+			if len(housingArgs) == 0 || strings.ToLower(housingArgs[0]) == `off` {
+				room.Housing = rooms.Housing{}
+				rooms.SaveRoom(*room)
+				user.SendText(`This room is no longer for sale.`)
+				return true, nil
+			}
+
+			housing := rooms.Housing{}
+			housing.Price, _ = strconv.Atoi(housingArgs[0])
+			if len(housingArgs) > 1 {
+				housing.Rent, _ = strconv.Atoi(housingArgs[1])
+			}
+			if len(housingArgs) > 2 {
+				housing.RentPeriod = strings.Join(housingArgs[2:], ` `)
+			}
+			housing.Furniture = room.Housing.Furniture
+			housing.EvictAfter = room.Housing.EvictAfter
+
+			if housing.Price < 1 {
+				user.SendText(`The price has to be at least 1 gold.`)
+				return true, nil
+			}
+
+			if housing.RentPeriod != `` {
+				if err := gametime.ValidatePeriod(housing.RentPeriod, true); err != nil {
+					user.SendText(fmt.Sprintf(`Invalid rent period: %s`, err.Error()))
+					return true, nil
+				}
+			}
+
+			room.Housing = housing
+			rooms.SaveRoom(*room)
+			user.SendText(fmt.Sprintf(`This room is for sale as a house for %d gold, with %d gold rent every %s.`, housing.Price, housing.Rent, housing.GetRentPeriod()))
+			return true, nil
+
 		} else if propertyName == "biome" {
 			room.Biome = strings.ToLower(propertyValue)
+		} else if propertyName == "movecost" {
+			moveCost, err := strconv.Atoi(propertyValue)
+			if err != nil || moveCost < 0 || moveCost > rooms.MaxMoveCost {
+				user.SendText(fmt.Sprintf(`The move cost must be a number from 0 to %d. 0 uses the biome's cost.`, rooms.MaxMoveCost))
+				return true, nil
+			}
+			room.MoveCost = moveCost
+			rooms.SaveRoom(*room)
+			user.SendText(fmt.Sprintf(`Moving into this room now costs %d action points.`, room.GetMoveCost()))
 		} else {
 			user.SendText(
 				`Invalid property provided to <ansi fg="command">room set</ansi>.`,
@@ -314,13 +434,20 @@ func Room(rest string, user *users.UserRecord, room *rooms.Room) (bool, error) {
 				return true, nil
 			}
 
+			// Up and down look for a room on the level above or below
+			firstStep := 1
+			if deltaD.Dz != 0 {
+				rGraph.SetLevel(deltaD.Dz)
+				firstStep = 0
+			}
+
 			map2D, cX, cY := rGraph.Generate2DMap(61, 61, user.Character.RoomId)
 			if len(map2D) < 1 {
 				user.SendText("Error generating a 2d map")
 				return true, nil
 			}
 
-			for i := 1; i <= 30; i++ {
+			for i := firstStep; i <= 30; i++ {
 				dy := deltaD.Dy * i
 				dx := deltaD.Dx * i
 				if cY+dy < len(map2D) && cX+dx < len(map2D[0]) {
@@ -0,0 +1,168 @@
+package usercommands
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/volte6/gomud/internal/configs"
+	"github.com/volte6/gomud/internal/gametime"
+	"github.com/volte6/gomud/internal/rooms"
+	"github.com/volte6/gomud/internal/schedules"
+	"github.com/volte6/gomud/internal/templates"
+	"github.com/volte6/gomud/internal/users"
+	"github.com/volte6/gomud/internal/util"
+)
+
+func Schedule(rest string, user *users.UserRecord, room *rooms.Room) (bool, error) {
+
+	// args should look like one of the following:
+	// list
+	// add <scheduleId> "<when>" <action> <value>
+	// remove <scheduleId>
+	// run <scheduleId>
+	args := util.SplitButRespectQuotes(rest)
+
+	if len(args) == 0 {
+		infoOutput, _ := templates.Process("admincommands/help/command.schedule", schedules.GetGameTimePeriods())
+		user.SendText(infoOutput)
+		return true, nil
+	}
+
+	scheduleCmd := strings.ToLower(args[0])
+	args = args[1:]
+
+	if scheduleCmd == `list` {
+
+		headers := []string{"Id", "When", "Actions", "Last Run", "Status"}
+		rows := [][]string{}
+		formatting := []string{`<ansi fg="yellow-bold">%s</ansi>`, `<ansi fg="cyan-bold">%s</ansi>`, `<ansi fg="white">%s</ansi>`, `<ansi fg="black-bold">%s</ansi>`, `<ansi fg="red">%s</ansi>`}
+
+		timeFormat := string(configs.GetConfig().TimeFormatShort)
+
+		for _, s := range schedules.GetAll() {
+
+			lastRun := `never`
+			if !s.LastRunTime.IsZero() {
+				lastRun = s.LastRunTime.Format(timeFormat)
+			}
+
+			status := `enabled`
+			if s.Disabled {
+				status = `disabled`
+			}
+
+			rows = append(rows, []string{s.ScheduleId, s.When(), strconv.Itoa(len(s.Actions)), lastRun, status})
+		}
+
+		gd := gametime.GetDate()
+		moonPhase := gametime.MoonPhaseName(gametime.GetMoonPhase(``, gd))
+
+		tblData := templates.GetTable(fmt.Sprintf(`Schedules (%s, %s)`, gd.String(), moonPhase), headers, rows, formatting)
+		tplTxt, _ := templates.Process("tables/generic", tblData)
+		user.SendText(tplTxt)
+
+		return true, nil
+	}
+
+	if len(args) < 1 {
+		user.SendText(`Not enough arguments provided.`)
+		return true, nil
+	}
+
+	scheduleId := strings.ToLower(args[0])
+	args = args[1:]
+
+	if scheduleCmd == `remove` {
+
+		if err := schedules.Remove(scheduleId); err != nil {
+			user.SendText(err.Error())
+			return true, nil
+		}
+
+		user.SendText(fmt.Sprintf(`Schedule <ansi fg="yellow-bold">%s</ansi> removed.`, scheduleId))
+		return true, nil
+	}
+
+	if scheduleCmd == `run` {
+
+		if err := schedules.RunNow(scheduleId); err != nil {
+			user.SendText(err.Error())
+			return true, nil
+		}
+
+		user.SendText(fmt.Sprintf(`Schedule <ansi fg="yellow-bold">%s</ansi> has been run.`, scheduleId))
+		return true, nil
+	}
+
+	if scheduleCmd == `add` {
+
+		// add <scheduleId> "<when>" <action> <value>
+		if len(args) < 3 {
+			user.SendText(`Usage: <ansi fg="command">schedule add [id] "[when]" [action] [value]</ansi>`)
+			return true, nil
+		}
+
+		when := strings.TrimSpace(args[0])
+		actionType := strings.ToLower(args[1])
+		value := strings.Join(args[2:], ` `)
+
+		newSchedule := &schedules.Schedule{
+			ScheduleId: scheduleId,
+		}
+
+		if schedules.IsGameTimePeriod(strings.ToLower(when)) {
+			newSchedule.GameTime = strings.ToLower(when)
+		} else {
+			newSchedule.Cron = when
+		}
+
+		// Actions target the zone the admin is standing in, except for scripts which target the room
+		action := schedules.Action{}
+
+		switch actionType {
+		case `broadcast`:
+			action.Broadcast = value
+		case `message`:
+			action.Message = value
+			action.Zone = room.Zone
+		case `command`:
+			// command <mobId> <command text>
+			parts := strings.SplitN(value, ` `, 2)
+			mobId, _ := strconv.Atoi(parts[0])
+			if mobId < 1 || len(parts) < 2 {
+				user.SendText(`Usage: <ansi fg="command">schedule add [id] "[when]" command [mobId] [command]</ansi>`)
+				return true, nil
+			}
+			action.MobId = mobId
+			action.Command = parts[1]
+			action.Zone = room.Zone
+		case `mutatoradd`:
+			action.MutatorAdd = value
+			action.Zone = room.Zone
+		case `mutatorremove`:
+			action.MutatorRemove = value
+			action.Zone = room.Zone
+		case `scriptfunction`:
+			action.ScriptFunction = value
+			action.RoomId = room.RoomId
+		default:
+			user.SendText(fmt.Sprintf(`Unknown action type: <ansi fg="red">%s</ansi>`, actionType))
+			return true, nil
+		}
+
+		newSchedule.Actions = []schedules.Action{action}
+
+		if err := schedules.Add(newSchedule); err != nil {
+			user.SendText(err.Error())
+			return true, nil
+		}
+
+		user.SendText(fmt.Sprintf(`Schedule <ansi fg="yellow-bold">%s</ansi> added (%s).`, newSchedule.ScheduleId, newSchedule.When()))
+		return true, nil
+	}
+
+	user.SendText(fmt.Sprintf(`Unknown schedule command: <ansi fg="red">%s</ansi>`, scheduleCmd))
+
+	return true, nil
+}
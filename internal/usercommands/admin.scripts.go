@@ -0,0 +1,176 @@
+package usercommands
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/volte6/gomud/internal/rooms"
+	"github.com/volte6/gomud/internal/scripting"
+	"github.com/volte6/gomud/internal/templates"
+	"github.com/volte6/gomud/internal/users"
+	"github.com/volte6/gomud/internal/util"
+)
+
+const (
+	// How many scripts to show in the list
+	scriptsListSize = 15
+)
+
+func Scripts(rest string, user *users.UserRecord, room *rooms.Room) (bool, error) {
+
+	// args should look like one of the following:
+	// [total|avg|max|calls|errors]
+	// errors
+	// disable <script>
+	// enable <script>
+	// reset
+	args := util.SplitButRespectQuotes(rest)
+
+	scriptsCmd := `total`
+	if len(args) > 0 {
+		scriptsCmd = strings.ToLower(args[0])
+		args = args[1:]
+	}
+
+	switch scriptsCmd {
+
+	case `help`:
+
+		infoOutput, _ := templates.Process("admincommands/help/command.scripts", nil)
+		user.SendText(infoOutput)
+
+	case `errors`:
+
+		headers := []string{"Time", "Script", "Function", "Error"}
+		rows := [][]string{}
+		formatting := []string{`<ansi fg="black-bold">%s</ansi>`, `<ansi fg="yellow">%s</ansi>`, `<ansi fg="white">%s</ansi>`, `<ansi fg="red">%s</ansi>`}
+
+		scriptErrors := scripting.GetRecentScriptErrors()
+		for i := len(scriptErrors) - 1; i >= 0; i-- {
+			e := scriptErrors[i]
+			rows = append(rows, []string{e.Time.Format(`15:04:05`), e.Namespace, e.Function, e.Error})
+		}
+
+		tblData := templates.GetTable(`Recent Script Errors`, headers, rows, formatting)
+		tplTxt, _ := templates.Process("tables/generic", tblData)
+		user.SendText(tplTxt)
+
+	case `disable`, `enable`:
+
+		if len(args) < 1 {
+			user.SendText(fmt.Sprintf(`Which script? e.g. <ansi fg="command">scripts %s room-1</ansi>`, scriptsCmd))
+			return true, nil
+		}
+
+		namespace := args[0]
+
+		if scriptsCmd == `disable` {
+			if scripting.DisableScript(namespace) {
+				user.SendText(fmt.Sprintf(`Script <ansi fg="yellow">%s</ansi> is now <ansi fg="red">disabled</ansi>.`, namespace))
+			} else {
+				user.SendText(fmt.Sprintf(`Script <ansi fg="yellow">%s</ansi> is already disabled.`, namespace))
+			}
+			return true, nil
+		}
+
+		if scripting.EnableScript(namespace) {
+			user.SendText(fmt.Sprintf(`Script <ansi fg="yellow">%s</ansi> is now <ansi fg="green">enabled</ansi>.`, namespace))
+		} else {
+			user.SendText(fmt.Sprintf(`Script <ansi fg="yellow">%s</ansi> was not disabled.`, namespace))
+		}
+
+	case `reset`:
+
+		scripting.ResetScriptStats()
+		user.SendText(`Script stats and errors have been reset.`)
+
+	case `total`, `avg`, `max`, `calls`:
+
+		allStats := scripting.GetScriptStats()
+
+		sort.Slice(allStats, func(i, j int) bool {
+			switch scriptsCmd {
+			case `avg`:
+				return allStats[i].AvgTime() > allStats[j].AvgTime()
+			case `max`:
+				return allStats[i].MaxTime > allStats[j].MaxTime
+			case `calls`:
+				return allStats[i].Calls > allStats[j].Calls
+			}
+			return allStats[i].TotalTime > allStats[j].TotalTime
+		})
+
+		// Disabled namespaces aren't case sensitive, but the stats keep the case they were recorded with
+		disabled := map[string]bool{}
+		for _, namespace := range scripting.GetDisabledScripts() {
+			disabled[strings.ToLower(namespace)] = true
+		}
+
+		headers := []string{"Script", "File", "Calls", "Avg", "Max", "Total", "Errors", "Status"}
+		rows := [][]string{}
+		formatting := []string{
+			`<ansi fg="yellow">%s</ansi>`,
+			`<ansi fg="black-bold">%s</ansi>`,
+			`<ansi fg="white">%s</ansi>`,
+			`<ansi fg="white">%s</ansi>`,
+			`<ansi fg="white">%s</ansi>`,
+			`<ansi fg="white">%s</ansi>`,
+			`<ansi fg="red">%s</ansi>`,
+			`<ansi fg="cyan">%s</ansi>`,
+		}
+
+		for i, stats := range allStats {
+			if i >= scriptsListSize {
+				break
+			}
+
+			status := `ok`
+			if disabled[strings.ToLower(stats.Namespace)] {
+				status = `disabled`
+			}
+
+			rows = append(rows, []string{
+				stats.Namespace,
+				stats.File,
+				strconv.FormatUint(stats.Calls, 10),
+				formatScriptDuration(stats.AvgTime()),
+				formatScriptDuration(stats.MaxTime),
+				formatScriptDuration(stats.TotalTime),
+				strconv.FormatUint(stats.Errors(), 10),
+				status,
+			})
+		}
+
+		// Disabled scripts may not have been called yet, but should still be shown
+		for namespace := range disabled {
+			found := false
+			for _, stats := range allStats {
+				if stats.Namespace == namespace {
+					found = true
+					break
+				}
+			}
+			if !found {
+				rows = append(rows, []string{namespace, ``, `0`, `-`, `-`, `-`, `0`, `disabled`})
+			}
+		}
+
+		tblData := templates.GetTable(fmt.Sprintf(`Heaviest Scripts (by %s)`, scriptsCmd), headers, rows, formatting)
+		tplTxt, _ := templates.Process("tables/generic", tblData)
+		user.SendText(tplTxt)
+
+		user.SendText(`Type <ansi fg="command">help scripts</ansi> for more options.`)
+
+	default:
+		user.SendText(fmt.Sprintf(`Unknown option "%s". Type <ansi fg="command">help scripts</ansi> for more options.`, scriptsCmd))
+	}
+
+	return true, nil
+}
+
+func formatScriptDuration(d time.Duration) string {
+	return d.Round(time.Microsecond).String()
+}
@@ -4,10 +4,12 @@ import (
 	"fmt"
 	"slices"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/volte6/gomud/internal/configs"
+	"github.com/volte6/gomud/internal/events"
 	"github.com/volte6/gomud/internal/gametime"
 	"github.com/volte6/gomud/internal/rooms"
 	"github.com/volte6/gomud/internal/templates"
@@ -107,6 +109,35 @@ func Server(rest string, user *users.UserRecord, room *rooms.Room) (bool, error)
 		templates.SetAnsiFlag(templates.AnsiTagsDefault)
 	}
 
+	if rest == "events" {
+
+		headers := []string{"Handle", "Due In", "Type", "Details"}
+		rows := [][]string{}
+		formatting := []string{`<ansi fg="black-bold">%s</ansi>`, `<ansi fg="cyan-bold">%s</ansi>`, `<ansi fg="yellow-bold">%s</ansi>`, `<ansi fg="white">%s</ansi>`}
+
+		currentTurn := events.CurrentTurn()
+		for _, scheduled := range events.GetScheduled() {
+
+			dueIn := uint64(0)
+			if scheduled.DueTurn > currentTurn {
+				dueIn = scheduled.DueTurn - currentTurn
+			}
+
+			rows = append(rows, []string{
+				strconv.FormatUint(uint64(scheduled.Handle), 10),
+				fmt.Sprintf(`%d turns`, dueIn),
+				scheduled.Event.Type(),
+				fmt.Sprintf(`%+v`, scheduled.Event),
+			})
+		}
+
+		tblData := templates.GetTable(`Scheduled Events`, headers, rows, formatting)
+		tplTxt, _ := templates.Process("tables/generic", tblData)
+		user.SendText(tplTxt)
+
+		return true, nil
+	}
+
 	if rest == "stats" || rest == "info" {
 
 		//
@@ -0,0 +1,110 @@
+package usercommands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/volte6/gomud/internal/configs"
+	"github.com/volte6/gomud/internal/connections"
+	"github.com/volte6/gomud/internal/rooms"
+	"github.com/volte6/gomud/internal/templates"
+	"github.com/volte6/gomud/internal/users"
+)
+
+func Snoop(rest string, user *users.UserRecord, room *rooms.Room) (bool, error) {
+
+	rest = strings.TrimSpace(rest)
+
+	// List who is being snooped
+	if rest == `` {
+
+		headers := []string{"Name", "Snooped By"}
+		rows := [][]string{}
+		formatting := []string{`<ansi fg="username">%s</ansi>`, `<ansi fg="username">%s</ansi>`}
+
+		for _, u := range users.GetAllActiveUsers() {
+
+			snooperNames := []string{}
+			for _, connId := range connections.GetSnoopers(u.ConnectionId()) {
+				if snooper := users.GetByConnectionId(connId); snooper != nil {
+					snooperNames = append(snooperNames, snooper.Character.Name)
+				}
+			}
+
+			if len(snooperNames) > 0 {
+				rows = append(rows, []string{u.Character.Name, strings.Join(snooperNames, `, `)})
+			}
+		}
+
+		tblData := templates.GetTable(`Active Snoops`, headers, rows, formatting)
+		tplTxt, _ := templates.Process("tables/generic", tblData)
+		user.SendText(tplTxt)
+
+		infoOutput, _ := templates.Process("admincommands/help/command.snoop", nil)
+		user.SendText(infoOutput)
+
+		return true, nil
+	}
+
+	// Stop snooping everyone
+	if strings.ToLower(rest) == `off` {
+
+		stopCt := 0
+		for _, u := range users.GetAllActiveUsers() {
+			if connections.RemoveSnooper(u.ConnectionId(), user.ConnectionId()) {
+				sendSnoopNotice(u, user, false)
+				stopCt++
+			}
+		}
+
+		user.SendText(fmt.Sprintf(`Stopped snooping %d player(s).`, stopCt))
+		return true, nil
+	}
+
+	targetUser := users.GetByCharacterName(rest)
+	if targetUser == nil {
+		user.SendText(fmt.Sprintf(`<ansi fg="username">%s</ansi> is not online.`, rest))
+		return true, nil
+	}
+
+	if targetUser.UserId == user.UserId {
+		user.SendText(`You can't snoop yourself.`)
+		return true, nil
+	}
+
+	// Snooping someone already being snooped toggles it off
+	if connections.RemoveSnooper(targetUser.ConnectionId(), user.ConnectionId()) {
+		sendSnoopNotice(targetUser, user, false)
+		user.SendText(fmt.Sprintf(`You stop snooping <ansi fg="username">%s</ansi>.`, targetUser.Character.Name))
+		return true, nil
+	}
+
+	if err := connections.AddSnooper(targetUser.ConnectionId(), user.ConnectionId()); err != nil {
+		user.SendText(fmt.Sprintf(`Could not snoop <ansi fg="username">%s</ansi>: %s`, targetUser.Character.Name, err))
+		return true, nil
+	}
+
+	sendSnoopNotice(targetUser, user, true)
+	user.SendText(fmt.Sprintf(`You are now snooping <ansi fg="username">%s</ansi>. Their output will be marked with <ansi fg="magenta">%%</ansi>.`, targetUser.Character.Name))
+
+	return true, nil
+}
+
+// Lets a player know they are being snooped, depending on the SnoopNotice config
+func sendSnoopNotice(targetUser *users.UserRecord, snooper *users.UserRecord, started bool) {
+
+	switch string(configs.GetConfig().SnoopNotice) {
+	case configs.SnoopNoticeAnonymous:
+		if started {
+			targetUser.SendText(`<ansi fg="magenta">An admin is now watching your session.</ansi>`)
+		} else {
+			targetUser.SendText(`<ansi fg="magenta">An admin is no longer watching your session.</ansi>`)
+		}
+	case configs.SnoopNoticeNamed:
+		if started {
+			targetUser.SendText(fmt.Sprintf(`<ansi fg="username">%s</ansi> <ansi fg="magenta">is now watching your session.</ansi>`, snooper.Character.Name))
+		} else {
+			targetUser.SendText(fmt.Sprintf(`<ansi fg="username">%s</ansi> <ansi fg="magenta">is no longer watching your session.</ansi>`, snooper.Character.Name))
+		}
+	}
+}
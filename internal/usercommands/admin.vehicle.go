@@ -0,0 +1,89 @@
+package usercommands
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/volte6/gomud/internal/gametime"
+	"github.com/volte6/gomud/internal/rooms"
+	"github.com/volte6/gomud/internal/templates"
+	"github.com/volte6/gomud/internal/users"
+	"github.com/volte6/gomud/internal/util"
+	"github.com/volte6/gomud/internal/vehicles"
+)
+
+func Vehicle(rest string, user *users.UserRecord, room *rooms.Room) (bool, error) {
+
+	// args should look like one of the following:
+	// list
+	// here
+	// <vehicleId>
+	args := util.SplitButRespectQuotes(rest)
+
+	if len(args) == 0 {
+		infoOutput, _ := templates.Process("admincommands/help/command.vehicle", nil)
+		user.SendText(infoOutput)
+		return true, nil
+	}
+
+	vehicleCmd := strings.ToLower(args[0])
+
+	if vehicleCmd == `list` {
+
+		headers := []string{"Id", "Name", "Rooms", "Stops", "Status", "Next Change"}
+		rows := [][]string{}
+		formatting := []string{`<ansi fg="yellow-bold">%s</ansi>`, `<ansi fg="white">%s</ansi>`, `<ansi fg="room-title">%s</ansi>`, `<ansi fg="white">%s</ansi>`, `<ansi fg="cyan-bold">%s</ansi>`, `<ansi fg="black-bold">%s</ansi>`}
+
+		for _, v := range vehicles.GetAll() {
+
+			roomIds := []string{}
+			for _, roomId := range v.RoomIds {
+				roomIds = append(roomIds, strconv.Itoa(roomId))
+			}
+
+			nextChange := `-`
+			if v.NextChangeRound() > 0 && !v.Disabled {
+				nextChange = gametime.GetDate(v.NextChangeRound()).String()
+			}
+
+			rows = append(rows, []string{v.VehicleId, v.Name, strings.Join(roomIds, `, `), strconv.Itoa(len(v.Stops)), v.Status(), nextChange})
+		}
+
+		tblData := templates.GetTable(fmt.Sprintf(`Vehicles (%s)`, gametime.GetDate().String()), headers, rows, formatting)
+		tplTxt, _ := templates.Process("tables/generic", tblData)
+		user.SendText(tplTxt)
+
+		return true, nil
+	}
+
+	var v *vehicles.Vehicle
+	if vehicleCmd == `here` {
+		v = vehicles.GetByRoomId(room.RoomId)
+	} else {
+		v = vehicles.Get(vehicleCmd)
+	}
+
+	if v == nil {
+		user.SendText(fmt.Sprintf(`Vehicle <ansi fg="red">%s</ansi> not found.`, vehicleCmd))
+		return true, nil
+	}
+
+	headers := []string{"Stop", "Room", "Title", "Dock", "Travel"}
+	rows := [][]string{}
+	formatting := []string{`<ansi fg="yellow-bold">%s</ansi>`, `<ansi fg="white">%s</ansi>`, `<ansi fg="room-title">%s</ansi>`, `<ansi fg="cyan-bold">%s</ansi>`, `<ansi fg="cyan-bold">%s</ansi>`}
+
+	for i, s := range v.Stops {
+		title := `(missing)`
+		if stopRoom := rooms.LoadRoom(s.RoomId); stopRoom != nil {
+			title = stopRoom.Title
+		}
+		rows = append(rows, []string{strconv.Itoa(i + 1), strconv.Itoa(s.RoomId), title, s.Dock, s.Travel})
+	}
+
+	tblData := templates.GetTable(fmt.Sprintf(`%s (%s): %s`, v.VehicleId, v.Name, v.Status()), headers, rows, formatting)
+	tplTxt, _ := templates.Process("tables/generic", tblData)
+	user.SendText(tplTxt)
+
+	return true, nil
+}
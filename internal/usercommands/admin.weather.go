@@ -0,0 +1,78 @@
+package usercommands
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/volte6/gomud/internal/rooms"
+	"github.com/volte6/gomud/internal/templates"
+	"github.com/volte6/gomud/internal/users"
+	"github.com/volte6/gomud/internal/weather"
+)
+
+func Weather(rest string, user *users.UserRecord, room *rooms.Room) (bool, error) {
+
+	// args should look like one of the following:
+	// list
+	// <weather> [all]
+	// auto [all]
+	args := strings.Fields(strings.ToLower(rest))
+
+	if len(args) == 0 {
+		infoOutput, _ := templates.Process("admincommands/help/command.weather", weather.GetAllNames())
+		user.SendText(infoOutput)
+		return true, nil
+	}
+
+	if args[0] == `list` {
+
+		zoneNames := rooms.GetAllZoneNames()
+		sort.Strings(zoneNames)
+
+		headers := []string{"Zone", "Biome", "Weather", "Forced"}
+		rows := [][]string{}
+		formatting := []string{`<ansi fg="yellow-bold">%s</ansi>`, `<ansi fg="white">%s</ansi>`, `<ansi fg="weather">%s</ansi>`, `<ansi fg="red">%s</ansi>`}
+
+		for _, zone := range zoneNames {
+			forced := ``
+			if weather.IsForced(zone) {
+				forced = `yes`
+			}
+			rows = append(rows, []string{zone, rooms.GetZoneBiome(zone), string(weather.Get(zone)), forced})
+		}
+
+		tblData := templates.GetTable(`Weather`, headers, rows, formatting)
+		tplTxt, _ := templates.Process("tables/generic", tblData)
+		user.SendText(tplTxt)
+
+		return true, nil
+	}
+
+	zones := []string{room.Zone}
+	if len(args) > 1 && args[1] == `all` {
+		zones = rooms.GetAllZoneNames()
+	}
+
+	if args[0] == `auto` {
+		for _, zone := range zones {
+			weather.Release(zone)
+		}
+		user.SendText(fmt.Sprintf(`The weather in %s will change naturally again.`, strings.Join(zones, `, `)))
+		return true, nil
+	}
+
+	w, ok := weather.Find(args[0])
+	if !ok {
+		user.SendText(fmt.Sprintf(`Unknown weather: <ansi fg="yellow">%s</ansi>. Try one of: %s`, args[0], strings.Join(weather.GetAllNames(), `, `)))
+		return true, nil
+	}
+
+	for _, zone := range zones {
+		weather.Set(zone, w, true)
+	}
+
+	user.SendText(fmt.Sprintf(`The weather in %s is now <ansi fg="weather">%s</ansi> until you type <ansi fg="command">weather auto</ansi>.`, strings.Join(zones, `, `), weather.GetInfo(w).Name()))
+
+	return true, nil
+}
@@ -2,9 +2,12 @@ package usercommands
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 
+	"github.com/volte6/gomud/internal/configs"
 	"github.com/volte6/gomud/internal/rooms"
 	"github.com/volte6/gomud/internal/templates"
 	"github.com/volte6/gomud/internal/users"
@@ -49,11 +52,120 @@ func Zone(rest string, user *users.UserRecord, room *rooms.Room) (bool, error) {
 			user.SendText(fmt.Sprintf(`  <ansi fg="yellow-bold">Mob AutoScale:</ansi>    <ansi fg="red">%d</ansi> - <ansi fg="red">%d</ansi>`, zoneConfig.MobAutoScale.Minimum, zoneConfig.MobAutoScale.Maximum))
 		}
 
+		if zoneConfig.Reset.Interval == `` {
+			user.SendText(`  <ansi fg="yellow-bold">Reset:</ansi>            <ansi fg="red">[disabled]</ansi>`)
+		} else {
+			resetCondition := zoneConfig.Reset.Condition
+			if resetCondition == `` {
+				resetCondition = rooms.ResetWhenEmpty
+			}
+			user.SendText(fmt.Sprintf(`  <ansi fg="yellow-bold">Reset:</ansi>            every <ansi fg="red">%s</ansi> (<ansi fg="red">%s</ansi>)`, zoneConfig.Reset.Interval, resetCondition))
+			if lastReset := rooms.GetZoneLastReset(room.Zone); lastReset > 0 {
+				user.SendText(fmt.Sprintf(`  <ansi fg="yellow-bold">Last Reset:</ansi>       <ansi fg="red">%d</ansi> rounds ago`, util.GetRoundCount()-lastReset))
+			}
+		}
+		for _, msg := range zoneConfig.Reset.Messages {
+			user.SendText(fmt.Sprintf(`  <ansi fg="yellow-bold">Reset Message:</ansi>    %s`, msg))
+		}
+
 		user.SendText(``)
 
 		return true, nil
 	}
 
+	// zone reset
+	if roomCmd == `reset` {
+
+		resetCt, err := rooms.ResetZone(room.Zone)
+		if err != nil {
+			user.SendText(fmt.Sprintf(`Couldn't reset <ansi fg="red">%s</ansi>: %s`, room.Zone, err.Error()))
+			return true, nil
+		}
+
+		user.SendText(fmt.Sprintf(`<ansi fg="yellow">%s</ansi> has been reset (%d loaded rooms).`, room.Zone, resetCt))
+		return true, nil
+	}
+
+	// zone instances
+	if roomCmd == `instances` {
+
+		allInstances := rooms.GetAllInstances()
+		if len(allInstances) == 0 {
+			user.SendText(`There are no instances right now.`)
+			return true, nil
+		}
+
+		roundNow := util.GetRoundCount()
+
+		user.SendText(``)
+		user.SendText(`<ansi fg="yellow-bold">Instances:</ansi>`)
+		for _, inst := range allInstances {
+
+			ownerName := strconv.Itoa(inst.OwnerUserId)
+			if owner := users.GetByUserId(inst.OwnerUserId); owner != nil {
+				ownerName = owner.Character.Name
+			}
+
+			roomIds := inst.GetRoomIds()
+			user.SendText(fmt.Sprintf(`  <ansi fg="red">#%d</ansi> <ansi fg="yellow">%s</ansi> owned by <ansi fg="username">%s</ansi> - rooms <ansi fg="red">%d</ansi>-<ansi fg="red">%d</ansi>, %d players inside, %d rounds old`,
+				inst.InstanceId, inst.Zone, ownerName, roomIds[0], roomIds[len(roomIds)-1], len(inst.GetPlayers()), roundNow-inst.CreatedRound))
+		}
+		user.SendText(``)
+
+		return true, nil
+	}
+
+	// zone export [dot/svg] [zone name]
+	if roomCmd == `export` {
+
+		format := `dot`
+		if len(args) > 0 {
+			format = strings.ToLower(args[0])
+			args = args[1:]
+		}
+
+		zoneName := room.Zone
+		if len(args) > 0 {
+			if zoneName = rooms.FindZoneName(strings.Join(args, ` `)); zoneName == `` {
+				user.SendText(fmt.Sprintf(`Couldn't find a zone named <ansi fg="red">%s</ansi>`, strings.Join(args, ` `)))
+				return true, nil
+			}
+		}
+
+		var exportData string
+		var err error
+
+		switch format {
+		case `dot`:
+			exportData, err = rooms.ExportZoneDOT(zoneName)
+		case `svg`:
+			exportData, err = rooms.ExportZoneSVG(zoneName)
+		default:
+			user.SendText(`Export format must be <ansi fg="command">dot</ansi> or <ansi fg="command">svg</ansi>.`)
+			return true, nil
+		}
+
+		if err != nil {
+			user.SendText(err.Error())
+			return true, nil
+		}
+
+		folder := string(configs.GetConfig().FolderZoneExports)
+		filePath := filepath.Join(folder, rooms.ZoneNameSanitize(zoneName)+`.`+format)
+
+		if err := os.MkdirAll(folder, 0755); err != nil {
+			user.SendText(err.Error())
+			return true, nil
+		}
+		if err := os.WriteFile(filePath, []byte(exportData), 0644); err != nil {
+			user.SendText(err.Error())
+			return true, nil
+		}
+
+		user.SendText(fmt.Sprintf(`Exported <ansi fg="red">%s</ansi> to <ansi fg="yellow">%s</ansi>`, zoneName, filePath))
+		return true, nil
+	}
+
 	// Everthing after this point requires additional args
 	if len(args) < 1 {
 		user.SendText(`Not enough arguments provided.`)
@@ -88,6 +200,59 @@ func Zone(rest string, user *users.UserRecord, room *rooms.Room) (bool, error) {
 			return true, nil
 		}
 
+		// zone set reset [interval] [empty/always]
+		if setWhat == `reset` {
+			if len(args) < 1 {
+				user.SendText(`Use <ansi fg="command">zone set reset off</ansi> to stop the zone resetting.`)
+				return true, nil
+			}
+
+			reset := zoneConfig.Reset
+
+			if strings.EqualFold(args[0], `off`) {
+				reset.Interval = ``
+			} else {
+				reset.Condition = rooms.ResetWhenEmpty
+				if lastArg := strings.ToLower(args[len(args)-1]); lastArg == rooms.ResetWhenEmpty || lastArg == rooms.ResetAlways {
+					reset.Condition = lastArg
+					args = args[:len(args)-1]
+				}
+				reset.Interval = strings.Trim(strings.Join(args, ` `), `"`)
+			}
+
+			if err := rooms.SetZoneReset(room.Zone, reset); err != nil {
+				user.SendText(err.Error())
+				return true, nil
+			}
+
+			user.SendText(`Done!`)
+			return true, nil
+		}
+
+		// zone set resetmessage [message/clear]
+		if setWhat == `resetmessage` {
+			if len(args) < 1 {
+				user.SendText(`Use <ansi fg="command">zone set resetmessage clear</ansi> to remove all reset messages.`)
+				return true, nil
+			}
+
+			reset := zoneConfig.Reset
+
+			if len(args) == 1 && strings.EqualFold(args[0], `clear`) {
+				reset.Messages = nil
+			} else {
+				reset.Messages = append(reset.Messages, strings.Trim(strings.Join(args, ` `), `"`))
+			}
+
+			if err := rooms.SetZoneReset(room.Zone, reset); err != nil {
+				user.SendText(err.Error())
+				return true, nil
+			}
+
+			user.SendText(`Done!`)
+			return true, nil
+		}
+
 	}
 
 	return true, nil
@@ -0,0 +1,42 @@
+package usercommands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/volte6/gomud/internal/rooms"
+	"github.com/volte6/gomud/internal/users"
+)
+
+func Close(rest string, user *users.UserRecord, room *rooms.Room) (bool, error) {
+
+	rest = strings.ToLower(strings.TrimSpace(rest))
+
+	if rest == `` {
+		user.SendText("Close what?")
+		return true, nil
+	}
+
+	exitName, exitInfo, found := room.FindDoor(rest)
+	if !found {
+		user.SendText("There is no door like that here.")
+		return true, nil
+	}
+
+	if !exitInfo.Door.Open {
+		user.SendText(fmt.Sprintf(`The <ansi fg="exit">%s</ansi> is already closed.`, exitInfo.Door.Name))
+		return true, nil
+	}
+
+	room.SetDoorOpen(exitName, false)
+
+	user.SendText(fmt.Sprintf(`You close the <ansi fg="exit">%s</ansi> to the <ansi fg="exit">%s</ansi>.`, exitInfo.Door.Name, exitName))
+	room.SendText(fmt.Sprintf(`<ansi fg="username">%s</ansi> closes the <ansi fg="exit">%s</ansi> to the <ansi fg="exit">%s</ansi>.`, user.Character.Name, exitInfo.Door.Name, exitName), user.UserId)
+
+	if otherRoom, otherExitName := room.GetDoorPair(exitName); otherRoom != nil {
+		otherExit, _ := otherRoom.GetExitInfo(otherExitName)
+		otherRoom.SendText(fmt.Sprintf(`The <ansi fg="exit">%s</ansi> to the <ansi fg="exit">%s</ansi> closes.`, otherExit.Door.Name, otherExitName))
+	}
+
+	return true, nil
+}
@@ -40,30 +40,32 @@ func Go(rest string, user *users.UserRecord, room *rooms.Room) (bool, error) {
 			return true, nil
 		}
 
-		actionCost := 10
-		encumbered := false
-		if len(user.Character.Items) > user.Character.CarryCapacity() {
-			actionCost = 50
-			encumbered = true
+		// Rougher terrain is more tiring to move into
+		terrainCost := rooms.DefaultMoveCost
+		if goRoom := rooms.LoadRoom(goRoomId); goRoom != nil {
+			terrainCost = goRoom.GetMoveCost()
 		}
 
-		if !user.Character.DeductActionPoints(actionCost) {
+		if !user.Character.DeductActionPoints(user.Character.MoveCost(terrainCost)) {
 
-			if encumbered {
+			if user.Character.IsEncumbered() {
 				user.SendText("You're too encumbered to move (<ansi fg=\"command\">help encumbrance</ansi>)!")
 			} else {
-				user.SendText("You're too tired to move (slow down)!")
+				user.SendText("You're too tired to move (<ansi fg=\"command\">rest</ansi> or slow down)!")
 			}
 
 			return true, nil
 		}
 
+		// Getting up and moving is the end of any rest
+		user.Character.CancelBuffsWithFlag(buffs.Resting)
+
 		originRoomId := user.Character.RoomId
 
 		exitInfo, _ := room.GetExitInfo(exitName)
 		if exitInfo.Lock.IsLocked() {
 
-			lockId := fmt.Sprintf(`%d-%s`, room.RoomId, exitName)
+			lockId := exitInfo.LockId(room.RoomId, exitName)
 
 			hasKey, hasSequence := user.Character.HasKey(lockId, int(room.Exits[exitName].Lock.Difficulty))
 
@@ -126,6 +128,20 @@ func Go(rest string, user *users.UserRecord, room *rooms.Room) (bool, error) {
 
 		}
 
+		if exitInfo.HasDoor() && !exitInfo.Door.Open {
+			user.SendText(fmt.Sprintf(`The <ansi fg="exit">%s</ansi> is closed. You'll need to <ansi fg="command">open %s</ansi> first.`, exitInfo.Door.Name, exitName))
+			return true, nil
+		}
+
+		// Instanced exits lead to the party's own copy of the zone
+		if exitInfo.Instanced {
+			instRoomId, err := rooms.EnterInstance(user.UserId, room.RoomId, goRoomId)
+			if err != nil {
+				return false, err
+			}
+			goRoomId = instRoomId
+		}
+
 		// Load current room details
 		destRoom := rooms.LoadRoom(goRoomId)
 		if destRoom == nil {
@@ -155,6 +171,11 @@ func Go(rest string, user *users.UserRecord, room *rooms.Room) (bool, error) {
 
 			scripting.TryRoomScriptEvent(`onExit`, user.UserId, originRoomId)
 
+			// Running out of action points leaves them unable to move on for a moment
+			if user.Character.ActionPoints < rooms.DefaultMoveCost {
+				user.AddBuff(43) // Exhausted
+			}
+
 			c := configs.GetConfig()
 
 			// Tell the player they are moving
@@ -9,6 +9,7 @@ import (
 	"github.com/volte6/gomud/internal/keywords"
 	"github.com/volte6/gomud/internal/races"
 	"github.com/volte6/gomud/internal/rooms"
+	"github.com/volte6/gomud/internal/scripting"
 	"github.com/volte6/gomud/internal/spells"
 	"github.com/volte6/gomud/internal/templates"
 	"github.com/volte6/gomud/internal/users"
@@ -72,6 +73,29 @@ func Help(rest string, user *users.UserRecord, room *rooms.Room) (bool, error) {
 
 		}
 
+		// Commands added by scripts
+		for _, scriptCmd := range scripting.GetScriptCommands() {
+
+			category := scriptCmd.Category
+			if category == `all` {
+				category = ``
+			}
+
+			missing := scriptCmd.Help == `` && !templates.Exists(`help/`+scriptCmd.Name)
+
+			if scriptCmd.AdminOnly {
+				if user.Permission == users.PermissionAdmin || user.HasAdminCommand(scriptCmd.Name) {
+					helpCommandList.Admin[category] = append(
+						helpCommandList.Admin[category],
+						helpCommand{Command: scriptCmd.Name, Type: "command-admin", Missing: missing},
+					)
+				}
+				continue
+			}
+
+			helpCommandList.Commands[category] = append(helpCommandList.Commands[category], helpCommand{Command: scriptCmd.Name, Type: `command`, Missing: missing})
+		}
+
 		helpTxt, err = templates.Process("help/help", helpCommandList)
 		if err != nil {
 			helpTxt = err.Error()
@@ -114,6 +138,22 @@ func Help(rest string, user *users.UserRecord, room *rooms.Room) (bool, error) {
 			}
 		}
 
+		// Commands added by scripts can bring their own help text
+		if !templates.Exists(`help/` + helpName) {
+			if scriptCmd, ok := scripting.GetScriptCommand(helpName); ok && scriptCmd.Help != `` {
+				if !scriptCmd.AdminOnly || user.Permission == users.PermissionAdmin || user.HasAdminCommand(scriptCmd.Name) {
+					user.SendText(fmt.Sprintf(`<ansi fg="black-bold">.:</ansi> <ansi fg="magenta">Help for </ansi><ansi fg="command">%s</ansi>`, scriptCmd.Name))
+					user.SendText(``)
+					user.SendText(scriptCmd.Help)
+					if len(scriptCmd.Aliases) > 0 {
+						user.SendText(``)
+						user.SendText(`<ansi fg="yellow">Aliases:</ansi> ` + strings.Join(scriptCmd.Aliases, `, `))
+					}
+					return true, nil
+				}
+			}
+		}
+
 		helpTxt, err = templates.Process("help/"+helpName, helpVars)
 		if err != nil {
 			user.SendText(fmt.Sprintf(`No help found for "%s"`, helpName))
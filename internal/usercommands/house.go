@@ -0,0 +1,330 @@
+package usercommands
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/volte6/gomud/internal/gametime"
+	"github.com/volte6/gomud/internal/rooms"
+	"github.com/volte6/gomud/internal/users"
+	"github.com/volte6/gomud/internal/util"
+)
+
+func House(rest string, user *users.UserRecord, room *rooms.Room) (bool, error) {
+
+	args := util.SplitButRespectQuotes(rest)
+
+	houseCmd := ``
+	if len(args) > 0 {
+		houseCmd = strings.ToLower(args[0])
+		args = args[1:]
+	}
+
+	// Most things can only be done from inside a house
+	h := rooms.GetHouse(room.HouseOwnerId())
+
+	switch houseCmd {
+
+	case ``, `info`:
+
+		if room.Housing.ForSale() {
+			housing := room.Housing
+			user.SendText(``)
+			user.SendText(`<ansi fg="table-title">This house is for sale:</ansi>`)
+			user.SendText(fmt.Sprintf(`  <ansi fg="yellow-bold">Price:</ansi>     <ansi fg="gold">%d gold</ansi> from your bank`, housing.Price))
+			if housing.Rent > 0 {
+				user.SendText(fmt.Sprintf(`  <ansi fg="yellow-bold">Rent:</ansi>      <ansi fg="gold">%d gold</ansi> every %s, from your bank`, housing.Rent, housing.GetRentPeriod()))
+				user.SendText(fmt.Sprintf(`  <ansi fg="yellow-bold">Eviction:</ansi>  after %d missed payments`, housing.GetEvictAfter()))
+			}
+			user.SendText(fmt.Sprintf(`  <ansi fg="yellow-bold">Furniture:</ansi> up to %d pieces`, housing.GetFurnitureLimit()))
+			user.SendText(``)
+		}
+
+		if h == nil {
+			h = rooms.GetHouse(user.UserId)
+		}
+
+		if h == nil {
+			if !room.Housing.ForSale() {
+				user.SendText(`You don't own a house. Look for one that is for sale.`)
+			} else {
+				user.SendText(`Type <ansi fg="command">house buy</ansi> to buy it.`)
+			}
+			return true, nil
+		}
+
+		showHouse(h, user)
+		return true, nil
+
+	case `buy`:
+
+		if !room.Housing.ForSale() {
+			user.SendText(`This place isn't for sale.`)
+			return true, nil
+		}
+
+		newHouse, err := rooms.BuyHouse(user, room.RoomId)
+		if err != nil {
+			user.SendText(fmt.Sprintf(`You can't buy this house: %s.`, err.Error()))
+			return true, nil
+		}
+
+		user.SendText(fmt.Sprintf(`You pay <ansi fg="gold">%d gold</ansi> and are handed the keys to your new house. Type <ansi fg="command">house enter</ansi> to go inside.`, room.Housing.Price))
+		room.SendText(fmt.Sprintf(`<ansi fg="username">%s</ansi> buys a house.`, user.Character.Name), user.UserId)
+
+		users.SaveUser(*user)
+		showHouse(newHouse, user)
+
+		return true, nil
+
+	case `sell`:
+
+		if rooms.GetHouse(user.UserId) == nil {
+			user.SendText(`You don't own a house.`)
+			return true, nil
+		}
+
+		if len(args) < 1 || strings.ToLower(args[0]) != `confirm` {
+			user.SendText(`Selling your house gets back half of what it cost. Your furniture, and everything in it, goes to your storage.`)
+			user.SendText(`Type <ansi fg="command">house sell confirm</ansi> if you're sure.`)
+			return true, nil
+		}
+
+		if _, err := rooms.SellHouse(user.UserId); err != nil {
+			user.SendText(fmt.Sprintf(`You can't sell your house: %s.`, err.Error()))
+		}
+
+		return true, nil
+
+	case `enter`:
+
+		var enterHouse *rooms.House
+
+		if len(args) > 0 {
+			enterHouse = rooms.FindHouse(strings.Join(args, ` `))
+		} else {
+			for _, canEnter := range rooms.GetHousesFor(user.UserId) {
+				if canEnter.TemplateRoomId == room.RoomId {
+					enterHouse = canEnter
+					break
+				}
+			}
+		}
+
+		if enterHouse == nil || enterHouse.TemplateRoomId != room.RoomId {
+			user.SendText(`There is no house here you can enter.`)
+			return true, nil
+		}
+
+		if !enterHouse.CanEnter(user.UserId) && user.Permission != users.PermissionAdmin {
+			user.SendText(`You aren't welcome in that house.`)
+			return true, nil
+		}
+
+		if err := rooms.MoveToRoom(user.UserId, enterHouse.RoomId()); err != nil {
+			user.SendText(`You can't get in.`)
+			return true, nil
+		}
+
+		user.SendText(`You let yourself in.`)
+		room.SendText(fmt.Sprintf(`<ansi fg="username">%s</ansi> goes inside a house.`, user.Character.Name), user.UserId)
+
+		if houseRoom := rooms.LoadRoom(enterHouse.RoomId()); houseRoom != nil {
+			houseRoom.SendText(fmt.Sprintf(`<ansi fg="username">%s</ansi> comes in.`, user.Character.Name), user.UserId)
+			Look(``, user, houseRoom)
+		}
+
+		return true, nil
+	}
+
+	// Everything else is managing the house they're standing in
+	if h == nil {
+		user.SendText(`You need to be inside a house to do that.`)
+		return true, nil
+	}
+
+	if !h.CanManage(user.UserId) {
+		user.SendText(`Only the owners of this house can do that.`)
+		return true, nil
+	}
+
+	switch houseCmd {
+
+	case `title`:
+
+		title := strings.Join(args, ` `)
+		if title == `` {
+			user.SendText(`Set the title to what? Use <ansi fg="command">house title clear</ansi> to go back to the original.`)
+			return true, nil
+		}
+		if strings.ToLower(title) == `clear` {
+			title = ``
+		}
+
+		h.SetTitle(title)
+		user.SendText(`You change the name of the house.`)
+
+	case `describe`, `description`:
+
+		description := strings.Join(args, ` `)
+		if description == `` {
+			user.SendText(`Describe it how? Use <ansi fg="command">house describe clear</ansi> to go back to the original.`)
+			return true, nil
+		}
+		if strings.ToLower(description) == `clear` {
+			description = ``
+		}
+
+		h.SetDescription(strings.ReplaceAll(description, `\n`, "\n"))
+		user.SendText(`You redecorate the house.`)
+
+	case `furnish`:
+
+		itemName := strings.Join(args, ` `)
+		itm, found := user.Character.FindInBackpack(itemName)
+		if !found {
+			user.SendText(fmt.Sprintf(`You don't have a %s.`, itemName))
+			return true, nil
+		}
+
+		containerName, err := h.AddFurniture(itm)
+		if err != nil {
+			user.SendText(fmt.Sprintf(`You can't place that: %s.`, err.Error()))
+			return true, nil
+		}
+
+		user.Character.RemoveItem(itm)
+
+		user.SendText(fmt.Sprintf(`You place the <ansi fg="container">%s</ansi>.`, containerName))
+		room.SendText(fmt.Sprintf(`<ansi fg="username">%s</ansi> places the <ansi fg="container">%s</ansi>.`, user.Character.Name, containerName), user.UserId)
+
+	case `unfurnish`:
+
+		containerName := room.FindContainerByName(strings.Join(args, ` `))
+
+		itm, err := h.RemoveFurniture(containerName)
+		if err != nil {
+			user.SendText(fmt.Sprintf(`You can't take that: %s.`, err.Error()))
+			return true, nil
+		}
+
+		user.Character.StoreItem(itm)
+
+		user.SendText(fmt.Sprintf(`You pick up the <ansi fg="item">%s</ansi>.`, itm.DisplayName()))
+		room.SendText(fmt.Sprintf(`<ansi fg="username">%s</ansi> picks up the <ansi fg="item">%s</ansi>.`, user.Character.Name, itm.DisplayName()), user.UserId)
+
+	case `guest`, `guests`, `coowner`, `coowners`:
+
+		isCoOwner := strings.HasPrefix(houseCmd, `coowner`)
+
+		if len(args) > 0 {
+			args[0] = strings.ToLower(args[0])
+		}
+
+		if len(args) < 2 || (args[0] != `add` && args[0] != `remove`) {
+			showHouse(h, user)
+			return true, nil
+		}
+
+		// Only the owner picks co-owners
+		if isCoOwner && user.UserId != h.OwnerUserId {
+			user.SendText(`Only the owner can choose co-owners.`)
+			return true, nil
+		}
+
+		adding := args[0] == `add`
+		name := strings.Join(args[1:], ` `)
+
+		userId, characterName := findCharacter(name)
+		if userId == 0 {
+			user.SendText(fmt.Sprintf(`No one named %s could be found.`, name))
+			return true, nil
+		}
+
+		if userId == h.OwnerUserId {
+			user.SendText(`The owner can always come and go.`)
+			return true, nil
+		}
+
+		what := `guest`
+		if isCoOwner {
+			what = `co-owner`
+			h.SetCoOwner(userId, characterName, adding)
+		} else {
+			h.SetGuest(userId, characterName, adding)
+		}
+
+		if adding {
+			user.SendText(fmt.Sprintf(`<ansi fg="username">%s</ansi> is now a %s of the house.`, characterName, what))
+			if u := users.GetByUserId(userId); u != nil {
+				u.SendText(fmt.Sprintf(`<ansi fg="username">%s</ansi> made you a %s of their house. Type <ansi fg="command">house enter %s</ansi> where it was bought to go in.`, user.Character.Name, what, h.OwnerName))
+			}
+		} else {
+			user.SendText(fmt.Sprintf(`<ansi fg="username">%s</ansi> is no longer a %s of the house.`, characterName, what))
+		}
+
+	default:
+		user.SendText(`Try <ansi fg="command">help house</ansi> for more information about houses.`)
+	}
+
+	return true, nil
+}
+
+func showHouse(h *rooms.House, user *users.UserRecord) {
+
+	housing := h.GetHousing()
+
+	user.SendText(``)
+	user.SendText(fmt.Sprintf(`<ansi fg="table-title">House of <ansi fg="username">%s</ansi>:</ansi>`, h.OwnerName))
+
+	if h.Title != `` {
+		user.SendText(fmt.Sprintf(`  <ansi fg="yellow-bold">Name:</ansi>      %s`, h.Title))
+	}
+
+	if housing.Rent > 0 && h.RentDueRound > 0 {
+		gd := gametime.GetDate(h.RentDueRound)
+		user.SendText(fmt.Sprintf(`  <ansi fg="yellow-bold">Rent:</ansi>      <ansi fg="gold">%d gold</ansi> due on day %d, month %d, year %d`, housing.Rent, gd.Day, gd.Month, gd.Year))
+		if h.MissedRent > 0 {
+			user.SendText(fmt.Sprintf(`  <ansi fg="yellow-bold">Missed:</ansi>    <ansi fg="alert-4">%d of %d payments before eviction</ansi>`, h.MissedRent, housing.GetEvictAfter()))
+		}
+	}
+
+	furnitureNames := []string{}
+	for containerName := range h.Furniture {
+		furnitureNames = append(furnitureNames, containerName)
+	}
+	sort.Strings(furnitureNames)
+	user.SendText(fmt.Sprintf(`  <ansi fg="yellow-bold">Furniture:</ansi> %d of %d %s`, len(furnitureNames), h.GetFurnitureLimit(), strings.Join(furnitureNames, `, `)))
+
+	user.SendText(fmt.Sprintf(`  <ansi fg="yellow-bold">Co-owners:</ansi> %s`, accessNames(h.CoOwners)))
+	user.SendText(fmt.Sprintf(`  <ansi fg="yellow-bold">Guests:</ansi>    %s`, accessNames(h.Guests)))
+	user.SendText(``)
+}
+
+func accessNames(access map[int]string) string {
+
+	if len(access) == 0 {
+		return `None`
+	}
+
+	names := make([]string, 0, len(access))
+	for _, name := range access {
+		names = append(names, `<ansi fg="username">`+name+`</ansi>`)
+	}
+	sort.Strings(names)
+
+	return strings.Join(names, `, `)
+}
+
+// Finds the user id and character name of someone whether they're online or not
+func findCharacter(name string) (int, string) {
+
+	if u := users.GetByCharacterName(name); u != nil {
+		return u.UserId, u.Character.Name
+	}
+
+	userId, _ := users.CharacterNameSearch(name)
+
+	return userId, name
+}
@@ -0,0 +1,40 @@
+package usercommands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/volte6/gomud/internal/rooms"
+	"github.com/volte6/gomud/internal/users"
+)
+
+func Knock(rest string, user *users.UserRecord, room *rooms.Room) (bool, error) {
+
+	rest = strings.ToLower(strings.TrimSpace(rest))
+
+	if rest == `` {
+		user.SendText("Knock on what?")
+		return true, nil
+	}
+
+	exitName, exitInfo, found := room.FindDoor(rest)
+	if !found {
+		user.SendText("There is no door like that here.")
+		return true, nil
+	}
+
+	if exitInfo.Door.Open {
+		user.SendText(fmt.Sprintf(`The <ansi fg="exit">%s</ansi> is open. You could just go <ansi fg="exit">%s</ansi>.`, exitInfo.Door.Name, exitName))
+		return true, nil
+	}
+
+	user.SendText(fmt.Sprintf(`You knock on the <ansi fg="exit">%s</ansi> to the <ansi fg="exit">%s</ansi>.`, exitInfo.Door.Name, exitName))
+	room.SendText(fmt.Sprintf(`<ansi fg="username">%s</ansi> knocks on the <ansi fg="exit">%s</ansi> to the <ansi fg="exit">%s</ansi>.`, user.Character.Name, exitInfo.Door.Name, exitName), user.UserId)
+
+	if otherRoom, otherExitName := room.GetDoorPair(exitName); otherRoom != nil {
+		otherExit, _ := otherRoom.GetExitInfo(otherExitName)
+		otherRoom.SendText(fmt.Sprintf(`Someone knocks on the <ansi fg="exit">%s</ansi> to the <ansi fg="exit">%s</ansi>.`, otherExit.Door.Name, otherExitName))
+	}
+
+	return true, nil
+}
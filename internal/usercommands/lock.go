@@ -77,7 +77,12 @@ func Lock(rest string, user *users.UserRecord, room *rooms.Room) (bool, error) {
 			return true, nil
 		}
 
-		lockId := fmt.Sprintf(`%d-%s`, room.RoomId, exitName)
+		if exitInfo.HasDoor() && exitInfo.Door.Open {
+			user.SendText(fmt.Sprintf(`You'll need to <ansi fg="command">close</ansi> the <ansi fg="exit">%s</ansi> first.`, exitInfo.Door.Name))
+			return true, nil
+		}
+
+		lockId := exitInfo.LockId(room.RoomId, exitName)
 		hasKey, _ := user.Character.HasKey(lockId, int(exitInfo.Lock.Difficulty))
 
 		var backpackKeyItm items.Item = items.Item{}
@@ -0,0 +1,51 @@
+package usercommands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/volte6/gomud/internal/rooms"
+	"github.com/volte6/gomud/internal/users"
+)
+
+func Open(rest string, user *users.UserRecord, room *rooms.Room) (bool, error) {
+
+	rest = strings.ToLower(strings.TrimSpace(rest))
+
+	if rest == `` {
+		user.SendText("Open what?")
+		return true, nil
+	}
+
+	exitName, exitInfo, found := room.FindDoor(rest)
+
+	// Containers and exits without doors only need unlocking
+	if !found {
+		return Unlock(rest, user, room)
+	}
+
+	if exitInfo.Door.Open {
+		user.SendText(fmt.Sprintf(`The <ansi fg="exit">%s</ansi> is already open.`, exitInfo.Door.Name))
+		return true, nil
+	}
+
+	// Try a key before giving up
+	if exitInfo.Lock.IsLocked() {
+		Unlock(exitName, user, room)
+		if exitInfo, _ = room.GetExitInfo(exitName); exitInfo.Lock.IsLocked() {
+			return true, nil
+		}
+	}
+
+	room.SetDoorOpen(exitName, true)
+
+	user.SendText(fmt.Sprintf(`You open the <ansi fg="exit">%s</ansi> to the <ansi fg="exit">%s</ansi>.`, exitInfo.Door.Name, exitName))
+	room.SendText(fmt.Sprintf(`<ansi fg="username">%s</ansi> opens the <ansi fg="exit">%s</ansi> to the <ansi fg="exit">%s</ansi>.`, user.Character.Name, exitInfo.Door.Name, exitName), user.UserId)
+
+	if otherRoom, otherExitName := room.GetDoorPair(exitName); otherRoom != nil {
+		otherExit, _ := otherRoom.GetExitInfo(otherExitName)
+		otherRoom.SendText(fmt.Sprintf(`The <ansi fg="exit">%s</ansi> to the <ansi fg="exit">%s</ansi> opens.`, otherExit.Door.Name, otherExitName))
+	}
+
+	return true, nil
+}
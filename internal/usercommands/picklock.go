@@ -80,7 +80,7 @@ func Picklock(rest string, user *users.UserRecord, room *rooms.Room) (bool, erro
 
 		lockStrength = int(exitInfo.Lock.Difficulty)
 		lockTrap = exitInfo.Lock.TrapBuffIds
-		lockId = fmt.Sprintf(`%d-%s`, room.RoomId, exitName)
+		lockId = exitInfo.LockId(room.RoomId, exitName)
 
 	} else {
 
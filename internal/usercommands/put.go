@@ -92,6 +92,11 @@ func Put(rest string, user *users.UserRecord, room *rooms.Room) (bool, error) {
 		room.SendText(fmt.Sprintf(`<ansi fg="username">%s</ansi> places some <ansi fg="gold">gold</ansi> into the <ansi fg="container">%s</ansi>`, user.Character.Name, containerName), user.UserId)
 	}
 
+	if itemFound && container.Capacity > 0 && len(container.Items) >= container.Capacity {
+		user.SendText(fmt.Sprintf(`The <ansi fg="container">%s</ansi> is full.`, containerName))
+		itemFound = false
+	}
+
 	if itemFound {
 		container.AddItem(item)
 		user.Character.RemoveItem(item)
@@ -100,7 +105,7 @@ func Put(rest string, user *users.UserRecord, room *rooms.Room) (bool, error) {
 		room.SendText(fmt.Sprintf(`<ansi fg="username">%s</ansi> places their <ansi fg="itemname">%s</ansi> into the <ansi fg="container">%s</ansi>`, user.Character.Name, item.DisplayName(), containerName), user.UserId)
 
 		// current hard limit of 10 max items.
-		if len(container.Items) > 10 {
+		if container.Capacity == 0 && len(container.Items) > 10 {
 
 			randItemToRemove := util.Rand(len(container.Items))
 			oopsItem := container.Items[randItemToRemove]
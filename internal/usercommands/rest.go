@@ -0,0 +1,29 @@
+package usercommands
+
+import (
+	"github.com/volte6/gomud/internal/buffs"
+	"github.com/volte6/gomud/internal/rooms"
+	"github.com/volte6/gomud/internal/users"
+)
+
+func Rest(rest string, user *users.UserRecord, room *rooms.Room) (bool, error) {
+
+	if user.Character.Aggro != nil {
+		user.SendText("You can't rest while you're fighting!")
+		return true, nil
+	}
+
+	if user.Character.HasBuffFlag(buffs.Resting) {
+		user.SendText("You're already resting.")
+		return true, nil
+	}
+
+	if user.Character.ActionPoints >= user.Character.ActionPointsMax.Value {
+		user.SendText("You aren't tired.")
+		return true, nil
+	}
+
+	user.AddBuff(41) // Resting
+
+	return true, nil
+}
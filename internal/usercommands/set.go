@@ -5,6 +5,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/volte6/gomud/internal/connections"
 	"github.com/volte6/gomud/internal/rooms"
 	"github.com/volte6/gomud/internal/users"
 	"github.com/volte6/gomud/internal/util"
@@ -47,6 +48,15 @@ func Set(rest string, user *users.UserRecord, room *rooms.Room) (bool, error) {
 		user.SendText(onTxt)
 		user.SendText(``)
 
+		on = user.GetConfigOption(`recording`)
+		onTxt = `<ansi fg="red">OFF</ansi>`
+		if on != nil && on.(bool) {
+			onTxt = `<ansi fg="green">ON</ansi>`
+		}
+		user.SendText(`<ansi fg="yellow-bold">recording:</ansi> `)
+		user.SendText(onTxt)
+		user.SendText(``)
+
 		currentPrompt := user.GetConfigOption(`prompt`)
 		if currentPrompt == nil {
 			currentPrompt = users.PromptDefault
@@ -134,6 +144,34 @@ func Set(rest string, user *users.UserRecord, room *rooms.Room) (bool, error) {
 
 	}
 
+	if setTarget == `recording` {
+
+		// A recording someone else started can't be stopped or replaced by the player
+		if rec := connections.GetRecorder(user.ConnectionId()); rec != nil && rec.StartedBy != user.Username {
+			user.SendText(`Your session is already being recorded, and that recording can't be changed from here.`)
+			return true, nil
+		}
+
+		on := user.GetConfigOption(`recording`)
+		if on == nil || !on.(bool) {
+			on = true
+			if _, err := connections.StartRecording(user.ConnectionId(), user.Username, user.Username); err != nil {
+				user.SendText(`Session recording could not be started.`)
+				return true, err
+			}
+			user.SendText(`Session recording turned <ansi fg="red">ON</ansi>.`)
+		} else {
+			on = false
+			connections.StopRecording(user.ConnectionId())
+			user.SendText(`Session recording turned <ansi fg="red">OFF</ansi>.`)
+		}
+
+		user.SetConfigOption(`recording`, on)
+
+		return true, nil
+
+	}
+
 	if setTarget == `prompt` {
 
 		if len(args) < 1 {
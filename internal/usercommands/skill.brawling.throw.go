@@ -163,6 +163,11 @@ func Throw(rest string, user *users.UserRecord, room *rooms.Room) (bool, error)
 		if exitName != `` {
 
 			exitInfo, _ := room.GetExitInfo(exitName)
+			if exitInfo.HasDoor() && !exitInfo.Door.Open {
+				user.SendText(fmt.Sprintf(`The %s to the %s is closed.`, exitInfo.Door.Name, exitName))
+				return true, nil
+			}
+
 			if exitInfo.Lock.IsLocked() {
 				user.SendText(fmt.Sprintf(`The %s exit is locked.`, exitName))
 				return true, nil
@@ -20,6 +20,8 @@ Level 1 - Map a 5x5 area
 Level 2 - Map a 9x7 area
 Level 3 - Map a 13x9 area
 Level 4 - Map a 17x9 area, and enables the "wide" version.
+
+"map up" and "map down" show the level above or below.
 */
 func Map(rest string, user *users.UserRecord, room *rooms.Room) (bool, error) {
 
@@ -30,6 +32,16 @@ func Map(rest string, user *users.UserRecord, room *rooms.Room) (bool, error) {
 		return true, errors.New(`you don't know how to map`)
 	}
 
+	// map up / map down
+	mapLevel := 0
+	if rest == "up" {
+		mapLevel = 1
+		rest = ""
+	} else if rest == "down" {
+		mapLevel = -1
+		rest = ""
+	}
+
 	if rest == "sprawl" {
 		user.SendText(fmt.Sprintf("The reach of your maps is %d rooms.", user.Character.GetMapSprawlCapacity()))
 		return true, nil
@@ -63,9 +75,12 @@ func Map(rest string, user *users.UserRecord, room *rooms.Room) (bool, error) {
 	}
 
 	// First check for a premade map.
-	if mapTxt, err := templates.Process("maps/"+rooms.ZoneNameSanitize(zone), zone); err == nil {
-		user.SendText(mapTxt)
-		return true, nil
+	// Premade maps are flat, so they aren't used for other levels.
+	if mapLevel == 0 {
+		if mapTxt, err := templates.Process("maps/"+rooms.ZoneNameSanitize(zone), zone); err == nil {
+			user.SendText(mapTxt)
+			return true, nil
+		}
 	}
 
 	var mapData rooms.MapData
@@ -176,10 +191,27 @@ func Map(rest string, user *users.UserRecord, room *rooms.Room) (bool, error) {
 
 	rGraph.AddRoomSymbolOverrides('@', "You", user.Character.RoomId)
 
+	minLevel, maxLevel := rGraph.LevelRange()
+	if mapLevel < minLevel || mapLevel > maxLevel {
+		if mapLevel > 0 {
+			user.SendText("You don't know of anything above here.")
+		} else {
+			user.SendText("You don't know of anything below here.")
+		}
+		return true, nil
+	}
+	rGraph.SetLevel(mapLevel)
+
+	// Only mention the level when there is more than one
+	mapTitle := zone
+	if minLevel != maxLevel {
+		mapTitle = fmt.Sprintf("%s (level %+d)", zone, mapLevel)
+	}
+
 	if rest == "wide" {
-		mapData, err = rooms.DrawZoneMapWide(rGraph, zone, mapWidth, mapHeight)
+		mapData, err = rooms.DrawZoneMapWide(rGraph, mapTitle, mapWidth, mapHeight)
 	} else {
-		mapData, err = rooms.DrawZoneMap(rGraph, zone, mapWidth, mapHeight)
+		mapData, err = rooms.DrawZoneMap(rGraph, mapTitle, mapWidth, mapHeight)
 	}
 
 	if mapData.LegendWidth < 72 { // 80 - " Legend "
@@ -11,6 +11,7 @@ import (
 	"github.com/volte6/gomud/internal/configs"
 	"github.com/volte6/gomud/internal/events"
 	"github.com/volte6/gomud/internal/rooms"
+	"github.com/volte6/gomud/internal/scripting"
 	"github.com/volte6/gomud/internal/templates"
 	"github.com/volte6/gomud/internal/term"
 	"github.com/volte6/gomud/internal/users"
@@ -38,6 +39,37 @@ func Suicide(rest string, user *users.UserRecord, room *rooms.Room) (bool, error
 		return true, nil
 	}
 
+	killedByUserIds := []int{}
+	for uid := range user.Character.PlayerDamage {
+		killedByUserIds = append(killedByUserIds, uid)
+	}
+
+	// Scripts can change the death penalties, or cancel the death entirely
+	hookDetails := scripting.HookDetails{
+		`killedByUserIds`:     killedByUserIds,
+		`loseLife`:            bool(config.PermaDeath),
+		`dropGold`:            true,
+		`dropBackpack`:        bool(config.OnDeathAlwaysDropBackpack),
+		`equipmentDropChance`: float64(config.OnDeathEquipmentDropChance),
+		`xpPenalty`:           string(config.OnDeathXPPenalty),
+	}
+	if scripting.TryPlayerHook(`onPlayerDeath`, user.UserId, hookDetails) {
+
+		if user.Character.Health < 1 {
+			user.Character.Health = 1
+		}
+		clear(user.Character.PlayerDamage)
+
+		return true, nil
+	}
+
+	config.PermaDeath = configs.ConfigBool(hookDetails.Bool(`loseLife`))
+	config.OnDeathAlwaysDropBackpack = configs.ConfigBool(hookDetails.Bool(`dropBackpack`))
+	config.OnDeathEquipmentDropChance = configs.ConfigFloat(hookDetails.Float(`equipmentDropChance`))
+	if xpPenalty := hookDetails.String(`xpPenalty`); xpPenalty != `` {
+		config.OnDeathXPPenalty = configs.ConfigString(xpPenalty)
+	}
+
 	// Send a death msg to everyone in the room.
 	room.SendText(
 		fmt.Sprintf(`<ansi fg="username">%s</ansi> has died.`, user.Character.Name),
@@ -129,7 +161,7 @@ func Suicide(rest string, user *users.UserRecord, room *rooms.Room) (bool, error
 		}
 	}
 
-	if user.Character.Gold > 0 {
+	if user.Character.Gold > 0 && hookDetails.Bool(`dropGold`) {
 		user.EventLog.Add(`death`, fmt.Sprintf(`Dropped <ansi fg="gold">%d gold</ansi> on death`, user.Character.Gold))
 		Drop(fmt.Sprintf(`%d gold`, user.Character.Gold), user, room)
 	}
@@ -2,10 +2,12 @@ package usercommands
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/volte6/gomud/internal/gametime"
 	"github.com/volte6/gomud/internal/rooms"
 	"github.com/volte6/gomud/internal/users"
+	"github.com/volte6/gomud/internal/weather"
 )
 
 func Time(rest string, user *users.UserRecord, room *rooms.Room) (bool, error) {
@@ -31,5 +33,11 @@ func Time(rest string, user *users.UserRecord, room *rooms.Room) (bool, error) {
 		gametime.GetZodiac(gd.Year),
 	))
 
+	seasonTxt := fmt.Sprintf(`It is <ansi fg="230">%s</ansi>.`, gametime.SeasonName(gd.Month))
+	if room.IsOutside() {
+		seasonTxt += fmt.Sprintf(` The weather is <ansi fg="weather">%s</ansi>.`, strings.ToLower(weather.GetInfo(room.GetWeather()).Name()))
+	}
+	user.SendText(seasonTxt)
+
 	return true, nil
 }
@@ -6,6 +6,7 @@ import (
 
 	"github.com/volte6/gomud/internal/buffs"
 	"github.com/volte6/gomud/internal/rooms"
+	"github.com/volte6/gomud/internal/scripting"
 	"github.com/volte6/gomud/internal/skills"
 	"github.com/volte6/gomud/internal/templates"
 	"github.com/volte6/gomud/internal/users"
@@ -163,6 +164,8 @@ func Train(rest string, user *users.UserRecord, room *rooms.Room) (bool, error)
 					fmt.Sprintf(`The trainer shakes <ansi fg="username">%ss</ansi> hand while congratulating them. Must be nice.`, user.Character.Name),
 					user.UserId)
 
+				scripting.TryPlayerHook(`onSkillGained`, user.UserId, scripting.HookDetails{`skill`: skillName, `level`: newLevel})
+
 				if match == string(skills.Tame) {
 					if newLevel == 1 {
 						user.Character.MobMastery.SetTame(1, 1)
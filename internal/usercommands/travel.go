@@ -0,0 +1,239 @@
+package usercommands
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/volte6/gomud/internal/gamelock"
+	"github.com/volte6/gomud/internal/items"
+	"github.com/volte6/gomud/internal/rooms"
+	"github.com/volte6/gomud/internal/templates"
+	"github.com/volte6/gomud/internal/users"
+)
+
+const (
+	// Travel is given up after this many rounds without getting anywhere
+	travelMaxStuckRounds = 3
+)
+
+// Where a player is travelling to. Kept in the user's temp data under "travel".
+type travelPlan struct {
+	Name        string // What they asked to travel to
+	RoomId      int
+	LastRoomId  int // Where they were the last time a step was taken
+	StuckRounds int // Rounds in a row they haven't moved
+}
+
+func Travel(rest string, user *users.UserRecord, room *rooms.Room) (bool, error) {
+
+	if rest == `` {
+		infoOutput, _ := templates.Process("help/travel", nil)
+		user.SendText(infoOutput)
+
+		if landmarks := rooms.GetLandmarks(room.Zone); len(landmarks) > 0 {
+			user.SendText(fmt.Sprintf(`Landmarks in %s: <ansi fg="yellow">%s</ansi>`, room.Zone, strings.Join(landmarks, `, `)))
+		}
+
+		return true, nil
+	}
+
+	if strings.EqualFold(rest, `stop`) {
+		if user.GetTempData(`travel`) == nil {
+			user.SendText(`You aren't travelling anywhere.`)
+		} else {
+			stopTravel(user, `You stop travelling.`)
+		}
+		return true, nil
+	}
+
+	if user.Character.Aggro != nil {
+		user.SendText("You can't do that! You are in combat!")
+		return true, nil
+	}
+
+	path, roomId, found := findTravelPath(user, room, rest)
+	if !found {
+		user.SendText(fmt.Sprintf(`You don't know a way to <ansi fg="yellow">%s</ansi> from here.`, rest))
+		return true, nil
+	}
+
+	if len(path) == 0 {
+		user.SendText(`You're already there.`)
+		return true, nil
+	}
+
+	user.SetTempData(`travel`, &travelPlan{
+		Name:   rest,
+		RoomId: roomId,
+	})
+
+	user.SendText(fmt.Sprintf(`You set off towards <ansi fg="yellow">%s</ansi> (%d rooms away). Type <ansi fg="command">travel stop</ansi> to stop.`, rest, len(path)))
+
+	ContinueTravel(user)
+
+	return true, nil
+}
+
+func Path(rest string, user *users.UserRecord, room *rooms.Room) (bool, error) {
+
+	if rest == `` {
+		infoOutput, _ := templates.Process("help/path", nil)
+		user.SendText(infoOutput)
+		return true, nil
+	}
+
+	path, _, found := findTravelPath(user, room, rest)
+	if !found {
+		user.SendText(fmt.Sprintf(`You don't know a way to <ansi fg="yellow">%s</ansi> from here.`, rest))
+		return true, nil
+	}
+
+	if len(path) == 0 {
+		user.SendText(`You're already there.`)
+		return true, nil
+	}
+
+	user.SendText(fmt.Sprintf(`The way to <ansi fg="yellow">%s</ansi> (%d rooms): %s`, rest, len(path), formatPath(path)))
+
+	return true, nil
+}
+
+// Takes the next step for a player that is travelling somewhere. Called once a round.
+func ContinueTravel(user *users.UserRecord) {
+
+	plan, ok := user.GetTempData(`travel`).(*travelPlan)
+	if !ok {
+		return
+	}
+
+	room := rooms.LoadRoom(user.Character.RoomId)
+	if room == nil {
+		stopTravel(user, ``)
+		return
+	}
+
+	if user.Character.Aggro != nil || room.AreMobsAttacking(user.UserId) || room.ArePlayersAttacking(user.UserId) {
+		stopTravel(user, `Combat interrupts your travel.`)
+		return
+	}
+
+	if room.RoomId == plan.RoomId {
+		stopTravel(user, fmt.Sprintf(`You have arrived at <ansi fg="yellow">%s</ansi>.`, plan.Name))
+		return
+	}
+
+	if room.RoomId == plan.LastRoomId {
+		if plan.StuckRounds++; plan.StuckRounds >= travelMaxStuckRounds {
+			stopTravel(user, `You can't seem to make any progress, and stop travelling.`)
+			return
+		}
+	} else {
+		plan.StuckRounds = 0
+	}
+	plan.LastRoomId = room.RoomId
+
+	// Found again every step, in case they were moved or the way changed
+	path, found := rooms.FindPath(room.RoomId, plan.RoomId, travelPathOptions(user))
+	if !found || len(path) == 0 {
+		stopTravel(user, fmt.Sprintf(`You've lost the way to <ansi fg="yellow">%s</ansi>.`, plan.Name))
+		return
+	}
+
+	if exitInfo, ok := room.GetExitInfo(path[0].ExitName); ok && exitInfo.HasDoor() && !exitInfo.Door.Open {
+		user.Command(`open ` + path[0].ExitName)
+	}
+
+	user.Command(`go ` + path[0].ExitName)
+}
+
+func stopTravel(user *users.UserRecord, msg string) {
+	user.SetTempData(`travel`, nil)
+	if msg != `` {
+		user.SendText(msg)
+	}
+}
+
+// Finds a path to a landmark or zone name. Admins and mods can also use a room id.
+func findTravelPath(user *users.UserRecord, room *rooms.Room, target string) (path []rooms.PathStep, roomId int, found bool) {
+
+	opts := travelPathOptions(user)
+
+	if targetRoomId, err := strconv.Atoi(target); err == nil {
+		if user.Permission != users.PermissionAdmin && user.Permission != users.PermissionMod {
+			return nil, 0, false
+		}
+		path, found = rooms.FindPath(room.RoomId, targetRoomId, opts)
+		return path, targetRoomId, found
+	}
+
+	return rooms.FindPathToLandmark(room.RoomId, target, opts)
+}
+
+// Players only use exits they can see, locks they can open and biomes they can enter. Closed doors are opened on the way.
+// Admins and mods also use secret exits.
+func travelPathOptions(user *users.UserRecord) rooms.PathOptions {
+
+	return rooms.PathOptions{
+		SecretExits: user.Permission == users.PermissionAdmin || user.Permission == users.PermissionMod,
+		OpenDoors:   true,
+		CanUnlock: func(lockId string, lock gamelock.Lock) bool {
+
+			hasKey, hasSequence := user.Character.HasKey(lockId, int(lock.Difficulty))
+			if hasKey {
+				return true
+			}
+
+			if _, hasBackpackKey := user.Character.FindKeyInBackpack(lockId); hasBackpackKey {
+				return true
+			}
+
+			if hasSequence {
+				for _, itm := range user.Character.GetAllBackpackItems() {
+					if itm.GetSpec().Type == items.Lockpicks {
+						return true
+					}
+				}
+			}
+
+			return false
+		},
+		HasItem: func(itemId int) bool {
+			for _, itm := range user.Character.GetAllBackpackItems() {
+				if itm.ItemId == itemId {
+					return true
+				}
+			}
+			for _, itm := range user.Character.GetAllWornItems() {
+				if itm.ItemId == itemId {
+					return true
+				}
+			}
+			return false
+		},
+	}
+}
+
+// Lists the exits of a path, grouping repeats such as "2 north, east"
+func formatPath(path []rooms.PathStep) string {
+
+	parts := []string{}
+
+	for i := 0; i < len(path); {
+
+		ct := 1
+		for i+ct < len(path) && path[i+ct].ExitName == path[i].ExitName {
+			ct++
+		}
+
+		if ct > 1 {
+			parts = append(parts, fmt.Sprintf(`%d <ansi fg="exit">%s</ansi>`, ct, path[i].ExitName))
+		} else {
+			parts = append(parts, fmt.Sprintf(`<ansi fg="exit">%s</ansi>`, path[i].ExitName))
+		}
+
+		i += ct
+	}
+
+	return strings.Join(parts, `, `)
+}
@@ -77,7 +77,7 @@ func Unlock(rest string, user *users.UserRecord, room *rooms.Room) (bool, error)
 			return true, nil
 		}
 
-		lockId := fmt.Sprintf(`%d-%s`, room.RoomId, exitName)
+		lockId := exitInfo.LockId(room.RoomId, exitName)
 		hasKey, _ := user.Character.HasKey(lockId, int(exitInfo.Lock.Difficulty))
 
 		var backpackKeyItm items.Item = items.Item{}
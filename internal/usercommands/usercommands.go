@@ -48,6 +48,7 @@ var (
 		`bump`:        {Bump, false, false},
 		`buy`:         {Buy, false, false},
 		`cast`:        {Cast, false, false},
+		`close`:       {Close, false, false},
 		`cooldowns`:   {Cooldowns, true, false},
 		`command`:     {Command, false, true}, // Admin only
 		`conditions`:  {Conditions, true, false},
@@ -58,6 +59,7 @@ var (
 		`drop`:        {Drop, true, false},
 		`drink`:       {Drink, false, false},
 		`eat`:         {Eat, false, false},
+		`eval`:        {Eval, true, true}, // Admin only
 		`emote`:       {Emote, true, false},
 		`enchant`:     {Enchant, false, false},
 		`exits`:       {Exits, true, false},
@@ -73,7 +75,9 @@ var (
 		`help`:        {Help, true, false},
 		`keyring`:     {KeyRing, true, false},
 		`killstats`:   {Killstats, true, false},
+		`knock`:       {Knock, false, false},
 		`history`:     {History, true, false},
+		`house`:       {House, false, false},
 		`inbox`:       {Inbox, true, false},
 		`inspect`:     {Inspect, false, false},
 		`inventory`:   {Inventory, true, false},
@@ -91,15 +95,19 @@ var (
 		`mute`:        {Mute, true, true},
 		`offer`:       {Offer, false, false},
 		`online`:      {Online, true, false},
+		`open`:        {Open, false, false},
 		`party`:       {Party, true, false},
 		`password`:    {Password, true, false},
+		`path`:        {Path, true, false},
 		`paz`:         {Paz, true, true}, // Admin only
 		`peep`:        {Peep, false, false},
+		`permdata`:    {PermData, true, true}, // Admin only
 		`pet`:         {Pet, false, false},
 		`picklock`:    {Picklock, false, false},
 		`pickpocket`:  {Pickpocket, false, false},
 		`prepare`:     {Prepare, true, true}, // Admin only
 		`portal`:      {Portal, false, false},
+		`possess`:     {Possess, true, true}, // Admin only
 		`pray`:        {Pray, false, false},
 		`print`:       {Print, true, false},
 		`printline`:   {PrintLine, true, false},
@@ -110,14 +118,19 @@ var (
 		`questtoken`:  {QuestToken, false, true}, // Admin only
 		`rank`:        {Rank, false, false},
 		`read`:        {Read, false, false},
+		`record`:      {Record, true, true}, // Admin only
 		`recover`:     {Recover, false, false},
 		`reload`:      {Reload, true, true}, // Admin only
 		`remove`:      {Remove, false, false},
+		`rest`:        {Rest, false, false},
+		`repl`:        {Repl, true, true},        // Admin only
 		`rename`:      {Rename, false, true},     // Admin only
 		`redescribe`:  {Redescribe, false, true}, // Admin only
 		`room`:        {Room, false, true},       // Admin only
 		`save`:        {Save, true, false},
 		`say`:         {Say, true, false},
+		`schedule`:    {Schedule, true, true}, // Admin only
+		`scripts`:     {Scripts, true, true},  // Admin only
 		`scribe`:      {Scribe, false, false},
 		`search`:      {Search, false, false},
 		`sell`:        {Sell, false, false},
@@ -130,6 +143,7 @@ var (
 		`skills`:      {Skills, true, false},
 		`skillset`:    {Skillset, false, true}, // Admin only
 		`sneak`:       {Sneak, false, false},
+		`snoop`:       {Snoop, true, true},  // Admin only
 		`spawn`:       {Spawn, false, true}, // Admin only
 		`spells`:      {Spells, true, false},
 		`stash`:       {Stash, false, false},
@@ -142,13 +156,16 @@ var (
 		`track`:       {Track, false, false},
 		`trash`:       {Trash, false, false},
 		`train`:       {Train, false, false},
+		`travel`:      {Travel, false, false},
 		`unenchant`:   {Unenchant, false, false},
 		`uncurse`:     {Uncurse, false, false},
 		`unlock`:      {Unlock, false, false},
 		`undeafen`:    {UnDeafen, true, true}, // Admin only
 		`unmute`:      {UnMute, true, true},   // Admin only
 		`use`:         {Use, false, false},
+		`vehicle`:     {Vehicle, true, true}, // Admin only
 		`dual-wield`:  {DualWield, true, false},
+		`weather`:     {Weather, true, true}, // Admin only
 		`whisper`:     {Whisper, true, false},
 		`who`:         {Who, true, false},
 		`zap`:         {Zap, true, true},   // Admin only
@@ -189,6 +206,18 @@ func GetCmdSuggestions(text string, includeAdmin bool) []string {
 		}
 	}
 
+	for _, scriptCmd := range scripting.GetScriptCommands() {
+		if scriptCmd.AdminOnly && !includeAdmin {
+			continue
+		}
+
+		for _, testCmd := range append([]string{scriptCmd.Name}, scriptCmd.Aliases...) {
+			if testCmd != text && strings.HasPrefix(testCmd, text) {
+				results = append(results, testCmd[len(text):])
+			}
+		}
+	}
+
 	return results
 }
 func GetHelpSuggestions(text string, includeAdmin bool) []string {
@@ -209,6 +238,15 @@ func GetHelpSuggestions(text string, includeAdmin bool) []string {
 		}
 	}
 
+	for _, scriptCmd := range scripting.GetScriptCommands() {
+		if scriptCmd.AdminOnly && !includeAdmin {
+			continue
+		}
+		if scriptCmd.Name != text && strings.HasPrefix(scriptCmd.Name, text) {
+			results = append(results, scriptCmd.Name[len(text):])
+		}
+	}
+
 	return results
 }
 
@@ -224,6 +262,8 @@ func TryCommand(cmd string, rest string, userId int) (bool, error) {
 		skipScript := false
 		if info, ok := userCommands[alias]; ok && info.AdminOnly {
 			skipScript = true
+		} else if scriptCmd, ok := scripting.GetScriptCommand(alias); ok && scriptCmd.AdminOnly {
+			skipScript = true
 		}
 
 		if !skipScript {
@@ -324,6 +364,26 @@ func TryCommand(cmd string, rest string, userId int) (bool, error) {
 		}
 	}
 
+	// Commands defined in the commands script folder
+	if scriptCmd, ok := scripting.GetScriptCommand(cmd); ok {
+
+		if userDisabled && !scriptCmd.AllowedWhenDowned && !scriptCmd.AdminOnly {
+			user.SendText("You are unable to do that while downed.")
+			return true, nil
+		}
+
+		if isAdmin || !scriptCmd.AdminOnly || user.HasAdminCommand(scriptCmd.Name) {
+
+			start := time.Now()
+			handled, err := scripting.TryScriptCommand(scriptCmd.Name, rest, user.UserId)
+			util.TrackTime(`usr-cmd[`+scriptCmd.Name+`]`, time.Since(start).Seconds())
+
+			if handled || err != nil {
+				return true, err
+			}
+		}
+	}
+
 	if _, ok := emoteAliases[cmd]; ok {
 		handled, err := Emote(cmd, user, room)
 		return handled, err
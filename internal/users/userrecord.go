@@ -28,7 +28,7 @@ var (
 	PermissionMod   string = "mod"   // Logged in has limited special powers
 	PermissionAdmin string = "admin" // Logged in and has special powers
 
-	PromptDefault         = `{8}[{t} {T} {255}HP:{hp}{8}/{HP} {255}MP:{13}{mp}{8}/{13}{MP}{8}]{239}{h}{8}:`
+	PromptDefault         = `{8}[{t} {T} {255}HP:{hp}{8}/{HP} {255}MP:{13}{mp}{8}/{13}{MP} {255}AP:{ap}{8}]{239}{h}{8}:`
 	promptDefaultCompiled = util.ConvertColorShortTags(PromptDefault)
 	promptColorRegex      = regexp.MustCompile(`\{(\d*)(?::)?(\d*)?\}`)
 	promptFindTagsRegex   = regexp.MustCompile(`\{[a-zA-Z%:\-]+\}`)
@@ -395,6 +395,9 @@ func (u *UserRecord) ProcessPromptString(promptStr string) string {
 			case `{ap}`:
 				promptOut.WriteString(strconv.Itoa(u.Character.ActionPoints))
 
+			case `{AP}`:
+				promptOut.WriteString(strconv.Itoa(u.Character.ActionPointsMax.Value))
+
 			case `{xp}`:
 				if currentXP == -1 && tnlXP == -1 {
 					currentXP, tnlXP = u.Character.XPTNLActual()
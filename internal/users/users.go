@@ -396,6 +396,10 @@ func SearchOfflineUsers(searchFunc func(u *UserRecord) bool) {
 			return nil
 		}
 
+		if len(path) > 11 && path[len(path)-11:] == `-house.yaml` {
+			return nil
+		}
+
 		var uRecord UserRecord
 
 		fpathLower := path[len(path)-5:] // Only need to compare the last 5 characters
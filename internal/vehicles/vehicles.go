@@ -0,0 +1,548 @@
+package vehicles
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/volte6/gomud/internal/configs"
+	"github.com/volte6/gomud/internal/exit"
+	"github.com/volte6/gomud/internal/fileloader"
+	"github.com/volte6/gomud/internal/gametime"
+	"github.com/volte6/gomud/internal/rooms"
+	"github.com/volte6/gomud/internal/scripting"
+)
+
+const (
+	defaultExitBack = `out`
+	// Docking exits are removed when the vehicle leaves, this just makes sure they don't linger forever.
+	exitExpires = `1 year`
+)
+
+var (
+	loadedVehicles = &VehicleFile{}
+)
+
+// A place the vehicle stops along its route.
+// Dock and Travel are game time periods such as "2 hours" or "1 day".
+type Stop struct {
+	RoomId  int      `yaml:"roomid"`
+	Dock    string   `yaml:"dock"`              // How long it waits here before departing
+	Travel  string   `yaml:"travel"`            // How long it takes to reach the next stop (or the first, after the last)
+	Scenery []string `yaml:"scenery,omitempty"` // Shown to passengers on the way to the next stop. Overrides the vehicle scenery.
+}
+
+// Text sent when the vehicle comes and goes.
+// {vehicle} is replaced with the vehicle name, and {stop} with the title of the stop room.
+type Messages struct {
+	Arrive       string `yaml:"arrive,omitempty"`       // Sent to the stop room
+	Depart       string `yaml:"depart,omitempty"`       // Sent to the stop room
+	ArriveAboard string `yaml:"arriveaboard,omitempty"` // Sent to the vehicle rooms
+	DepartAboard string `yaml:"departaboard,omitempty"` // Sent to the vehicle rooms
+}
+
+type Vehicle struct {
+	VehicleId string   `yaml:"vehicleid"`
+	Name      string   `yaml:"name"`
+	RoomIds   []int    `yaml:"roomids"`            // Rooms that make up the vehicle. The first is where passengers get on and off.
+	ExitName  string   `yaml:"exitname"`           // Exit added to a stop room while docked, leading aboard
+	ExitBack  string   `yaml:"exitback,omitempty"` // Exit added aboard while docked, leading to the stop. Defaults to "out"
+	Stops     []Stop   `yaml:"stops"`
+	Scenery   []string `yaml:"scenery,omitempty"` // Shown to passengers while travelling
+	Messages  Messages `yaml:"messages,omitempty"`
+	Disabled  bool     `yaml:"disabled,omitempty"`
+
+	legs     []leg    // Rounds spent docked and travelling for each stop
+	position position // Where the vehicle was last round
+	started  bool
+}
+
+type leg struct {
+	dockRounds   uint64
+	travelRounds uint64
+}
+
+// Where a vehicle is along its route
+type position struct {
+	stopIdx  int     // The stop it is docked at, or last left
+	docked   bool    // Whether it is docked at stopIdx, or travelling to the next stop
+	progress float64 // How far along the journey to the next stop (0.0 - 1.0)
+	changes  uint64  // The round it next departs or arrives
+}
+
+type VehicleFile struct {
+	Vehicles []*Vehicle `yaml:"vehicles"`
+}
+
+func (vf *VehicleFile) Filepath() string {
+	return filepath.Base(string(configs.GetConfig().FileVehicles))
+}
+
+func (vf *VehicleFile) Validate() error {
+
+	seen := map[string]struct{}{}
+	seenRooms := map[int]string{}
+
+	for _, v := range vf.Vehicles {
+
+		if err := v.Validate(); err != nil {
+			return err
+		}
+
+		if _, ok := seen[v.VehicleId]; ok {
+			return fmt.Errorf(`duplicate vehicleid: %s`, v.VehicleId)
+		}
+		seen[v.VehicleId] = struct{}{}
+
+		for _, roomId := range v.RoomIds {
+			if otherId, ok := seenRooms[roomId]; ok {
+				return fmt.Errorf(`vehicle %s: room %d is already part of vehicle %s`, v.VehicleId, roomId, otherId)
+			}
+			seenRooms[roomId] = v.VehicleId
+		}
+	}
+
+	return nil
+}
+
+func (v *Vehicle) Validate() error {
+
+	v.VehicleId = strings.ToLower(strings.TrimSpace(v.VehicleId))
+	v.ExitName = strings.ToLower(strings.TrimSpace(v.ExitName))
+	v.ExitBack = strings.ToLower(strings.TrimSpace(v.ExitBack))
+
+	if v.VehicleId == `` {
+		return errors.New(`vehicleid cannot be empty`)
+	}
+
+	if v.Name == `` {
+		v.Name = v.VehicleId
+	}
+
+	if len(v.RoomIds) == 0 {
+		return fmt.Errorf(`vehicle %s: no roomids defined`, v.VehicleId)
+	}
+
+	if v.ExitName == `` {
+		return fmt.Errorf(`vehicle %s: exitname cannot be empty`, v.VehicleId)
+	}
+
+	if v.ExitBack == `` {
+		v.ExitBack = defaultExitBack
+	}
+
+	if len(v.Stops) == 0 {
+		return fmt.Errorf(`vehicle %s: no stops defined`, v.VehicleId)
+	}
+
+	for i, s := range v.Stops {
+		if s.RoomId < 1 {
+			return fmt.Errorf(`vehicle %s: stop %d has no roomid`, v.VehicleId, i+1)
+		}
+		for _, roomId := range v.RoomIds {
+			if s.RoomId == roomId {
+				return fmt.Errorf(`vehicle %s: stop %d is one of its own rooms`, v.VehicleId, i+1)
+			}
+		}
+		if s.Dock == `` || s.Travel == `` {
+			return fmt.Errorf(`vehicle %s: stop %d needs both dock and travel periods`, v.VehicleId, i+1)
+		}
+		// Legs are fixed lengths of time, so "noon" or "sunset" make no sense here
+		if err := gametime.ValidatePeriod(s.Dock, false); err != nil {
+			return fmt.Errorf(`vehicle %s: stop %d dock: %w`, v.VehicleId, i+1, err)
+		}
+		if err := gametime.ValidatePeriod(s.Travel, false); err != nil {
+			return fmt.Errorf(`vehicle %s: stop %d travel: %w`, v.VehicleId, i+1, err)
+		}
+	}
+
+	// Recalculated next time it's needed
+	v.legs = nil
+
+	return nil
+}
+
+// The room passengers get on and off at
+func (v *Vehicle) BoardingRoomId() int {
+	return v.RoomIds[0]
+}
+
+// Returns true if the room is part of the vehicle
+func (v *Vehicle) HasRoom(roomId int) bool {
+	for _, rId := range v.RoomIds {
+		if rId == roomId {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns a short human readable description of where the vehicle is
+func (v *Vehicle) Status() string {
+
+	if v.Disabled {
+		return `disabled`
+	}
+
+	if !v.started {
+		return `waiting`
+	}
+
+	if v.position.docked {
+		return `docked at ` + stopTitle(v.Stops[v.position.stopIdx].RoomId)
+	}
+
+	return fmt.Sprintf(`travelling to %s (%d%%)`, stopTitle(v.Stops[v.nextStopIdx(v.position.stopIdx)].RoomId), int(v.position.progress*100))
+}
+
+// Returns the round the vehicle next arrives or departs
+func (v *Vehicle) NextChangeRound() uint64 {
+	return v.position.changes
+}
+
+// Returns the stop the vehicle is docked at, if any
+func (v *Vehicle) DockedAt() (Stop, bool) {
+	if !v.started || !v.position.docked {
+		return Stop{}, false
+	}
+	return v.Stops[v.position.stopIdx], true
+}
+
+func (v *Vehicle) nextStopIdx(stopIdx int) int {
+	return (stopIdx + 1) % len(v.Stops)
+}
+
+// Converts the dock and travel periods into rounds
+func (v *Vehicle) getLegs() []leg {
+
+	if v.legs != nil {
+		return v.legs
+	}
+
+	gd := gametime.GetDate(1)
+
+	v.legs = make([]leg, len(v.Stops))
+	for i, s := range v.Stops {
+		v.legs[i] = leg{
+			dockRounds:   gd.AddPeriod(s.Dock) - gd.RoundNumber,
+			travelRounds: gd.AddPeriod(s.Travel) - gd.RoundNumber,
+		}
+		// Every leg takes at least a round
+		if v.legs[i].dockRounds < 1 {
+			v.legs[i].dockRounds = 1
+		}
+		if v.legs[i].travelRounds < 1 {
+			v.legs[i].travelRounds = 1
+		}
+	}
+
+	return v.legs
+}
+
+// Works out where along the route the vehicle is on a given round.
+// Routes loop forever, so the position only depends on the round number.
+func (v *Vehicle) positionAt(roundNumber uint64) position {
+
+	legs := v.getLegs()
+
+	cycleRounds := uint64(0)
+	for _, l := range legs {
+		cycleRounds += l.dockRounds + l.travelRounds
+	}
+
+	offset := roundNumber % cycleRounds
+	cycleStart := roundNumber - offset
+
+	for i, l := range legs {
+
+		if offset < l.dockRounds {
+			return position{stopIdx: i, docked: true, changes: cycleStart + l.dockRounds}
+		}
+		offset -= l.dockRounds
+		cycleStart += l.dockRounds
+
+		if offset < l.travelRounds {
+			return position{stopIdx: i, progress: float64(offset) / float64(l.travelRounds), changes: cycleStart + l.travelRounds}
+		}
+		offset -= l.travelRounds
+		cycleStart += l.travelRounds
+	}
+
+	return position{stopIdx: 0, docked: true}
+}
+
+// Returns which scenery message should have been shown by this far into the journey, or -1 if none yet.
+// Messages are spread out evenly over the journey.
+func sceneryIndex(progress float64, sceneryCt int) int {
+	if sceneryCt == 0 {
+		return -1
+	}
+	return int(progress*float64(sceneryCt+1)) - 1
+}
+
+func (v *Vehicle) scenery(stopIdx int) []string {
+	if len(v.Stops[stopIdx].Scenery) > 0 {
+		return v.Stops[stopIdx].Scenery
+	}
+	return v.Scenery
+}
+
+func (v *Vehicle) formatMessage(msg string, defaultMsg string, stopRoomId int) string {
+	if msg == `` {
+		msg = defaultMsg
+	}
+	msg = strings.ReplaceAll(msg, `{vehicle}`, v.Name)
+	return strings.ReplaceAll(msg, `{stop}`, stopTitle(stopRoomId))
+}
+
+// Sends text to every vehicle room someone might be in
+func (v *Vehicle) sendAboard(txt string) {
+	for _, roomId := range v.RoomIds {
+		if !rooms.IsRoomLoaded(roomId) {
+			continue
+		}
+		if room := rooms.LoadRoom(roomId); room != nil {
+			room.SendText(txt)
+		}
+	}
+}
+
+func (v *Vehicle) stopExit() exit.TemporaryRoomExit {
+	return exit.TemporaryRoomExit{
+		RoomId:  v.BoardingRoomId(),
+		Title:   v.ExitName,
+		Expires: exitExpires,
+	}
+}
+
+func (v *Vehicle) boardingExit(stopRoomId int) exit.TemporaryRoomExit {
+	return exit.TemporaryRoomExit{
+		RoomId:  stopRoomId,
+		Title:   v.ExitBack,
+		Expires: exitExpires,
+	}
+}
+
+// Adds the exits between the vehicle and the stop, if they aren't already there.
+// Rooms that were unloaded lose their temporary exits, so this is checked every round while docked.
+func (v *Vehicle) addExits(stopRoomId int, loadRooms bool) {
+
+	if loadRooms || rooms.IsRoomLoaded(stopRoomId) {
+		if stopRoom := rooms.LoadRoom(stopRoomId); stopRoom != nil {
+			if _, ok := stopRoom.ExitsTemp[v.ExitName]; !ok {
+				stopRoom.AddTemporaryExit(v.ExitName, v.stopExit())
+			}
+		}
+	}
+
+	if loadRooms || rooms.IsRoomLoaded(v.BoardingRoomId()) {
+		if boardingRoom := rooms.LoadRoom(v.BoardingRoomId()); boardingRoom != nil {
+			if _, ok := boardingRoom.ExitsTemp[v.ExitBack]; !ok {
+				boardingRoom.AddTemporaryExit(v.ExitBack, v.boardingExit(stopRoomId))
+			}
+		}
+	}
+}
+
+func (v *Vehicle) removeExits(stopRoomId int) {
+
+	if rooms.IsRoomLoaded(stopRoomId) {
+		if stopRoom := rooms.LoadRoom(stopRoomId); stopRoom != nil {
+			stopRoom.RemoveTemporaryExit(v.stopExit())
+		}
+	}
+
+	if rooms.IsRoomLoaded(v.BoardingRoomId()) {
+		if boardingRoom := rooms.LoadRoom(v.BoardingRoomId()); boardingRoom != nil {
+			boardingRoom.RemoveTemporaryExit(v.boardingExit(stopRoomId))
+		}
+	}
+}
+
+func (v *Vehicle) arrive(stopIdx int) {
+
+	stopRoomId := v.Stops[stopIdx].RoomId
+
+	slog.Info(`Vehicle Arrive`, `vehicleId`, v.VehicleId, `stopRoomId`, stopRoomId)
+
+	v.addExits(stopRoomId, true)
+
+	if stopRoom := rooms.LoadRoom(stopRoomId); stopRoom != nil {
+		stopRoom.SendText(v.formatMessage(v.Messages.Arrive, `The {vehicle} arrives.`, stopRoomId))
+	}
+	v.sendAboard(v.formatMessage(v.Messages.ArriveAboard, `The {vehicle} arrives at {stop}.`, stopRoomId))
+
+	v.triggerScripts(`onVehicleArrive`, stopRoomId)
+}
+
+func (v *Vehicle) depart(stopIdx int) {
+
+	stopRoomId := v.Stops[stopIdx].RoomId
+
+	slog.Info(`Vehicle Depart`, `vehicleId`, v.VehicleId, `stopRoomId`, stopRoomId)
+
+	v.removeExits(stopRoomId)
+
+	if rooms.IsRoomLoaded(stopRoomId) {
+		if stopRoom := rooms.LoadRoom(stopRoomId); stopRoom != nil {
+			stopRoom.SendText(v.formatMessage(v.Messages.Depart, `The {vehicle} departs.`, stopRoomId))
+		}
+	}
+	v.sendAboard(v.formatMessage(v.Messages.DepartAboard, `The {vehicle} departs from {stop}.`, stopRoomId))
+
+	v.triggerScripts(`onVehicleDepart`, stopRoomId)
+}
+
+// Calls the event in the zone scripts of the stop and the vehicle, and the world script
+func (v *Vehicle) triggerScripts(eventName string, stopRoomId int) {
+
+	stopRoom := rooms.LoadRoom(stopRoomId)
+	boardingRoom := rooms.LoadRoom(v.BoardingRoomId())
+	if stopRoom == nil || boardingRoom == nil {
+		return
+	}
+
+	sStopRoom := scripting.GetRoom(stopRoomId)
+	sBoardingRoom := scripting.GetRoom(v.BoardingRoomId())
+
+	if _, err := scripting.TryZoneScriptEvent(eventName, stopRoom.Zone, v.VehicleId, sBoardingRoom, sStopRoom); err != nil {
+		slog.Error(`Vehicle Script`, `eventName`, eventName, `zone`, stopRoom.Zone, `error`, err)
+	}
+
+	if boardingRoom.Zone != stopRoom.Zone {
+		if _, err := scripting.TryZoneScriptEvent(eventName, boardingRoom.Zone, v.VehicleId, sBoardingRoom, sStopRoom); err != nil {
+			slog.Error(`Vehicle Script`, `eventName`, eventName, `zone`, boardingRoom.Zone, `error`, err)
+		}
+	}
+
+	if _, err := scripting.TryWorldScriptEvent(eventName, v.VehicleId, sBoardingRoom, sStopRoom); err != nil {
+		slog.Error(`Vehicle Script`, `eventName`, eventName, `error`, err)
+	}
+}
+
+// Moves the vehicle along its route
+func (v *Vehicle) roundTick(roundNumber uint64) {
+
+	now := v.positionAt(roundNumber)
+
+	// Nobody saw it get here, so it quietly docks without announcements
+	if !v.started {
+		v.started = true
+		v.position = now
+		if now.docked {
+			v.addExits(v.Stops[now.stopIdx].RoomId, true)
+		}
+		return
+	}
+
+	was := v.position
+	v.position = now
+
+	if was.docked != now.docked || was.stopIdx != now.stopIdx {
+
+		if was.docked {
+			v.depart(was.stopIdx)
+		}
+
+		if now.docked {
+			v.arrive(now.stopIdx)
+		}
+
+		return
+	}
+
+	if now.docked {
+		v.addExits(v.Stops[now.stopIdx].RoomId, false)
+		return
+	}
+
+	scenery := v.scenery(now.stopIdx)
+	if sceneryIdx := sceneryIndex(now.progress, len(scenery)); sceneryIdx >= 0 && sceneryIdx != sceneryIndex(was.progress, len(scenery)) {
+		v.sendAboard(scenery[sceneryIdx])
+	}
+}
+
+func stopTitle(roomId int) string {
+	if room := rooms.LoadRoom(roomId); room != nil {
+		return room.Title
+	}
+	return fmt.Sprintf(`room %d`, roomId)
+}
+
+// Returns all vehicles sorted by id
+func GetAll() []*Vehicle {
+	all := append([]*Vehicle{}, loadedVehicles.Vehicles...)
+	sort.Slice(all, func(i, j int) bool {
+		return all[i].VehicleId < all[j].VehicleId
+	})
+	return all
+}
+
+func Get(vehicleId string) *Vehicle {
+	vehicleId = strings.ToLower(vehicleId)
+	for _, v := range loadedVehicles.Vehicles {
+		if v.VehicleId == vehicleId {
+			return v
+		}
+	}
+	return nil
+}
+
+// Returns the vehicle a room is part of, if any
+func GetByRoomId(roomId int) *Vehicle {
+	for _, v := range loadedVehicles.Vehicles {
+		if v.HasRoom(roomId) {
+			return v
+		}
+	}
+	return nil
+}
+
+// Should be called once per round
+func RoundTick(roundNumber uint64) {
+	for _, v := range loadedVehicles.Vehicles {
+		if v.Disabled {
+			continue
+		}
+		v.roundTick(roundNumber)
+	}
+}
+
+// Removes any docking exits, such as before the vehicles are reloaded
+func undockAll() {
+	for _, v := range loadedVehicles.Vehicles {
+		if stop, ok := v.DockedAt(); ok {
+			v.removeExits(stop.RoomId)
+		}
+	}
+}
+
+func LoadDataFiles() {
+
+	start := time.Now()
+
+	path := string(configs.GetConfig().FileVehicles)
+
+	undockAll()
+
+	// The vehicle file is optional
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		loadedVehicles = &VehicleFile{}
+		slog.Info("vehicles.LoadDataFiles()", "loadedCount", 0, "Time Taken", time.Since(start))
+		return
+	}
+
+	tmpVehicles, err := fileloader.LoadFlatFile[*VehicleFile](path)
+	if err != nil {
+		panic(err)
+	}
+
+	loadedVehicles = tmpVehicles
+
+	slog.Info("vehicles.LoadDataFiles()", "loadedCount", len(loadedVehicles.Vehicles), "Time Taken", time.Since(start))
+}
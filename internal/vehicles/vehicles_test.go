@@ -0,0 +1,114 @@
+package vehicles
+
+import (
+	"testing"
+)
+
+func TestPositionAt(t *testing.T) {
+
+	v := &Vehicle{
+		VehicleId: `ferry`,
+		RoomIds:   []int{1},
+		ExitName:  `ferry`,
+		Stops: []Stop{
+			{RoomId: 10, Dock: `2 hours`, Travel: `1 hour`},
+			{RoomId: 20, Dock: `2 hours`, Travel: `1 hour`},
+		},
+	}
+
+	if err := v.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	// Skip the game time conversion
+	v.legs = []leg{
+		{dockRounds: 10, travelRounds: 5},
+		{dockRounds: 20, travelRounds: 10},
+	}
+
+	tests := []struct {
+		round    uint64
+		stopIdx  int
+		docked   bool
+		progress float64
+		changes  uint64
+	}{
+		{0, 0, true, 0, 10},
+		{9, 0, true, 0, 10},
+		{10, 0, false, 0, 15},
+		{12, 0, false, 0.4, 15},
+		{15, 1, true, 0, 35},
+		{35, 1, false, 0, 45},
+		{45, 0, true, 0, 55}, // Back to the first stop
+		{57, 0, false, 0.4, 60},
+	}
+
+	for _, tt := range tests {
+		pos := v.positionAt(tt.round)
+		if pos.stopIdx != tt.stopIdx || pos.docked != tt.docked || pos.progress != tt.progress || pos.changes != tt.changes {
+			t.Errorf("positionAt(%d) = %+v, want stopIdx=%d docked=%v progress=%v changes=%d", tt.round, pos, tt.stopIdx, tt.docked, tt.progress, tt.changes)
+		}
+	}
+}
+
+func TestSceneryIndex(t *testing.T) {
+
+	tests := []struct {
+		progress  float64
+		sceneryCt int
+		want      int
+	}{
+		{0.5, 0, -1},
+		{0.0, 2, -1},
+		{0.3, 2, -1},
+		{0.34, 2, 0},
+		{0.66, 2, 0},
+		{0.67, 2, 1},
+		{0.99, 2, 1},
+		{0.5, 1, 0},
+	}
+
+	for _, tt := range tests {
+		if got := sceneryIndex(tt.progress, tt.sceneryCt); got != tt.want {
+			t.Errorf("sceneryIndex(%v, %d) = %d, want %d", tt.progress, tt.sceneryCt, got, tt.want)
+		}
+	}
+}
+
+func TestVehicleFileValidate(t *testing.T) {
+
+	newVehicle := func(vehicleId string, roomId int) *Vehicle {
+		return &Vehicle{
+			VehicleId: vehicleId,
+			RoomIds:   []int{roomId},
+			ExitName:  `ship`,
+			Stops:     []Stop{{RoomId: 100, Dock: `1 hour`, Travel: `1 hour`}},
+		}
+	}
+
+	vf := &VehicleFile{Vehicles: []*Vehicle{newVehicle(`A`, 1), newVehicle(`b`, 2)}}
+	if err := vf.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if vf.Vehicles[0].VehicleId != `a` || vf.Vehicles[0].ExitBack != defaultExitBack {
+		t.Errorf("Validate() did not normalize vehicle: %+v", vf.Vehicles[0])
+	}
+
+	vf = &VehicleFile{Vehicles: []*Vehicle{newVehicle(`a`, 1), newVehicle(`b`, 1)}}
+	if err := vf.Validate(); err == nil {
+		t.Error("Validate() expected an error for a room shared by two vehicles")
+	}
+
+	for _, period := range []string{`5 xx`, `1 noon`, `sunset`, `often`} {
+		badPeriod := newVehicle(`a`, 1)
+		badPeriod.Stops[0].Travel = period
+		if err := badPeriod.Validate(); err == nil {
+			t.Errorf("Validate() expected an error for travel period %q", period)
+		}
+	}
+
+	docksAtItself := newVehicle(`a`, 100)
+	if err := docksAtItself.Validate(); err == nil {
+		t.Error("Validate() expected an error for a stop that is part of the vehicle")
+	}
+}
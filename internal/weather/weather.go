@@ -0,0 +1,243 @@
+package weather
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/volte6/gomud/internal/util"
+)
+
+type Weather string
+
+const (
+	Clear    Weather = `clear`
+	Rain     Weather = `rain`
+	Storm    Weather = `storm`
+	Snow     Weather = `snow`
+	Fog      Weather = `fog`
+	Heatwave Weather = `heatwave`
+
+	// Chance in 100 that the weather stays the same when it is rolled
+	stayChance = 60
+)
+
+type WeatherInfo struct {
+	name          string
+	description   string // Shown below the description of rooms that are outside
+	startText     string // Shown to players outside when it begins
+	visibilityMod int    // Added to the visibility of rooms that are outside
+	buffId        int    // Applied to players outside while it lasts. 0 for none.
+}
+
+func (wi WeatherInfo) Name() string {
+	return wi.name
+}
+
+func (wi WeatherInfo) Description() string {
+	return wi.description
+}
+
+func (wi WeatherInfo) StartText() string {
+	return wi.startText
+}
+
+func (wi WeatherInfo) VisibilityMod() int {
+	return wi.visibilityMod
+}
+
+func (wi WeatherInfo) BuffId() int {
+	return wi.buffId
+}
+
+// A change of weather in a zone that hasn't been announced yet
+type Change struct {
+	Zone   string
+	From   Weather
+	To     Weather
+	Forced bool // Whether an admin set it
+}
+
+type zoneWeather struct {
+	weather Weather
+	forced  bool // If forced, it doesn't change until released
+}
+
+var (
+	AllWeather = map[Weather]WeatherInfo{
+		Clear: {
+			name:      `Clear`,
+			startText: `The skies clear.`,
+		},
+		Rain: {
+			name:        `Rain`,
+			description: `Rain falls steadily from a grey sky.`,
+			startText:   `It begins to rain.`,
+			buffId:      40, // soaked
+		},
+		Storm: {
+			name:          `Storm`,
+			description:   `A storm rages overhead. Thunder rolls and sheets of rain lash the ground.`,
+			startText:     `Thunder rumbles as a storm rolls in.`,
+			visibilityMod: -1,
+			buffId:        40, // soaked
+		},
+		Snow: {
+			name:        `Snow`,
+			description: `Snow drifts down, blanketing everything in white.`,
+			startText:   `Snowflakes begin to fall.`,
+			buffId:      31, // freezing snow
+		},
+		Fog: {
+			name:          `Fog`,
+			description:   `A thick fog hangs in the air, hiding anything more than a few steps away.`,
+			startText:     `A thick fog rolls in.`,
+			visibilityMod: -1,
+		},
+		Heatwave: {
+			name:        `Heatwave`,
+			description: `The air shimmers in the oppressive heat.`,
+			startText:   `The air grows stiflingly hot.`,
+			buffId:      33, // thirsty
+		},
+	}
+
+	// How likely each weather is in each season
+	seasonChances = map[string]map[Weather]int{
+		`winter`: {Clear: 40, Rain: 10, Storm: 5, Snow: 30, Fog: 15, Heatwave: 0},
+		`spring`: {Clear: 45, Rain: 30, Storm: 10, Snow: 2, Fog: 13, Heatwave: 0},
+		`summer`: {Clear: 60, Rain: 12, Storm: 10, Snow: 0, Fog: 3, Heatwave: 15},
+		`autumn`: {Clear: 45, Rain: 25, Storm: 10, Snow: 5, Fog: 15, Heatwave: 0},
+	}
+
+	// Percent of the season chance that applies in a biome. Missing means 100.
+	biomeChances = map[string]map[Weather]int{
+		`desert`:    {Rain: 10, Storm: 20, Snow: 0, Fog: 10, Heatwave: 400},
+		`snow`:      {Rain: 20, Snow: 300, Heatwave: 0},
+		`mountains`: {Snow: 200, Heatwave: 50},
+		`cliffs`:    {Snow: 150, Storm: 150},
+		`swamp`:     {Fog: 300, Rain: 150, Snow: 50},
+		`water`:     {Storm: 200, Fog: 150},
+		`shore`:     {Storm: 150, Fog: 200},
+		`forest`:    {Rain: 150, Fog: 150},
+	}
+
+	zones          = map[string]zoneWeather{}
+	pendingChanges = []Change{}
+)
+
+func GetInfo(w Weather) WeatherInfo {
+	return AllWeather[w]
+}
+
+// Finds weather by name, such as "rain"
+func Find(name string) (Weather, bool) {
+	w := Weather(strings.ToLower(strings.TrimSpace(name)))
+	_, ok := AllWeather[w]
+	return w, ok
+}
+
+// Returns the names of all weather, sorted
+func GetAllNames() []string {
+	names := make([]string, 0, len(AllWeather))
+	for w := range AllWeather {
+		names = append(names, string(w))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Returns the current weather in a zone
+func Get(zone string) Weather {
+	if zw, ok := zones[zone]; ok {
+		return zw.weather
+	}
+	return Clear
+}
+
+// Whether an admin has forced the weather in a zone
+func IsForced(zone string) bool {
+	return zones[zone].forced
+}
+
+// Sets the weather in a zone.
+// Forced weather stays until it is released.
+func Set(zone string, w Weather, forced bool) {
+
+	from := Get(zone)
+	zones[zone] = zoneWeather{weather: w, forced: forced}
+
+	if from != w {
+		pendingChanges = append(pendingChanges, Change{Zone: zone, From: from, To: w, Forced: forced})
+	}
+}
+
+// Lets the weather in a zone change naturally again
+func Release(zone string) {
+	zw := zones[zone]
+	zw.forced = false
+	zones[zone] = zw
+}
+
+// Returns and forgets the changes since it was last called
+func TakeChanges() []Change {
+	changes := pendingChanges
+	pendingChanges = []Change{}
+	return changes
+}
+
+// Rolls the next weather for a zone from its biome and the season, unless it has been forced.
+func Roll(zone string, biome string, season string) {
+
+	if IsForced(zone) {
+		return
+	}
+
+	current := Get(zone)
+	if util.Rand(100) < stayChance && chance(current, biome, season) > 0 {
+		return
+	}
+
+	Set(zone, pick(biome, season), false)
+}
+
+// How likely weather is in a biome and season
+func chance(w Weather, biome string, season string) int {
+
+	ct := seasonChances[season][w]
+
+	if mods, ok := biomeChances[biome]; ok {
+		if pct, ok := mods[w]; ok {
+			ct = ct * pct / 100
+		}
+	}
+
+	return ct
+}
+
+func pick(biome string, season string) Weather {
+
+	all := make([]Weather, 0, len(AllWeather))
+	for w := range AllWeather {
+		all = append(all, w)
+	}
+	// Sorted so that the same roll always picks the same weather
+	sort.Slice(all, func(i, j int) bool { return all[i] < all[j] })
+
+	total := 0
+	for _, w := range all {
+		total += chance(w, biome, season)
+	}
+
+	if total == 0 {
+		return Clear
+	}
+
+	roll := util.Rand(total)
+	for _, w := range all {
+		if roll -= chance(w, biome, season); roll < 0 {
+			return w
+		}
+	}
+
+	return Clear
+}
@@ -0,0 +1,42 @@
+package weather
+
+import "testing"
+
+func TestPick_BiomeExcludesWeather(t *testing.T) {
+
+	for i := 0; i < 500; i++ {
+		if w := pick(`desert`, `winter`); w == Snow {
+			t.Fatalf("pick(desert, winter) = %s, expected it never to snow in the desert", w)
+		}
+		if w := pick(`snow`, `summer`); w == Heatwave {
+			t.Fatalf("pick(snow, summer) = %s, expected no heatwave in the snow", w)
+		}
+	}
+}
+
+func TestRoll_ForcedWeatherStays(t *testing.T) {
+
+	zone := `Weather Test`
+	defer delete(zones, zone)
+
+	Set(zone, Snow, true)
+	TakeChanges()
+
+	for i := 0; i < 100; i++ {
+		Roll(zone, `desert`, `summer`)
+	}
+
+	if w := Get(zone); w != Snow {
+		t.Errorf("Get() = %s after rolling forced weather, expected %s", w, Snow)
+	}
+	if changes := TakeChanges(); len(changes) != 0 {
+		t.Errorf("TakeChanges() = %v, expected no changes while forced", changes)
+	}
+
+	Release(zone)
+	Set(zone, Clear, false)
+
+	if changes := TakeChanges(); len(changes) != 1 || changes[0].From != Snow || changes[0].To != Clear {
+		t.Errorf("TakeChanges() = %v, expected a change from %s to %s", changes, Snow, Clear)
+	}
+}
@@ -0,0 +1,103 @@
+package web
+
+import (
+	"fmt"
+	"html"
+	"log/slog"
+	"net/http"
+	"sort"
+	"text/template"
+
+	"github.com/volte6/gomud/internal/rooms"
+)
+
+func zonesIndex(w http.ResponseWriter, r *http.Request) {
+
+	tmpl, err := template.New("index.html").Funcs(funcMap).ParseFiles("_datafiles/html/admin/_header.html", "_datafiles/html/admin/zones/index.html", "_datafiles/html/admin/_footer.html")
+	if err != nil {
+		slog.Error("HTML Template", "error", err)
+	}
+
+	zoneNames := rooms.GetAllZoneNames()
+	sort.Strings(zoneNames)
+
+	zoneIndexData := struct {
+		Zones []string
+	}{
+		zoneNames,
+	}
+
+	if err := tmpl.Execute(w, zoneIndexData); err != nil {
+		slog.Error("HTML Execute", "error", err)
+	}
+
+}
+
+func zoneMap(w http.ResponseWriter, r *http.Request) {
+
+	// Only known zones are rendered, since the page isn't escaped
+	zone := r.URL.Query().Get(`zone`)
+	if _, err := rooms.GetZoneRoot(zone); err != nil {
+		http.Error(w, `zone not found`, http.StatusNotFound)
+		return
+	}
+
+	tmpl, err := template.New("zone.map.html").Funcs(funcMap).ParseFiles("_datafiles/html/admin/zones/zone.map.html")
+	if err != nil {
+		slog.Error("HTML Template", "error", err)
+	}
+
+	tplData := map[string]any{}
+	tplData[`zone`] = zone
+	tplData[`zoneName`] = html.EscapeString(zone)
+	tplData[`roomCount`] = rooms.GetRoomCount(zone)
+
+	svg, err := rooms.ExportZoneSVG(zone)
+	if err != nil {
+		tplData[`error`] = html.EscapeString(err.Error())
+	}
+	tplData[`svg`] = svg
+
+	if err := tmpl.Execute(w, tplData); err != nil {
+		slog.Error("HTML Execute", "error", err)
+	}
+
+}
+
+// Downloads a zone map as a DOT or SVG file
+func zoneExport(w http.ResponseWriter, r *http.Request) {
+
+	urlVals := r.URL.Query()
+
+	zone := urlVals.Get(`zone`)
+	format := urlVals.Get(`format`)
+
+	var exportData string
+	var contentType string
+	var err error
+
+	switch format {
+	case `dot`:
+		exportData, err = rooms.ExportZoneDOT(zone)
+		contentType = `text/vnd.graphviz`
+	case `svg`:
+		exportData, err = rooms.ExportZoneSVG(zone)
+		contentType = `image/svg+xml`
+	default:
+		http.Error(w, `format must be dot or svg`, http.StatusBadRequest)
+		return
+	}
+
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set(`Content-Type`, contentType)
+	w.Header().Set(`Content-Disposition`, fmt.Sprintf(`attachment; filename="%s.%s"`, rooms.ZoneNameSanitize(zone), format))
+
+	if _, err := w.Write([]byte(exportData)); err != nil {
+		slog.Error("Zone Export", "error", err)
+	}
+
+}
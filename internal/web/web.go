@@ -99,6 +99,17 @@ func Listen(webPort int, wg *sync.WaitGroup, webSocketHandler func(*websocket.Co
 		doBasicAuth(mutatorData),
 	))
 
+	// Zone Admin
+	http.HandleFunc("GET /admin/zones/", RunWithMUDLocked(
+		doBasicAuth(zonesIndex),
+	))
+	http.HandleFunc("GET /admin/zones/zonemap/", RunWithMUDLocked(
+		doBasicAuth(zoneMap),
+	))
+	http.HandleFunc("GET /admin/zones/export/", RunWithMUDLocked(
+		doBasicAuth(zoneExport),
+	))
+
 	go func() {
 		defer wg.Done()
 		if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
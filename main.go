@@ -38,6 +38,7 @@ import (
 	"github.com/volte6/gomud/internal/quests"
 	"github.com/volte6/gomud/internal/races"
 	"github.com/volte6/gomud/internal/rooms"
+	"github.com/volte6/gomud/internal/schedules"
 	"github.com/volte6/gomud/internal/scripting"
 	"github.com/volte6/gomud/internal/spells"
 	"github.com/volte6/gomud/internal/suggestions"
@@ -45,6 +46,7 @@ import (
 	"github.com/volte6/gomud/internal/term"
 	"github.com/volte6/gomud/internal/users"
 	"github.com/volte6/gomud/internal/util"
+	"github.com/volte6/gomud/internal/vehicles"
 	"github.com/volte6/gomud/internal/version"
 	"github.com/volte6/gomud/internal/web"
 )
@@ -139,7 +141,17 @@ func main() {
 
 	gametime.GetZodiac(1) // The first time this is called it randomizes all zodiacs
 
-	scripting.Setup(int(c.ScriptLoadTimeoutMs), int(c.ScriptRoomTimeoutMs))
+	scripting.Setup(int(c.ScriptLoadTimeoutMs), int(c.ScriptRoomTimeoutMs), int(c.ScriptMaxHeapGrowthMB))
+	scripting.LoadCommandScripts()
+	scripting.ReloadChangedScripts() // Remembers which scripts exist, so only later changes are reloaded
+	scripting.RestoreRoomTimers()
+
+	if testPath := flags.ScriptTestPath(); testPath != `` {
+		if _, failed := scripting.RunScriptTests(testPath, os.Stdout); failed > 0 {
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
 
 	//
 	slog.Info(`========================`)
@@ -771,6 +783,8 @@ func loadAllDataFiles(isReload bool) {
 	templates.LoadAliases()
 	keywords.LoadAliases()
 	mutators.LoadDataFiles()
+	schedules.LoadDataFiles()
+	vehicles.LoadDataFiles()
 	colorpatterns.LoadColorPatterns()
 	characters.CompileAdjectiveSwaps() // This should come after loading color patterns.
 }
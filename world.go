@@ -17,6 +17,7 @@ import (
 	"github.com/volte6/gomud/internal/colorpatterns"
 	"github.com/volte6/gomud/internal/configs"
 	"github.com/volte6/gomud/internal/connections"
+	"github.com/volte6/gomud/internal/crashreports"
 	"github.com/volte6/gomud/internal/events"
 	"github.com/volte6/gomud/internal/items"
 	"github.com/volte6/gomud/internal/keywords"
@@ -116,6 +117,17 @@ func (w *World) enterWorld(userId int, roomId int) {
 	users.RemoveZombieUser(userId)
 
 	room := rooms.LoadRoom(user.Character.RoomId)
+
+	// Instances don't outlast their party or a restart, so go back to where it was entered
+	if room == nil || !rooms.CanEnterRoom(userId, room.RoomId) {
+		if entranceRoomId, ok := user.Character.GetMiscData(`InstanceEntrance`).(int); ok {
+			user.Character.SetMiscData(`InstanceEntrance`, nil)
+			user.Character.RoomId = entranceRoomId
+			room = rooms.LoadRoom(user.Character.RoomId)
+			roomId = user.Character.RoomId
+		}
+	}
+
 	if room == nil {
 
 		slog.Error("EnterWorld", "error", fmt.Sprintf(`room %d not found`, user.Character.RoomId))
@@ -128,6 +140,15 @@ func (w *World) enterWorld(userId int, roomId int) {
 		}
 	}
 
+	// Players can opt in to having their sessions recorded
+	if recordOn := user.GetConfigOption(`recording`); recordOn != nil && recordOn.(bool) {
+		if connections.GetRecorder(user.ConnectionId()) == nil {
+			if _, err := connections.StartRecording(user.ConnectionId(), user.Username, user.Username); err != nil {
+				slog.Error("EnterWorld", "error", err)
+			}
+		}
+	}
+
 	// TODO HERE
 	loginCmds := configs.GetConfig().OnLoginCommands
 	if len(loginCmds) > 0 {
@@ -160,6 +181,8 @@ func (w *World) enterWorld(userId int, roomId int) {
 
 	// Pu thtme in the room
 	rooms.MoveToRoom(userId, roomId, true)
+
+	scripting.TryPlayerHook(`onPlayerLogin`, userId, nil)
 }
 
 func (w *World) leaveWorld(userId int) {
@@ -169,12 +192,16 @@ func (w *World) leaveWorld(userId int) {
 		return
 	}
 
+	scripting.TryPlayerHook(`onPlayerLogout`, userId, nil)
+
 	room := rooms.LoadRoom(user.Character.RoomId)
 
 	if currentParty := parties.Get(userId); currentParty != nil {
 		currentParty.Leave(userId)
 	}
 
+	usercommands.ReleasePossessed(user)
+
 	for _, mobInstId := range room.GetMobs(rooms.FindCharmed) {
 		if mob := mobs.GetInstance(mobInstId); mob != nil {
 			if mob.Character.IsCharmed(userId) {
@@ -638,6 +665,10 @@ func (w *World) MainWorker(shutdown chan bool, wg *sync.WaitGroup) {
 	turnTimer := time.NewTimer(time.Duration(c.TurnMs) * time.Millisecond)
 	statsTimer := time.NewTimer(time.Duration(10) * time.Second)
 
+	util.LockMud()
+	triggerZoneScripts(`onServerStart`)
+	util.UnlockMud()
+
 loop:
 	for {
 
@@ -771,6 +802,22 @@ func (w *World) processInput(userId int, inputText string) {
 
 	connId := user.ConnectionId()
 
+	// A panic while handling input should only abort this one command
+	originalInput := inputText
+	defer func() {
+		if r := recover(); r != nil {
+
+			report := crashreports.New(r, `processInput`)
+			report.UserId = userId
+			report.RoomId = user.Character.RoomId
+			report.Input = originalInput
+			report.Save()
+
+			user.SendText(`<ansi fg="red">Something went wrong while doing that. The admins have been notified.</ansi>`)
+			connections.SendTo([]byte(templates.AnsiParse(user.GetCommandPrompt(true))), connId)
+		}
+	}()
+
 	var activeQuestion *prompt.Question = nil
 
 	if cmdPrompt := user.GetPrompt(); cmdPrompt != nil {
@@ -799,6 +846,33 @@ func (w *World) processInput(userId int, inputText string) {
 
 	inputText = strings.TrimSpace(inputText)
 
+	// Admins possessing a mob have their input performed by the mob instead
+	if possessedMob := usercommands.GetPossessed(user); possessedMob != nil {
+
+		if len(inputText) > 0 {
+			user.SetLastInputRound(util.GetRoundCount())
+		}
+
+		if strings.EqualFold(inputText, `return`) {
+			usercommands.ReleasePossessed(user)
+			user.SendText(fmt.Sprintf(`You leave <ansi fg="mobname">%s</ansi> and return to your body.`, possessedMob.Character.Name))
+		} else if len(inputText) > 0 {
+
+			roomIdBefore := possessedMob.Character.RoomId
+
+			w.processMobInput(possessedMob.InstanceId, inputText)
+
+			if mob := usercommands.GetPossessed(user); mob == nil {
+				user.SendText(`Your connection to the mob is severed and you return to your body.`)
+			} else if mob.Character.RoomId != roomIdBefore {
+				usercommands.LookRoomSecretly(user, mob.Character.RoomId)
+			}
+		}
+
+		connections.SendTo([]byte(templates.AnsiParse(user.GetCommandPrompt(true))), connId)
+		return
+	}
+
 	if len(inputText) > 0 {
 
 		// Update their last input
@@ -870,6 +944,17 @@ func (w *World) processMobInput(mobInstanceId int, inputText string) {
 		return
 	}
 
+	// A panic while handling input should only abort this one command
+	defer func() {
+		if r := recover(); r != nil {
+			report := crashreports.New(r, `processMobInput`)
+			report.MobInstanceId = mobInstanceId
+			report.RoomId = mob.Character.RoomId
+			report.Input = inputText
+			report.Save()
+		}
+	}()
+
 	command := ""
 	remains := ""
 
@@ -1093,6 +1178,25 @@ func (w *World) MessageTick() {
 				}
 			}
 
+			// Admins possessing a mob in the room see what the mob sees
+			for _, mobInstanceId := range room.GetMobs() {
+
+				mob := mobs.GetInstance(mobInstanceId)
+				if mob == nil || mob.PossessedBy < 1 || mob.PossessedBy == message.UserId {
+					continue
+				}
+
+				user := users.GetByUserId(mob.PossessedBy)
+				if user == nil || user.Character.RoomId == room.RoomId {
+					continue
+				}
+
+				connections.SendTo([]byte(term.AnsiMoveCursorColumn.String()+term.AnsiEraseLine.String()+templates.AnsiParse(`<ansi fg="magenta">»</ansi> `+message.Text)), user.ConnectionId())
+				if _, ok := redrawPrompts[user.ConnectionId()]; !ok {
+					redrawPrompts[user.ConnectionId()] = templates.AnsiParse(user.GetCommandPrompt(true))
+				}
+			}
+
 		}
 
 	}
@@ -1144,6 +1248,11 @@ func (w *World) TurnTick() {
 
 	turnCt := util.IncrementTurnCount()
 
+	//
+	// Release any scheduled events that are now due into their queues
+	//
+	events.ReleaseDue(turnCt)
+
 	//
 	// Cleanup any zombies
 	//
@@ -1234,13 +1343,16 @@ func (w *World) TurnTick() {
 
 		//slog.Debug(`Event`, `type`, input.Type(), `UserId`, input.UserId, `MobInstanceId`, input.MobInstanceId, `WaitTurns`, input.WaitTurns, `InputText`, input.InputText)
 
+		// Delayed input is handed off to the scheduler and comes back when it is due
+		if input.WaitTurns > 0 {
+			waitTurns := input.WaitTurns
+			input.WaitTurns = 0
+			events.ScheduleTurns(input, waitTurns)
+			continue
+		}
+
 		if input.MobInstanceId > 0 {
-			if input.WaitTurns < 1 {
-				w.processMobInput(input.MobInstanceId, input.InputText)
-			} else {
-				input.WaitTurns--
-				events.Requeue(input)
-			}
+			w.processMobInput(input.MobInstanceId, input.InputText)
 			continue
 		}
 
@@ -1254,13 +1366,9 @@ func (w *World) TurnTick() {
 			continue
 		}
 
-		if input.WaitTurns == 0 { // 0 means process immediately but wait another turn before processing another from this user
-			w.processInput(input.UserId, input.InputText)
-			alreadyProcessed[input.UserId] = struct{}{}
-		} else {
-			input.WaitTurns--
-			events.Requeue(input)
-		}
+		// 0 means process immediately but wait another turn before processing another from this user
+		w.processInput(input.UserId, input.InputText)
+		alreadyProcessed[input.UserId] = struct{}{}
 
 	}
 
@@ -1282,10 +1390,16 @@ func (w *World) TurnTick() {
 
 		if action.WaitTurns > 0 {
 
-			if action.WaitTurns%c.TurnsPerRound() == 0 {
-				// Get the parts of the command
-				parts := strings.SplitN(action.Action, ` `, 3)
-				if parts[0] == `detonate` {
+			// Get the parts of the command
+			parts := strings.SplitN(action.Action, ` `, 3)
+
+			// Most actions just wait on the scheduler until they are due
+			waitTurns := action.WaitTurns
+
+			if parts[0] == `detonate` {
+
+				// Detonations wake up once a round to warn the room
+				if action.WaitTurns%c.TurnsPerRound() == 0 {
 					// Make sure the room exists
 					room := rooms.LoadRoom(action.RoomId)
 					if room == nil {
@@ -1308,10 +1422,15 @@ func (w *World) TurnTick() {
 					room.SendText(fmt.Sprintf(`The <ansi fg="itemname">%s</ansi> looks like it's about to explode...`, itm.DisplayName()))
 				}
 
+				if nextWarning := action.WaitTurns % c.TurnsPerRound(); nextWarning > 0 {
+					waitTurns = nextWarning
+				} else if action.WaitTurns > c.TurnsPerRound() {
+					waitTurns = c.TurnsPerRound()
+				}
 			}
 
-			action.WaitTurns--
-			events.Requeue(action)
+			action.WaitTurns -= waitTurns
+			events.ScheduleTurns(action, waitTurns)
 			continue
 		}
 
@@ -1505,7 +1624,14 @@ func (w *World) TurnTick() {
 			continue
 		}
 
-		slog.Debug(`Event`, `type`, buff.Type(), `UserId`, buff.UserId, `MobInstanceId`, buff.MobInstanceId, `BuffId`, buff.BuffId)
+		slog.Debug(`Event`, `type`, buff.Type(), `UserId`, buff.UserId, `MobInstanceId`, buff.MobInstanceId, `BuffId`, buff.BuffId, `WaitTurns`, buff.WaitTurns)
+
+		if buff.WaitTurns > 0 {
+			waitTurns := buff.WaitTurns
+			buff.WaitTurns = 0
+			events.ScheduleTurns(buff, waitTurns)
+			continue
+		}
 
 		buffInfo := buffs.GetBuffSpec(buff.BuffId)
 		if buffInfo == nil {
@@ -1621,33 +1747,55 @@ func (w *World) TurnTick() {
 				questUser.SendText(questUpTxt)
 			}
 
+			// Scripts can change the rewards, or cancel them entirely
+			rewards := questInfo.Rewards
+			hookDetails := scripting.HookDetails{
+				`questId`:    quest.QuestToken,
+				`questName`:  questInfo.Name,
+				`gold`:       rewards.Gold,
+				`experience`: rewards.Experience,
+				`itemId`:     rewards.ItemId,
+				`buffId`:     rewards.BuffId,
+				`skill`:      rewards.SkillInfo,
+				`roomId`:     rewards.RoomId,
+			}
+			if scripting.TryPlayerHook(`onQuestComplete`, questUser.UserId, hookDetails) {
+				continue
+			}
+			rewards.Gold = hookDetails.Int(`gold`)
+			rewards.Experience = hookDetails.Int(`experience`)
+			rewards.ItemId = hookDetails.Int(`itemId`)
+			rewards.BuffId = hookDetails.Int(`buffId`)
+			rewards.SkillInfo = hookDetails.String(`skill`)
+			rewards.RoomId = hookDetails.Int(`roomId`)
+
 			// Message to player?
-			if len(questInfo.Rewards.PlayerMessage) > 0 {
-				questUser.SendText(questInfo.Rewards.PlayerMessage)
+			if len(rewards.PlayerMessage) > 0 {
+				questUser.SendText(rewards.PlayerMessage)
 			}
 			// Message to room?
-			if len(questInfo.Rewards.RoomMessage) > 0 {
+			if len(rewards.RoomMessage) > 0 {
 				if room := rooms.LoadRoom(questUser.Character.RoomId); room != nil {
-					room.SendText(questInfo.Rewards.RoomMessage, questUser.UserId)
+					room.SendText(rewards.RoomMessage, questUser.UserId)
 				}
 			}
 			// New quest to start?
-			if len(questInfo.Rewards.QuestId) > 0 {
+			if len(rewards.QuestId) > 0 {
 
 				events.AddToQueue(events.Quest{
 					UserId:     questUser.UserId,
-					QuestToken: questInfo.Rewards.QuestId,
+					QuestToken: rewards.QuestId,
 				})
 
 			}
 			// Gold reward?
-			if questInfo.Rewards.Gold > 0 {
-				questUser.SendText(fmt.Sprintf(`You receive <ansi fg="gold">%d gold</ansi>!`, questInfo.Rewards.Gold))
-				questUser.Character.Gold += questInfo.Rewards.Gold
+			if rewards.Gold > 0 {
+				questUser.SendText(fmt.Sprintf(`You receive <ansi fg="gold">%d gold</ansi>!`, rewards.Gold))
+				questUser.Character.Gold += rewards.Gold
 			}
 			// Item reward?
-			if questInfo.Rewards.ItemId > 0 {
-				newItm := items.New(questInfo.Rewards.ItemId)
+			if rewards.ItemId > 0 {
+				newItm := items.New(rewards.ItemId)
 				questUser.SendText(fmt.Sprintf(`You receive <ansi fg="itemname">%s</ansi>!`, newItm.NameSimple()))
 				questUser.Character.StoreItem(newItm)
 
@@ -1662,22 +1810,22 @@ func (w *World) TurnTick() {
 				}
 			}
 			// Buff reward?
-			if questInfo.Rewards.BuffId > 0 {
+			if rewards.BuffId > 0 {
 
 				events.AddToQueue(events.Buff{
 					UserId:        questUser.UserId,
 					MobInstanceId: 0,
-					BuffId:        questInfo.Rewards.BuffId,
+					BuffId:        rewards.BuffId,
 				})
 
 			}
 			// Experience reward?
-			if questInfo.Rewards.Experience > 0 {
-				questUser.GrantXP(questInfo.Rewards.Experience, `quest progress`)
+			if rewards.Experience > 0 {
+				questUser.GrantXP(rewards.Experience, `quest progress`)
 			}
 			// Skill reward?
-			if questInfo.Rewards.SkillInfo != `` {
-				details := strings.Split(questInfo.Rewards.SkillInfo, `:`)
+			if rewards.SkillInfo != `` {
+				details := strings.Split(rewards.SkillInfo, `:`)
 				if len(details) > 1 {
 					skillName := strings.ToLower(details[0])
 					skillLevel, _ := strconv.Atoi(details[1])
@@ -1695,19 +1843,21 @@ func (w *World) TurnTick() {
 						}
 						skillUpTxt, _ := templates.Process("character/skillup", skillData)
 						questUser.SendText(skillUpTxt)
+
+						scripting.TryPlayerHook(`onSkillGained`, questUser.UserId, scripting.HookDetails{`skill`: skillName, `level`: newLevel})
 					}
 
 				}
 			}
 			// Move them to another room/area?
-			if questInfo.Rewards.RoomId > 0 {
+			if rewards.RoomId > 0 {
 				questUser.SendText(`You are suddenly moved to a new place!`)
 
 				if room := rooms.LoadRoom(questUser.Character.RoomId); room != nil {
 					room.SendText(fmt.Sprintf(`<ansi fg="username">%s</ansi> is suddenly moved to a new place!`, questUser.Character.Name), questUser.UserId)
 				}
 
-				rooms.MoveToRoom(questUser.UserId, questInfo.Rewards.RoomId)
+				rooms.MoveToRoom(questUser.UserId, rewards.RoomId)
 			}
 		} else {
 			if !questInfo.Secret {
@@ -1721,6 +1871,39 @@ func (w *World) TurnTick() {
 
 	}
 
+	//
+	// Let zone and world scripts know when players change zones
+	//
+	eq = events.GetQueue(events.RoomChange{})
+	for eq.Len() > 0 {
+
+		e := eq.Poll().(events.Event)
+
+		roomChange, typeOk := e.(events.RoomChange)
+		if !typeOk {
+			slog.Error("Event", "Expected Type", "RoomChange", "Actual Type", e.Type())
+			continue
+		}
+
+		if roomChange.FromZone == roomChange.ToZone {
+			continue
+		}
+
+		sUser := scripting.GetUser(roomChange.UserId)
+		if sUser == nil {
+			continue
+		}
+
+		fromRoom := scripting.GetRoom(roomChange.FromRoomId)
+		toRoom := scripting.GetRoom(roomChange.ToRoomId)
+
+		scripting.TryZoneScriptEvent(`onPlayerLeaveZone`, roomChange.FromZone, sUser, fromRoom, roomChange.ToZone)
+		scripting.TryWorldScriptEvent(`onPlayerLeaveZone`, sUser, fromRoom, roomChange.ToZone)
+
+		scripting.TryZoneScriptEvent(`onPlayerEnterZone`, roomChange.ToZone, sUser, toRoom, roomChange.FromZone)
+		scripting.TryWorldScriptEvent(`onPlayerEnterZone`, sUser, toRoom, roomChange.FromZone)
+	}
+
 	//
 	// Prune all buffs that have expired.
 	//
@@ -1732,7 +1915,11 @@ func (w *World) TurnTick() {
 	//
 	for _, uId := range users.GetOnlineUserIds() {
 		if user := users.GetByUserId(uId); user != nil {
-			user.Character.ActionPoints += 1
+			if user.Character.HasBuffFlag(buffs.Resting) {
+				user.Character.ActionPoints += 3
+			} else if !user.Character.IsEncumbered() || turnCt%2 == 0 { // Encumbered characters recover at half speed
+				user.Character.ActionPoints += 1
+			}
 			if user.Character.ActionPoints > user.Character.ActionPointsMax.Value {
 				user.Character.ActionPoints = user.Character.ActionPointsMax.Value
 			}
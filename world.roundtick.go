@@ -14,18 +14,23 @@ import (
 	"github.com/volte6/gomud/internal/combat"
 	"github.com/volte6/gomud/internal/configs"
 	"github.com/volte6/gomud/internal/connections"
+	"github.com/volte6/gomud/internal/crashreports"
 	"github.com/volte6/gomud/internal/events"
 	"github.com/volte6/gomud/internal/exit"
 	"github.com/volte6/gomud/internal/gametime"
 	"github.com/volte6/gomud/internal/items"
 	"github.com/volte6/gomud/internal/mobs"
 	"github.com/volte6/gomud/internal/rooms"
+	"github.com/volte6/gomud/internal/schedules"
 	"github.com/volte6/gomud/internal/scripting"
 	"github.com/volte6/gomud/internal/spells"
 	"github.com/volte6/gomud/internal/templates"
 	"github.com/volte6/gomud/internal/term"
+	"github.com/volte6/gomud/internal/usercommands"
 	"github.com/volte6/gomud/internal/users"
 	"github.com/volte6/gomud/internal/util"
+	"github.com/volte6/gomud/internal/vehicles"
+	"github.com/volte6/gomud/internal/weather"
 )
 
 func (w *World) roundTick() {
@@ -48,6 +53,9 @@ func (w *World) roundTick() {
 	gdNow := gametime.GetDate()
 
 	if gdBefore.Night != gdNow.Night {
+
+		w.runRoundSubsystem(`onDayNightChange`, func() { triggerZoneScripts(`onDayNightChange`, gdNow.Night) })
+
 		if gdNow.Night {
 			sunsetTxt, _ := templates.Process("generic/sunset", nil)
 
@@ -64,15 +72,52 @@ func (w *World) roundTick() {
 		}
 	}
 
+	//
+	// Change the weather and announce it
+	//
+	w.runRoundSubsystem(`weather`, func() { w.handleWeather(gdBefore, gdNow) })
+
+	//
+	// Run any scheduled world events that are due
+	//
+	w.runRoundSubsystem(`schedules`, func() { schedules.RoundTick(gdBefore, gdNow) })
+
+	//
+	// Move vehicles along their routes
+	//
+	w.runRoundSubsystem(`vehicles`, func() { vehicles.RoundTick(roundNumber) })
+
+	//
+	// Zone and world scripts
+	//
+	w.runRoundSubsystem(`onRoundTick`, func() { triggerZoneScripts(`onRoundTick`, roundNumber) })
+
+	//
+	// Call any script timers that are due
+	//
+	w.runRoundSubsystem(`scriptTimers`, func() { scripting.ProcessTimers(roundNumber) })
+
+	//
+	// Pick up new or changed command scripts
+	//
+	w.runRoundSubsystem(`commandScripts`, func() { scripting.LoadCommandScripts() })
+
+	//
+	// Reload changed room, mob, item and zone scripts
+	//
+	if watchRounds := uint64(c.ScriptWatchRounds); watchRounds > 0 && roundNumber%watchRounds == 0 {
+		w.runRoundSubsystem(`scriptWatcher`, func() { scripting.ReloadChangedScripts() })
+	}
+
 	//
 	// Disconnect players that have been inactive too long
 	//
-	w.handleInactivePlayers(c.SecondsToRounds(int(c.MaxIdleSeconds)))
+	w.runRoundSubsystem(`handleInactivePlayers`, func() { w.handleInactivePlayers(c.SecondsToRounds(int(c.MaxIdleSeconds))) })
 
 	//
 	// Do auction maintenance
 	//
-	w.processAuction(tStart)
+	w.runRoundSubsystem(`processAuction`, func() { w.processAuction(tStart) })
 
 	if roundNumber%100 == 0 {
 		scripting.PruneVMs()
@@ -95,50 +140,95 @@ func (w *World) roundTick() {
 	//
 	// Reduce existing hostility (if any)
 	//
-	mobs.ReduceHostility()
+	w.runRoundSubsystem(`ReduceHostility`, mobs.ReduceHostility)
 
 	//
 	// Player round ticks
 	//
-	w.handlePlayerRoundTicks()
+	w.runRoundSubsystem(`handlePlayerRoundTicks`, w.handlePlayerRoundTicks)
+	//
+	// Move players that are travelling somewhere
+	//
+	w.runRoundSubsystem(`handleTravel`, func() {
+		for _, user := range users.GetAllActiveUsers() {
+			usercommands.ContinueTravel(user)
+		}
+	})
 	//
 	// Player round ticks
 	//
-	w.handleMobRoundTicks()
+	w.runRoundSubsystem(`handleMobRoundTicks`, w.handleMobRoundTicks)
 
 	//
 	// Respawn any enemies that have been missing for too long
 	//
-	w.handleRespawns()
+	w.runRoundSubsystem(`handleRespawns`, w.handleRespawns)
 
 	//
 	// Combat rounds
 	//
-	affectedPlayers1, affectedMobs1 := w.handlePlayerCombat()
+	var affectedPlayers1, affectedMobs1, affectedPlayers2, affectedMobs2 []int
 
-	affectedPlayers2, affectedMobs2 := w.handleMobCombat()
+	w.runRoundSubsystem(`handlePlayerCombat`, func() { affectedPlayers1, affectedMobs1 = w.handlePlayerCombat() })
+
+	w.runRoundSubsystem(`handleMobCombat`, func() { affectedPlayers2, affectedMobs2 = w.handleMobCombat() })
 
 	// Do any resolution or extra checks based on everyone that has been involved in combat this round.
-	w.handleAffected(append(affectedPlayers1, affectedPlayers2...), append(affectedMobs1, affectedMobs2...))
+	w.runRoundSubsystem(`handleAffected`, func() {
+		w.handleAffected(append(affectedPlayers1, affectedPlayers2...), append(affectedMobs1, affectedMobs2...))
+	})
 
 	//
 	// Healing
 	//
-	w.handleAutoHealing(roundNumber)
+	w.runRoundSubsystem(`handleAutoHealing`, func() { w.handleAutoHealing(roundNumber) })
 
 	//
 	// Idle mobs
 	//
-	w.handleIdleMobs()
+	w.runRoundSubsystem(`handleIdleMobs`, w.handleIdleMobs)
 
 	//
 	// Shadow/death realm
 	//
-	w.handleShadowRealm(roundNumber)
+	w.runRoundSubsystem(`handleShadowRealm`, func() { w.handleShadowRealm(roundNumber) })
 
 	util.TrackTime(`World::RoundTick()`, time.Since(tStart).Seconds())
 }
 
+// Runs a single round tick subsystem.
+// A panic is recovered and reported so that only this subsystem is skipped for the round.
+func (w *World) runRoundSubsystem(name string, subsystem func()) {
+	defer func() {
+		if r := recover(); r != nil {
+			crashreports.New(r, `roundTick:`+name).Save()
+		}
+	}()
+
+	subsystem()
+}
+
+// Calls a function in the world script and every zone script.
+// Zone scripts receive the root room of their zone as an extra final argument.
+func triggerZoneScripts(eventName string, args ...any) {
+
+	scripting.TryWorldScriptEvent(eventName, args...)
+
+	for _, zone := range rooms.GetAllZoneNames() {
+
+		if !scripting.HasZoneScript(zone) {
+			continue
+		}
+
+		zoneArgs := args
+		if rootRoomId, err := rooms.GetZoneRoot(zone); err == nil {
+			zoneArgs = append(append([]any{}, args...), scripting.GetRoom(rootRoomId))
+		}
+
+		scripting.TryZoneScriptEvent(eventName, zone, zoneArgs...)
+	}
+}
+
 func (w *World) handleInactivePlayers(maxIdleRounds int) {
 
 	if maxIdleRounds == 0 {
@@ -1510,6 +1600,12 @@ func (w *World) handleIdleMobs() {
 			continue
 		}
 
+		// Possessed mobs only do what they are told
+		if mob.PossessedBy > 0 {
+			mob.BoredomCounter = 0
+			continue
+		}
+
 		if mob.BoredomCounter >= maxBoredom {
 			if mob.Despawns() {
 				mob.Command(`despawn` + fmt.Sprintf(` depression %d/%d`, mob.BoredomCounter, maxBoredom))
@@ -1694,6 +1790,77 @@ func (w *World) handleShadowRealm(roundNumber uint64) {
 
 }
 
+// Rolls the weather of every zone once a game hour, then announces any changes
+// and applies the weather's buff to players that are outside.
+func (w *World) handleWeather(gdBefore gametime.GameDate, gdNow gametime.GameDate) {
+
+	newHour := gdBefore.Hour24 != gdNow.Hour24
+	if newHour {
+		season := gametime.SeasonName(gdNow.Month)
+		for _, zone := range rooms.GetAllZoneNames() {
+			weather.Roll(zone, rooms.GetZoneBiome(zone), season)
+		}
+	}
+
+	changedZones := map[string]struct{}{}
+
+	for _, change := range weather.TakeChanges() {
+
+		changedZones[change.Zone] = struct{}{}
+
+		startText := weather.GetInfo(change.To).StartText()
+
+		for _, roomId := range rooms.GetRoomsWithPlayers() {
+			if room := rooms.LoadRoom(roomId); room != nil && room.Zone == change.Zone && room.IsOutside() {
+				room.SendText(`<ansi fg="weather">` + startText + `</ansi>`)
+			}
+		}
+
+		if rootRoomId, err := rooms.GetZoneRoot(change.Zone); err == nil {
+			scripting.TryZoneScriptEvent(`onWeatherChange`, change.Zone, string(change.From), string(change.To), scripting.GetRoom(rootRoomId))
+		}
+
+		for _, roomId := range rooms.GetZoneRoomIds(change.Zone) {
+			if !rooms.IsRoomLoaded(roomId) {
+				continue
+			}
+			if room := rooms.LoadRoom(roomId); room != nil && room.IsOutside() {
+				scripting.TryRoomWeatherEvent(roomId, string(change.From), string(change.To))
+			}
+		}
+	}
+
+	if !newHour && len(changedZones) == 0 {
+		return
+	}
+
+	for _, roomId := range rooms.GetRoomsWithPlayers() {
+
+		room := rooms.LoadRoom(roomId)
+		if room == nil {
+			continue
+		}
+
+		if _, ok := changedZones[room.Zone]; !ok && !newHour {
+			continue
+		}
+
+		buffId := weather.GetInfo(room.GetWeather()).BuffId()
+		if buffId == 0 {
+			continue
+		}
+
+		for _, userId := range room.GetPlayers() {
+			if user := users.GetByUserId(userId); user != nil && !user.Character.HasBuff(buffId) {
+				events.AddToQueue(events.Buff{
+					UserId: userId,
+					BuffId: buffId,
+				})
+			}
+		}
+	}
+}
+
 // Handle dropped players
 func (w *World) HandleDroppedPlayers(droppedPlayers []int) {
 
@@ -1751,9 +1918,19 @@ func (w *World) CheckForLevelUps() {
 
 				user.SendText(levelUpStr)
 
-				events.AddToQueue(events.Broadcast{
-					Text: fmt.Sprintf(`<ansi fg="magenta-bold">***</ansi> <ansi fg="username">%s</ansi> <ansi fg="yellow">has leveled up to level %d!</ansi> <ansi fg="magenta-bold">***</ansi>%s`, user.Character.Name, user.Character.Level, term.CRLFStr),
-				})
+				// Scripts can quiet the announcement, but can't undo the level
+				hookDetails := scripting.HookDetails{
+					`level`:    user.Character.Level,
+					`livesUp`:  user.Character.ExtraLives - livesBefore,
+					`announce`: true,
+				}
+				scripting.TryPlayerHook(`onLevelUp`, user.UserId, hookDetails)
+
+				if hookDetails.Bool(`announce`) {
+					events.AddToQueue(events.Broadcast{
+						Text: fmt.Sprintf(`<ansi fg="magenta-bold">***</ansi> <ansi fg="username">%s</ansi> <ansi fg="yellow">has leveled up to level %d!</ansi> <ansi fg="magenta-bold">***</ansi>%s`, user.Character.Name, user.Character.Level, term.CRLFStr),
+					})
+				}
 
 				if user.Character.Level >= 5 {
 					for _, mobInstanceId := range user.Character.CharmedMobs {